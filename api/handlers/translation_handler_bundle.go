@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	api "github.com/henok321/translation-service/gen"
+	"github.com/henok321/translation-service/pkg/translation"
+)
+
+// bundleFormat is a representation of the translation list. The JSON array
+// of api.Translation has no writer, it carries the ids and metadata of the
+// translations the platform formats leave out.
+type bundleFormat struct {
+	contentType string
+	write       func(io.Writer, []translation.Translation, translation.Locale) error
+}
+
+var bundleFormats = map[api.GetTranslationsParamsFormat]bundleFormat{
+	api.GetTranslationsParamsFormatJson:        {contentType: "application/json"},
+	api.GetTranslationsParamsFormatFlat:        {contentType: "application/x-flat+json", write: withoutLocale(translation.WriteFlatJSON)},
+	api.GetTranslationsParamsFormatI18next:     {contentType: "application/x-i18next+json", write: withoutLocale(translation.WriteI18next)},
+	api.GetTranslationsParamsFormatArb:         {contentType: "application/x-arb+json", write: translation.WriteARB},
+	api.GetTranslationsParamsFormatProperties:  {contentType: "text/x-java-properties", write: withoutLocale(translation.WriteProperties)},
+	api.GetTranslationsParamsFormatAndroid:     {contentType: "application/x-android-strings+xml", write: withoutLocale(translation.WriteAndroidStrings)},
+	api.GetTranslationsParamsFormatIos:         {contentType: "text/x-ios-strings", write: withoutLocale(translation.WriteAppleStrings)},
+	api.GetTranslationsParamsFormatStringsdict: {contentType: "application/x-ios-stringsdict+xml", write: withoutLocale(translation.WriteStringsdict)},
+}
+
+// bundleFormatOrder lists the formats offered to the Accept header, JSON
+// first as clients accepting anything get it.
+var bundleFormatOrder = []api.GetTranslationsParamsFormat{
+	api.GetTranslationsParamsFormatJson,
+	api.GetTranslationsParamsFormatFlat,
+	api.GetTranslationsParamsFormatI18next,
+	api.GetTranslationsParamsFormatArb,
+	api.GetTranslationsParamsFormatProperties,
+	api.GetTranslationsParamsFormatAndroid,
+	api.GetTranslationsParamsFormatIos,
+	api.GetTranslationsParamsFormatStringsdict,
+}
+
+// negotiateBundleFormat picks the format of a translation list. An explicit
+// format wins, otherwise the Accept header is negotiated. Headers accepting
+// none of the formats are answered with JSON rather than 406.
+func negotiateBundleFormat(w http.ResponseWriter, r *http.Request, requested *api.GetTranslationsParamsFormat) (bundleFormat, bool) {
+	w.Header().Add("Vary", "Accept")
+
+	if requested != nil {
+		format, ok := bundleFormats[*requested]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		return format, ok
+	}
+
+	offered := make([]string, len(bundleFormatOrder))
+	for i, name := range bundleFormatOrder {
+		offered[i] = bundleFormats[name].contentType
+	}
+
+	contentType, _ := translation.NegotiateMediaType(r.Header.Get("Accept"), offered)
+	for _, name := range bundleFormatOrder {
+		if bundleFormats[name].contentType == contentType {
+			return bundleFormats[name], true
+		}
+	}
+	return bundleFormats[api.GetTranslationsParamsFormatJson], true
+}
+
+// withoutLocale adapts writers of formats that do not name the locale.
+func withoutLocale(write func(io.Writer, []translation.Translation) error) func(io.Writer, []translation.Translation, translation.Locale) error {
+	return func(w io.Writer, translations []translation.Translation, _ translation.Locale) error {
+		return write(w, translations)
+	}
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+
+	api "github.com/henok321/translation-service/gen"
+	"github.com/henok321/translation-service/pkg/translation"
+)
+
+const csvContentType = "text/csv"
+
+// GetCsv exports the translations of the namespace as CSV table.
+func (t TranslationRESTHandler) GetCsv(w http.ResponseWriter, _ *http.Request, params api.GetCsvParams) {
+	var locales []translation.Locale
+	if params.Locale != nil {
+		for _, code := range *params.Locale {
+			locales = append(locales, translation.Locale(code))
+		}
+	}
+
+	table, err := t.catalogs.ExportCSV(t.namespace, locales)
+	if err != nil {
+		writeCatalogError(w, err)
+		return
+	}
+
+	var body bytes.Buffer
+	if err := translation.WriteCSV(&body, table); err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	writeCatalog(w, csvContentType+"; charset=utf-8", t.namespace+".csv", body.Bytes())
+}
+
+// PostCsv imports a CSV table, or only reports its diff in a dry run.
+func (t TranslationRESTHandler) PostCsv(w http.ResponseWriter, r *http.Request, params api.PostCsvParams) {
+	table, err := translation.ParseCSV(http.MaxBytesReader(w, r.Body, maxCatalogSize))
+	if err != nil {
+		writeCatalogError(w, err)
+		return
+	}
+
+	dryRun := params.DryRun != nil && *params.DryRun
+	diff, err := t.catalogs.ImportCSV(t.namespace, table, stringValue(params.XAuthor), dryRun, stringValue(params.Fingerprint))
+	if err != nil {
+		writeCatalogError(w, err)
+		return
+	}
+
+	if !dryRun {
+		for _, locale := range table.Locales {
+			t.invalidate(locale)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, toAPITableDiff(diff))
+}
+
+func (t TranslationRESTHandler) GetNamespacesNamespaceCsv(w http.ResponseWriter, r *http.Request, namespace string, params api.GetNamespacesNamespaceCsvParams) {
+	t.inNamespace(namespace).GetCsv(w, r, api.GetCsvParams(params))
+}
+
+func (t TranslationRESTHandler) PostNamespacesNamespaceCsv(w http.ResponseWriter, r *http.Request, namespace string, params api.PostNamespacesNamespaceCsvParams) {
+	t.inNamespace(namespace).PostCsv(w, r, api.PostCsvParams(params))
+}
+
+func toAPITableDiff(diff *translation.TableDiff) api.TableDiff {
+	return api.TableDiff{
+		Added:       toAPITableChanges(diff.Added),
+		Changed:     toAPITableChanges(diff.Changed),
+		Removed:     toAPITableChanges(diff.Removed),
+		Unchanged:   toAPITableChanges(diff.Unchanged),
+		Fingerprint: diff.Fingerprint,
+	}
+}
+
+func toAPITableChanges(changes []translation.TableChange) []api.TableChange {
+	result := make([]api.TableChange, 0, len(changes))
+	for _, change := range changes {
+		apiChange := api.TableChange{Key: change.Key, Locale: change.Locale.String()}
+		if change.Current != "" {
+			apiChange.Current = &change.Current
+		}
+		if change.Imported != "" {
+			apiChange.Imported = &change.Imported
+		}
+		result = append(result, apiChange)
+	}
+	return result
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	api "github.com/henok321/translation-service/gen"
+	"github.com/henok321/translation-service/pkg/translation"
+)
+
+const (
+	poContentType  = "text/x-gettext-translation"
+	potContentType = "text/x-gettext-translation-template"
+	moContentType  = "application/x-gettext-translation"
+
+	// maxCatalogSize limits the size of uploaded catalogs.
+	maxCatalogSize = 32 << 20
+)
+
+// GetGettext exports the keys of the namespace as POT template.
+func (t TranslationRESTHandler) GetGettext(w http.ResponseWriter, _ *http.Request) {
+	if _, ok := t.namespaceSettings(w); !ok {
+		return
+	}
+
+	catalog, err := t.catalogs.ExportTemplate(t.namespace)
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	var body bytes.Buffer
+	if err := translation.WritePO(&body, catalog); err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	writeCatalog(w, potContentType, t.namespace+".pot", body.Bytes())
+}
+
+// GetGettextLocale exports the translations of a locale, without fallbacks, as
+// PO or MO catalog.
+func (t TranslationRESTHandler) GetGettextLocale(w http.ResponseWriter, _ *http.Request, code string, params api.GetGettextLocaleParams) {
+	namespace, ok := t.namespaceSettings(w)
+	if !ok {
+		return
+	}
+
+	format := api.GetGettextLocaleParamsFormatPo
+	if params.Format != nil {
+		format = *params.Format
+	}
+
+	write := translation.WritePO
+	contentType := poContentType
+	switch format {
+	case api.GetGettextLocaleParamsFormatPo:
+	case api.GetGettextLocaleParamsFormatMo:
+		write = translation.WriteMO
+		contentType = moContentType
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	locale, ok := t.parseLocale(w, namespace, code)
+	if !ok {
+		return
+	}
+
+	catalog, err := t.catalogs.ExportCatalog(t.namespace, locale)
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	var body bytes.Buffer
+	if err := write(&body, catalog); err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	writeCatalog(w, contentType, locale.String()+"."+string(format), body.Bytes())
+}
+
+// PostGettextLocale imports a PO or MO catalog, told apart by the magic number
+// of MO files rather than by the content type.
+func (t TranslationRESTHandler) PostGettextLocale(w http.ResponseWriter, r *http.Request, code string, params api.PostGettextLocaleParams) {
+	namespace, ok := t.namespaceSettings(w)
+	if !ok {
+		return
+	}
+
+	locale, ok := t.parseLocale(w, namespace, code)
+	if !ok {
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxCatalogSize))
+	if err != nil {
+		writeCatalogError(w, fmt.Errorf("%w: %w", translation.ErrInvalidCatalog, err))
+		return
+	}
+
+	catalog, err := translation.ParseCatalog(data)
+	if err != nil {
+		writeCatalogError(w, err)
+		return
+	}
+
+	report, err := t.catalogs.ImportCatalog(t.namespace, locale, catalog, stringValue(params.XAuthor))
+	if err != nil {
+		writeCatalogError(w, err)
+		return
+	}
+
+	t.invalidate(locale)
+
+	writeJSON(w, http.StatusOK, toAPIImportReport(report))
+}
+
+func (t TranslationRESTHandler) GetNamespacesNamespaceGettext(w http.ResponseWriter, r *http.Request, namespace string) {
+	t.inNamespace(namespace).GetGettext(w, r)
+}
+
+func (t TranslationRESTHandler) GetNamespacesNamespaceGettextLocale(w http.ResponseWriter, r *http.Request, namespace, locale string, params api.GetNamespacesNamespaceGettextLocaleParams) {
+	exportParams := api.GetGettextLocaleParams{}
+	if params.Format != nil {
+		format := api.GetGettextLocaleParamsFormat(*params.Format)
+		exportParams.Format = &format
+	}
+
+	t.inNamespace(namespace).GetGettextLocale(w, r, locale, exportParams)
+}
+
+func (t TranslationRESTHandler) PostNamespacesNamespaceGettextLocale(w http.ResponseWriter, r *http.Request, namespace, locale string, params api.PostNamespacesNamespaceGettextLocaleParams) {
+	t.inNamespace(namespace).PostGettextLocale(w, r, locale, api.PostGettextLocaleParams(params))
+}
+
+func toAPIImportReport(report *translation.ImportReport) api.ImportReport {
+	result := api.ImportReport{
+		Created:   append([]string{}, report.Created...),
+		Updated:   append([]string{}, report.Updated...),
+		Skipped:   make([]api.SkippedEntry, 0, len(report.Skipped)),
+		Conflicts: make([]api.ImportConflict, 0, len(report.Conflicts)),
+	}
+	for _, skipped := range report.Skipped {
+		result.Skipped = append(result.Skipped, api.SkippedEntry{Key: skipped.Key, Reason: skipped.Reason})
+	}
+	for _, conflict := range report.Conflicts {
+		result.Conflicts = append(result.Conflicts, api.ImportConflict{Key: conflict.Key, Current: conflict.Current, Imported: conflict.Imported})
+	}
+	return result
+}
+
+func writeCatalog(w http.ResponseWriter, contentType, filename string, body []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		slog.Error("failed to write response", "error", err)
+	}
+}
+
+// writeCatalogError reports why a catalog, XLIFF document or CSV table could
+// not be imported or exported.
+func writeCatalogError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+	if errors.Is(err, translation.ErrInvalidCatalog) || errors.Is(err, translation.ErrInvalidXLIFF) || errors.Is(err, translation.ErrInvalidCSV) {
+		writeJSON(w, http.StatusBadRequest, api.Error{Message: err.Error()})
+		return
+	}
+	if errors.Is(err, translation.ErrStaleDiff) {
+		writeJSON(w, http.StatusConflict, api.Error{Message: err.Error()})
+		return
+	}
+	writeRepositoryError(w, err)
+}
@@ -3,35 +3,72 @@ package handlers
 import (
 	"context"
 	"errors"
-	"fmt"
+	"time"
 
 	apiv1 "github.com/henok321/translation-service/gen/go/translation/v1"
 	"github.com/henok321/translation-service/pkg/translation"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"gorm.io/gorm"
 )
 
 type translationHandler struct {
 	apiv1.UnimplementedTranslationServiceServer
-	repo translation.Repository
+	repo       translation.Repository
+	locales    translation.LocaleRegistry
+	namespaces translation.NamespaceRegistry
+	namespace  string
+	releases   translation.ReleaseStore
+	revisions  translation.RevisionStore
+	drafts     translation.DraftStore
+	search     translation.SearchStore
+	feed       *translation.ChangeFeed
 }
 
-func NewTranslationGRPCHandler(db *gorm.DB) apiv1.TranslationServiceServer {
-	return &translationHandler{repo: translation.NewRepository(db)}
+func NewTranslationGRPCHandler(repo translation.Repository, locales translation.LocaleRegistry, namespaces translation.NamespaceRegistry, releases translation.ReleaseStore, revisions translation.RevisionStore, drafts translation.DraftStore, search translation.SearchStore, feed *translation.ChangeFeed) apiv1.TranslationServiceServer {
+	return &translationHandler{
+		repo:       repo,
+		locales:    locales,
+		namespaces: namespaces,
+		namespace:  translation.DefaultNamespace,
+		releases:   releases,
+		revisions:  revisions,
+		drafts:     drafts,
+		search:     search,
+		feed:       feed,
+	}
 }
 
 func (t translationHandler) GetTranslationByKeyAndLocale(_ context.Context, request *apiv1.GetTranslationByKeyAndLocaleRequest) (*apiv1.GetTranslationByKeyAndLocaleResponse, error) {
+	t = t.inNamespace(request.GetNamespace())
+
+	namespace, err := t.namespaceSettings()
+	if err != nil {
+		return nil, err
+	}
+
 	if request.GetLanguageKey() == "" {
 		return nil, status.Errorf(codes.InvalidArgument, "language key is required")
 	}
 
-	locale, err := mapToDBLocale(request.GetLocale())
+	locale, err := t.requestLocale(namespace, request.GetLocale())
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := t.fallbackChain(namespace, locale)
 	if err != nil {
-		return nil, status.Errorf(codes.InvalidArgument, "invalid locale: %v", err)
+		return nil, err
 	}
 
-	result, err := t.repo.GetTranslationByKey(request.GetLanguageKey(), locale)
+	repo, err := t.readRepository(request.GetRelease(), request.GetPreview())
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := repo.GetTranslationByKey(request.GetLanguageKey(), chain...)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, status.Errorf(codes.NotFound, "translation not found")
@@ -40,33 +77,1088 @@ func (t translationHandler) GetTranslationByKeyAndLocale(_ context.Context, requ
 	}
 
 	resp := &apiv1.GetTranslationByKeyAndLocaleResponse{
-		Translation: &apiv1.Translation{
-			LanguageKey: result.LanguageKey,
-			Translation: result.Translation,
-			Locale:      mapFromDBLocale(result.Locale), // returns apiv1.Locale
-		},
+		Translation: mapFromDBTranslation(result, locale),
+	}
+	if request.GetCount() != "" {
+		text, err := result.PluralForm(request.GetCount())
+		if err != nil {
+			return nil, repositoryErrorStatus("get translation", err)
+		}
+		resp.Translation.Translation = text
 	}
 	return resp, nil
 }
 
-func mapToDBLocale(apiv1Locale apiv1.Locale) (translation.Locale, error) {
-	switch apiv1Locale {
-	case apiv1.Locale_LOCALE_DE_DE:
-		return translation.LocaleDEDE, nil
-	case apiv1.Locale_LOCALE_EN_GB:
-		return translation.LocaleENGB, nil
+func (t translationHandler) ListTranslations(_ context.Context, request *apiv1.ListTranslationsRequest) (*apiv1.ListTranslationsResponse, error) {
+	t = t.inNamespace(request.GetNamespace())
+
+	namespace, err := t.namespaceSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	locale, err := t.requestLocale(namespace, request.GetLocale())
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := t.fallbackChain(namespace, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := t.readRepository(request.GetRelease(), request.GetPreview())
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := repo.ListTranslations(translation.ListOptions{
+		KeyPrefix: request.GetKeyPrefix(),
+		OrderBy:   request.GetOrderBy(),
+		PageSize:  int(request.GetPageSize()),
+		PageToken: request.GetPageToken(),
+	}, chain...)
+	if err != nil {
+		return nil, repositoryErrorStatus("list translations", err)
+	}
+
+	translations := &apiv1.TranslationList{}
+	for i := range page.Translations {
+		translations.Translations = append(translations.Translations, mapFromDBTranslation(&page.Translations[i], locale))
+	}
+
+	return &apiv1.ListTranslationsResponse{
+		Translations:  translations,
+		NextPageToken: page.NextPageToken,
+	}, nil
+}
+
+func (t translationHandler) SearchTranslations(_ context.Context, request *apiv1.SearchTranslationsRequest) (*apiv1.SearchTranslationsResponse, error) {
+	t = t.inNamespace(request.GetNamespace())
+
+	namespace, err := t.namespaceSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	options := translation.SearchOptions{
+		Query:     request.GetQuery(),
+		PageSize:  int(request.GetPageSize()),
+		PageToken: request.GetPageToken(),
+	}
+	if request.GetLocale() != "" {
+		locale, err := t.parseLocale(namespace, request.GetLocale())
+		if err != nil {
+			return nil, err
+		}
+		options.Locale = locale
+	}
+
+	page, err := t.search.SearchTranslations(t.namespace, options)
+	if err != nil {
+		return nil, repositoryErrorStatus("search translations", err)
+	}
+
+	resp := &apiv1.SearchTranslationsResponse{NextPageToken: page.NextPageToken}
+	for i := range page.Results {
+		resp.Results = append(resp.Results, mapFromDBSearchResult(&page.Results[i]))
+	}
+
+	return resp, nil
+}
+
+func (t translationHandler) GetGroupedTranslations(_ context.Context, request *apiv1.GetGroupedTranslationsRequest) (*apiv1.GetGroupedTranslationsResponse, error) {
+	t = t.inNamespace(request.GetNamespace())
+
+	namespace, err := t.namespaceSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(request.GetLocales()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "at least one locale is required")
+	}
+
+	repo, err := t.readRepository(request.GetRelease(), request.GetPreview())
+	if err != nil {
+		return nil, err
+	}
+
+	grouped := &apiv1.GroupedTranslations{}
+
+	for _, code := range request.GetLocales() {
+		locale, err := t.parseLocale(namespace, code)
+		if err != nil {
+			return nil, err
+		}
+
+		translations, err := t.listTranslations(repo, namespace, locale)
+		if err != nil {
+			return nil, err
+		}
+
+		grouped.Groups = append(grouped.Groups, &apiv1.LocaleGroup{
+			Locale:       locale.String(),
+			Translations: translations,
+		})
+	}
+
+	return &apiv1.GetGroupedTranslationsResponse{GroupedTranslations: grouped}, nil
+}
+
+func (t translationHandler) BatchGetTranslations(_ context.Context, request *apiv1.BatchGetTranslationsRequest) (*apiv1.BatchGetTranslationsResponse, error) {
+	t = t.inNamespace(request.GetNamespace())
+
+	namespace, err := t.namespaceSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	locale, err := t.requestLocale(namespace, request.GetLocale())
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := t.fallbackChain(namespace, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := t.readRepository(request.GetRelease(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := repo.GetTranslationsByKeys(request.GetLanguageKeys(), chain...)
+	if err != nil {
+		return nil, repositoryErrorStatus("get translations", err)
+	}
+
+	resp := &apiv1.BatchGetTranslationsResponse{
+		MissingKeys: translation.MissingKeys(request.GetLanguageKeys(), result),
+	}
+	for i := range result {
+		resp.Translations = append(resp.Translations, mapFromDBTranslation(&result[i], locale))
+	}
+	return resp, nil
+}
+
+func (t translationHandler) RenderTranslation(_ context.Context, request *apiv1.RenderTranslationRequest) (*apiv1.RenderTranslationResponse, error) {
+	t = t.inNamespace(request.GetNamespace())
+
+	namespace, err := t.namespaceSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	if request.GetLanguageKey() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "language key is required")
+	}
+
+	locale, err := t.requestLocale(namespace, request.GetLocale())
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := t.fallbackChain(namespace, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := t.readRepository(request.GetRelease(), request.GetPreview())
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := repo.GetTranslationByKey(request.GetLanguageKey(), chain...)
+	if err != nil {
+		return nil, repositoryErrorStatus("get translation", err)
+	}
+
+	args := map[string]any{}
+	for name, value := range request.GetArgs() {
+		args[name] = value.AsInterface()
+	}
+
+	var location *time.Location
+	if request.GetTimeZone() != "" {
+		location, err = translation.LoadTimeZone(request.GetTimeZone())
+		if err != nil {
+			return nil, repositoryErrorStatus("render translation", err)
+		}
+	}
+
+	text, err := translation.RenderMessage(result.Locale, result.Translation, args, location)
+	if err != nil {
+		return nil, repositoryErrorStatus("render translation", err)
+	}
+
+	return &apiv1.RenderTranslationResponse{
+		Text:           text,
+		Locale:         locale.String(),
+		ResolvedLocale: result.Locale.String(),
+	}, nil
+}
+
+func (t translationHandler) GetChanges(_ context.Context, request *apiv1.GetChangesRequest) (*apiv1.GetChangesResponse, error) {
+	t = t.inNamespace(request.GetNamespace())
+
+	namespace, err := t.namespaceSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	locale, err := t.parseLocale(namespace, request.GetLocale())
+	if err != nil {
+		return nil, err
+	}
+	if request.GetPageSize() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "negative page size")
+	}
+
+	if err := t.feed.ValidateVersion(request.GetSinceVersion()); err != nil {
+		return nil, repositoryErrorStatus("get changes", err)
+	}
+
+	chain, err := t.fallbackChain(namespace, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	delta, err := t.feed.Delta(request.GetSinceVersion(), int(request.GetPageSize()), chain...)
+	if err != nil {
+		return nil, repositoryErrorStatus("get changes", err)
+	}
+
+	resp := &apiv1.GetChangesResponse{
+		DeletedKeys: delta.DeletedKeys,
+		Version:     delta.Version,
+		HasMore:     delta.HasMore,
+	}
+	for i := range delta.Translations {
+		resp.Translations = append(resp.Translations, mapFromDBTranslation(&delta.Translations[i], locale))
+	}
+	return resp, nil
+}
+
+func (t translationHandler) CreateTranslation(ctx context.Context, request *apiv1.CreateTranslationRequest) (*apiv1.CreateTranslationResponse, error) {
+	t = t.inNamespace(request.GetNamespace())
+
+	namespace, err := t.namespaceSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	locale, err := t.parseLocale(namespace, request.GetLocale())
+	if err != nil {
+		return nil, err
+	}
+
+	entity := &translation.Translation{
+		LanguageKey: request.GetLanguageKey(),
+		Locale:      locale,
+		Translation: request.GetTranslation(),
+		PluralForms: mapToDBPluralForms(request.GetPluralForms()),
+	}
+
+	if err := t.repo.CreateTranslation(entity, authorFromContext(ctx)); err != nil {
+		return nil, repositoryErrorStatus("create translation", err)
+	}
+
+	return &apiv1.CreateTranslationResponse{Translation: mapFromDBTranslation(entity, entity.Locale)}, nil
+}
+
+func (t translationHandler) UpdateTranslation(ctx context.Context, request *apiv1.UpdateTranslationRequest) (*apiv1.UpdateTranslationResponse, error) {
+	t = t.inNamespace(request.GetNamespace())
+
+	namespace, err := t.namespaceSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	locale, err := t.parseLocale(namespace, request.GetLocale())
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := t.repo.UpdateTranslation(request.GetLanguageKey(), locale, request.GetTranslation(), mapToDBPluralForms(request.GetPluralForms()), authorFromContext(ctx))
+	if err != nil {
+		return nil, repositoryErrorStatus("update translation", err)
+	}
+
+	return &apiv1.UpdateTranslationResponse{Translation: mapFromDBTranslation(result, result.Locale)}, nil
+}
+
+func (t translationHandler) DeleteTranslation(ctx context.Context, request *apiv1.DeleteTranslationRequest) (*apiv1.DeleteTranslationResponse, error) {
+	t = t.inNamespace(request.GetNamespace())
+
+	namespace, err := t.namespaceSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	if request.GetLanguageKey() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "language key is required")
+	}
+
+	locale, err := t.parseLocale(namespace, request.GetLocale())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.repo.DeleteTranslation(request.GetLanguageKey(), locale, authorFromContext(ctx)); err != nil {
+		return nil, repositoryErrorStatus("delete translation", err)
+	}
+
+	return &apiv1.DeleteTranslationResponse{}, nil
+}
+
+func (t translationHandler) ListLocales(_ context.Context, request *apiv1.ListLocalesRequest) (*apiv1.ListLocalesResponse, error) {
+	result, err := t.locales.GetLocales(request.GetIncludeDisabled())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list locales: %v", err)
+	}
+
+	resp := &apiv1.ListLocalesResponse{}
+	for i := range result {
+		resp.Locales = append(resp.Locales, mapFromDBLocale(&result[i]))
+	}
+	return resp, nil
+}
+
+func (t translationHandler) AddLocale(_ context.Context, request *apiv1.AddLocaleRequest) (*apiv1.AddLocaleResponse, error) {
+	result, err := t.locales.AddLocale(request.GetCode(), request.GetFallback())
+	if err != nil {
+		return nil, repositoryErrorStatus("add locale", err)
+	}
+
+	return &apiv1.AddLocaleResponse{Locale: mapFromDBLocale(result)}, nil
+}
+
+func (t translationHandler) UpdateLocale(_ context.Context, request *apiv1.UpdateLocaleRequest) (*apiv1.UpdateLocaleResponse, error) {
+	result, err := t.locales.UpdateLocale(request.GetCode(), translation.LocaleUpdate{
+		Enabled:  request.Enabled,
+		Fallback: request.Fallback,
+	})
+	if err != nil {
+		return nil, repositoryErrorStatus("update locale", err)
+	}
+
+	return &apiv1.UpdateLocaleResponse{Locale: mapFromDBLocale(result)}, nil
+}
+
+// listTranslations loads the bundle of a locale including the keys served by
+// its fallback locales.
+func (t translationHandler) listTranslations(repo translation.Repository, namespace *translation.Namespace, locale translation.Locale) (*apiv1.TranslationList, error) {
+	chain, err := t.fallbackChain(namespace, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := repo.GetTranslations(chain...)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list translations: %v", err)
+	}
+
+	translations := &apiv1.TranslationList{}
+	for i := range result {
+		translations.Translations = append(translations.Translations, mapFromDBTranslation(&result[i], locale))
+	}
+	return translations, nil
+}
+
+// WatchTranslations sends the current translations of the locale unless the
+// client resumes from a version, then streams the changes logged after it.
+func (t translationHandler) WatchTranslations(request *apiv1.WatchTranslationsRequest, stream apiv1.TranslationService_WatchTranslationsServer) error {
+	t = t.inNamespace(request.GetNamespace())
+
+	namespace, err := t.namespaceSettings()
+	if err != nil {
+		return err
+	}
+
+	locale, err := t.parseLocale(namespace, request.GetLocale())
+	if err != nil {
+		return err
+	}
+
+	// Subscribe first, so no change between reading and waiting goes unnoticed.
+	subscription := t.feed.Subscribe(locale)
+	defer subscription.Close()
+
+	version := request.GetSinceVersion()
+	if version == 0 {
+		snapshot, snapshotVersion, err := t.feed.Snapshot(locale)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to get translations: %v", err)
+		}
+
+		translations := &apiv1.TranslationList{}
+		for i := range snapshot {
+			translations.Translations = append(translations.Translations, mapFromDBTranslation(&snapshot[i], locale))
+		}
+
+		version = snapshotVersion
+		if err := stream.Send(&apiv1.WatchTranslationsResponse{
+			Event:   &apiv1.WatchTranslationsResponse_Snapshot{Snapshot: translations},
+			Version: version,
+		}); err != nil {
+			return err
+		}
+	} else if err := t.feed.ValidateVersion(version); err != nil {
+		return repositoryErrorStatus("watch translations", err)
+	}
+
+	for {
+		changes, err := t.feed.ChangesSince(version, translation.MaxPageSize, locale)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to get changes: %v", err)
+		}
+
+		for i := range changes {
+			version = changes[i].Version
+			if err := stream.Send(&apiv1.WatchTranslationsResponse{
+				Event:   &apiv1.WatchTranslationsResponse_Change{Change: mapFromDBChange(&changes[i])},
+				Version: version,
+			}); err != nil {
+				return err
+			}
+		}
+
+		if len(changes) == translation.MaxPageSize {
+			continue
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-subscription.Done():
+			return status.Errorf(codes.Unavailable, "server is shutting down, resume from version %d", version)
+		case <-subscription.Changed():
+		}
+	}
+}
+
+func (t translationHandler) CreateRelease(_ context.Context, request *apiv1.CreateReleaseRequest) (*apiv1.CreateReleaseResponse, error) {
+	release, err := t.releases.CreateRelease(request.GetDescription())
+	if err != nil {
+		return nil, repositoryErrorStatus("create release", err)
+	}
+
+	return &apiv1.CreateReleaseResponse{Release: mapFromDBRelease(release)}, nil
+}
+
+func (t translationHandler) ListReleases(_ context.Context, _ *apiv1.ListReleasesRequest) (*apiv1.ListReleasesResponse, error) {
+	releases, err := t.releases.ListReleases()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list releases: %v", err)
+	}
+
+	resp := &apiv1.ListReleasesResponse{}
+	for i := range releases {
+		resp.Releases = append(resp.Releases, mapFromDBRelease(&releases[i]))
+	}
+	return resp, nil
+}
+
+func (t translationHandler) GetRelease(_ context.Context, request *apiv1.GetReleaseRequest) (*apiv1.GetReleaseResponse, error) {
+	release, err := t.releases.GetRelease(request.GetRelease())
+	if err != nil {
+		return nil, repositoryErrorStatus("get release", err)
+	}
+
+	return &apiv1.GetReleaseResponse{Release: mapFromDBRelease(release)}, nil
+}
+
+func (t translationHandler) RollbackRelease(_ context.Context, request *apiv1.RollbackReleaseRequest) (*apiv1.RollbackReleaseResponse, error) {
+	release, err := t.releases.RollbackRelease(request.GetRelease())
+	if err != nil {
+		return nil, repositoryErrorStatus("roll back release", err)
+	}
+
+	return &apiv1.RollbackReleaseResponse{Release: mapFromDBRelease(release)}, nil
+}
+
+func (t translationHandler) ListRevisions(_ context.Context, request *apiv1.ListRevisionsRequest) (*apiv1.ListRevisionsResponse, error) {
+	t = t.inNamespace(request.GetNamespace())
+
+	namespace, err := t.namespaceSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	locale, err := t.parseLocale(namespace, request.GetLocale())
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := t.revisions.ListRevisions(request.GetLanguageKey(), locale)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list revisions: %v", err)
+	}
+
+	resp := &apiv1.ListRevisionsResponse{}
+	for i := range revisions {
+		resp.Revisions = append(resp.Revisions, mapFromDBRevision(&revisions[i]))
+	}
+	return resp, nil
+}
+
+func (t translationHandler) RestoreRevision(ctx context.Context, request *apiv1.RestoreRevisionRequest) (*apiv1.RestoreRevisionResponse, error) {
+	t = t.inNamespace(request.GetNamespace())
+
+	namespace, err := t.namespaceSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := t.revisions.GetRevision(request.GetRevision())
+	if err != nil {
+		return nil, repositoryErrorStatus("get revision", err)
+	}
+
+	// The namespace may have disabled the locale since the revision was made.
+	if _, err := t.parseLocale(namespace, revision.Locale.String()); err != nil {
+		return nil, err
+	}
+
+	result, err := translation.RestoreRevision(t.repo, revision, request.GetLanguageKey(), authorFromContext(ctx))
+	if err != nil {
+		return nil, repositoryErrorStatus("restore revision", err)
+	}
+
+	return &apiv1.RestoreRevisionResponse{Translation: mapFromDBTranslation(result, result.Locale)}, nil
+}
+
+func (t translationHandler) ListDrafts(_ context.Context, request *apiv1.ListDraftsRequest) (*apiv1.ListDraftsResponse, error) {
+	t = t.inNamespace(request.GetNamespace())
+
+	if _, err := t.namespaceSettings(); err != nil {
+		return nil, err
+	}
+
+	drafts, err := t.drafts.ListDrafts(mapToDBStatus(request.GetStatus()))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list drafts: %v", err)
+	}
+
+	resp := &apiv1.ListDraftsResponse{}
+	for i := range drafts {
+		resp.Drafts = append(resp.Drafts, mapFromDBDraft(&drafts[i]))
+	}
+	return resp, nil
+}
+
+func (t translationHandler) GetDraft(_ context.Context, request *apiv1.GetDraftRequest) (*apiv1.GetDraftResponse, error) {
+	t = t.inNamespace(request.GetNamespace())
+
+	namespace, err := t.namespaceSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	locale, err := t.parseLocale(namespace, request.GetLocale())
+	if err != nil {
+		return nil, err
+	}
+
+	draft, err := t.drafts.GetDraft(request.GetLanguageKey(), locale)
+	if err != nil {
+		return nil, repositoryErrorStatus("get draft", err)
+	}
+
+	return &apiv1.GetDraftResponse{Draft: mapFromDBDraft(draft)}, nil
+}
+
+func (t translationHandler) SaveDraft(ctx context.Context, request *apiv1.SaveDraftRequest) (*apiv1.SaveDraftResponse, error) {
+	t = t.inNamespace(request.GetNamespace())
+
+	namespace, err := t.namespaceSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	locale, err := t.parseLocale(namespace, request.GetLocale())
+	if err != nil {
+		return nil, err
+	}
+
+	draft, err := t.drafts.SaveDraft(request.GetLanguageKey(), locale, request.GetTranslation(), mapToDBPluralForms(request.GetPluralForms()), authorFromContext(ctx))
+	if err != nil {
+		return nil, repositoryErrorStatus("save draft", err)
+	}
+
+	return &apiv1.SaveDraftResponse{Draft: mapFromDBDraft(draft)}, nil
+}
+
+func (t translationHandler) DiscardDraft(_ context.Context, request *apiv1.DiscardDraftRequest) (*apiv1.DiscardDraftResponse, error) {
+	t = t.inNamespace(request.GetNamespace())
+
+	namespace, err := t.namespaceSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	locale, err := t.parseLocale(namespace, request.GetLocale())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.drafts.DiscardDraft(request.GetLanguageKey(), locale); err != nil {
+		return nil, repositoryErrorStatus("discard draft", err)
+	}
+
+	return &apiv1.DiscardDraftResponse{}, nil
+}
+
+func (t translationHandler) SubmitDraft(_ context.Context, request *apiv1.SubmitDraftRequest) (*apiv1.SubmitDraftResponse, error) {
+	t = t.inNamespace(request.GetNamespace())
+
+	namespace, err := t.namespaceSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	locale, err := t.parseLocale(namespace, request.GetLocale())
+	if err != nil {
+		return nil, err
+	}
+
+	draft, err := t.drafts.Submit(request.GetLanguageKey(), locale)
+	if err != nil {
+		return nil, repositoryErrorStatus("submit draft", err)
+	}
+
+	return &apiv1.SubmitDraftResponse{Draft: mapFromDBDraft(draft)}, nil
+}
+
+func (t translationHandler) ApproveDraft(ctx context.Context, request *apiv1.ApproveDraftRequest) (*apiv1.ApproveDraftResponse, error) {
+	t = t.inNamespace(request.GetNamespace())
+
+	namespace, err := t.namespaceSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	locale, err := t.parseLocale(namespace, request.GetLocale())
+	if err != nil {
+		return nil, err
+	}
+
+	draft, err := t.drafts.Approve(request.GetLanguageKey(), locale, authorFromContext(ctx), request.GetComment())
+	if err != nil {
+		return nil, repositoryErrorStatus("approve draft", err)
+	}
+
+	return &apiv1.ApproveDraftResponse{Draft: mapFromDBDraft(draft)}, nil
+}
+
+func (t translationHandler) RejectDraft(ctx context.Context, request *apiv1.RejectDraftRequest) (*apiv1.RejectDraftResponse, error) {
+	t = t.inNamespace(request.GetNamespace())
+
+	namespace, err := t.namespaceSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	locale, err := t.parseLocale(namespace, request.GetLocale())
+	if err != nil {
+		return nil, err
+	}
+
+	draft, err := t.drafts.Reject(request.GetLanguageKey(), locale, authorFromContext(ctx), request.GetComment())
+	if err != nil {
+		return nil, repositoryErrorStatus("reject draft", err)
+	}
+
+	return &apiv1.RejectDraftResponse{Draft: mapFromDBDraft(draft)}, nil
+}
+
+func (t translationHandler) PublishDraft(ctx context.Context, request *apiv1.PublishDraftRequest) (*apiv1.PublishDraftResponse, error) {
+	t = t.inNamespace(request.GetNamespace())
+
+	namespace, err := t.namespaceSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	locale, err := t.parseLocale(namespace, request.GetLocale())
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := t.drafts.Publish(request.GetLanguageKey(), locale, authorFromContext(ctx))
+	if err != nil {
+		return nil, repositoryErrorStatus("publish draft", err)
+	}
+	t.invalidate(locale)
+
+	return &apiv1.PublishDraftResponse{Translation: mapFromDBTranslation(result, result.Locale)}, nil
+}
+
+func (t translationHandler) ListNamespaces(_ context.Context, _ *apiv1.ListNamespacesRequest) (*apiv1.ListNamespacesResponse, error) {
+	result, err := t.namespaces.GetNamespaces()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list namespaces: %v", err)
+	}
+
+	resp := &apiv1.ListNamespacesResponse{}
+	for i := range result {
+		resp.Namespaces = append(resp.Namespaces, mapFromDBNamespace(&result[i]))
+	}
+	return resp, nil
+}
+
+func (t translationHandler) GetNamespace(_ context.Context, request *apiv1.GetNamespaceRequest) (*apiv1.GetNamespaceResponse, error) {
+	result, err := t.namespaces.GetNamespace(request.GetName())
+	if err != nil {
+		return nil, repositoryErrorStatus("get namespace", err)
+	}
+
+	return &apiv1.GetNamespaceResponse{Namespace: mapFromDBNamespace(result)}, nil
+}
+
+func (t translationHandler) CreateNamespace(_ context.Context, request *apiv1.CreateNamespaceRequest) (*apiv1.CreateNamespaceResponse, error) {
+	settings := translation.NamespaceUpdate{
+		Description: &request.Description,
+		Locales:     &request.Locales,
+	}
+	if request.GetDefaultLocale() != "" {
+		settings.DefaultLocale = &request.DefaultLocale
+	}
+
+	result, err := t.namespaces.CreateNamespace(request.GetName(), settings)
+	if err != nil {
+		return nil, repositoryErrorStatus("create namespace", err)
+	}
+
+	return &apiv1.CreateNamespaceResponse{Namespace: mapFromDBNamespace(result)}, nil
+}
+
+func (t translationHandler) UpdateNamespace(_ context.Context, request *apiv1.UpdateNamespaceRequest) (*apiv1.UpdateNamespaceResponse, error) {
+	update := translation.NamespaceUpdate{
+		Description:   request.Description,
+		DefaultLocale: request.DefaultLocale,
+	}
+	if request.GetLocales() != nil {
+		update.Locales = &request.Locales.Locales
+	}
+
+	result, err := t.namespaces.UpdateNamespace(request.GetName(), update)
+	if err != nil {
+		return nil, repositoryErrorStatus("update namespace", err)
+	}
+
+	return &apiv1.UpdateNamespaceResponse{Namespace: mapFromDBNamespace(result)}, nil
+}
+
+func (t translationHandler) DeleteNamespace(_ context.Context, request *apiv1.DeleteNamespaceRequest) (*apiv1.DeleteNamespaceResponse, error) {
+	if err := t.namespaces.DeleteNamespace(request.GetName()); err != nil {
+		return nil, repositoryErrorStatus("delete namespace", err)
+	}
+
+	return &apiv1.DeleteNamespaceResponse{}, nil
+}
+
+// authorFromContext returns the author passed in the x-author metadata.
+func authorFromContext(ctx context.Context) string {
+	values := metadata.ValueFromIncomingContext(ctx, "x-author")
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// readRepository returns the repository of the requested release or preview,
+// or the live translations if neither is requested.
+func (t translationHandler) readRepository(release string, preview bool) (translation.Repository, error) {
+	if preview {
+		if release != "" {
+			return nil, status.Errorf(codes.InvalidArgument, "preview cannot be combined with a release")
+		}
+		return t.drafts.PreviewRepository(), nil
+	}
+	if release == "" {
+		return t.repo, nil
+	}
+
+	repo, err := t.releases.ReleaseRepository(release)
+	if err != nil {
+		return nil, repositoryErrorStatus("get release", err)
+	}
+	return repo.InNamespace(t.namespace), nil
+}
+
+// inNamespace returns a copy of the handler serving the translations of the
+// namespace, an empty name keeps the default namespace.
+func (t translationHandler) inNamespace(namespace string) translationHandler {
+	if namespace == "" {
+		return t
+	}
+	t.namespace = namespace
+	t.repo = t.repo.InNamespace(namespace)
+	t.revisions = t.revisions.InNamespace(namespace)
+	t.drafts = t.drafts.InNamespace(namespace)
+	t.feed = t.feed.InNamespace(namespace)
+	return t
+}
+
+// invalidate drops the cached translations of locale after a write that
+// bypasses the repository, such as a published draft.
+func (t translationHandler) invalidate(locale translation.Locale) {
+	if handler, ok := t.repo.(translation.ChangeHandler); ok {
+		handler.TranslationChanged(translation.Change{Namespace: t.namespace, Locale: locale})
+	}
+}
+
+func (t translationHandler) namespaceSettings() (*translation.Namespace, error) {
+	namespace, err := t.namespaces.GetNamespace(t.namespace)
+	if err != nil {
+		return nil, repositoryErrorStatus("get namespace", err)
+	}
+	return namespace, nil
+}
+
+// requestLocale resolves the locale of a read request, an empty code selects
+// the default locale of the namespace.
+func (t translationHandler) requestLocale(namespace *translation.Namespace, code string) (translation.Locale, error) {
+	if code == "" {
+		code = namespace.EffectiveDefaultLocale().String()
+	}
+	return t.parseLocale(namespace, code)
+}
+
+func (t translationHandler) parseLocale(namespace *translation.Namespace, code string) (translation.Locale, error) {
+	locale, err := t.locales.ParseLocale(code)
+	if err != nil {
+		if errors.Is(err, translation.ErrUnsupportedLocale) {
+			return "", status.Errorf(codes.InvalidArgument, "invalid locale: %v", err)
+		}
+		return "", status.Errorf(codes.Internal, "failed to resolve locale: %v", err)
+	}
+
+	if !namespace.Enables(locale) {
+		return "", status.Errorf(codes.InvalidArgument, "invalid locale: %q is not enabled in namespace %q", locale, namespace.Name)
+	}
+
+	return locale, nil
+}
+
+// fallbackChain returns the fallback chain of locale restricted to the locales
+// enabled in the namespace.
+func (t translationHandler) fallbackChain(namespace *translation.Namespace, locale translation.Locale) ([]translation.Locale, error) {
+	chain, err := t.locales.FallbackChain(locale)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to resolve fallback locales: %v", err)
+	}
+
+	return namespace.FilterLocales(chain), nil
+}
+
+func repositoryErrorStatus(operation string, err error) error {
+	switch {
+	case errors.Is(err, translation.ErrInvalidTranslation),
+		errors.Is(err, translation.ErrUnsupportedLocale),
+		errors.Is(err, translation.ErrInvalidLocale),
+		errors.Is(err, translation.ErrInvalidListOptions),
+		errors.Is(err, translation.ErrUnknownVersion),
+		errors.Is(err, translation.ErrInvalidRelease),
+		errors.Is(err, translation.ErrInvalidRevision),
+		errors.Is(err, translation.ErrInvalidNamespace),
+		errors.Is(err, translation.ErrInvalidArgument),
+		errors.Is(err, translation.ErrInvalidCount),
+		errors.Is(err, translation.ErrInvalidSearch):
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	case errors.Is(err, translation.ErrReleaseImmutable),
+		errors.Is(err, translation.ErrInvalidMessage),
+		errors.Is(err, translation.ErrInvalidTransition),
+		errors.Is(err, translation.ErrPreviewReadOnly),
+		errors.Is(err, translation.ErrNamespaceNotEmpty):
+		return status.Errorf(codes.FailedPrecondition, "%v", err)
+	case errors.Is(err, translation.ErrTranslationExists),
+		errors.Is(err, translation.ErrLocaleExists),
+		errors.Is(err, translation.ErrNamespaceExists):
+		return status.Errorf(codes.AlreadyExists, "%v", err)
+	case errors.Is(err, translation.ErrReviewRequired):
+		return status.Errorf(codes.PermissionDenied, "%v", err)
+	case errors.Is(err, translation.ErrUnknownNamespace):
+		return status.Errorf(codes.NotFound, "%v", err)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return status.Errorf(codes.NotFound, "failed to %s: not found", operation)
 	default:
-		return "", fmt.Errorf("unsupported locale: %v", apiv1Locale)
+		return status.Errorf(codes.Internal, "failed to %s: %v", operation, err)
+	}
+}
+
+// mapFromDBTranslation maps a translation served for the requested locale,
+// the entity locale may differ if it was resolved through a fallback.
+func mapFromDBTranslation(entity *translation.Translation, requested translation.Locale) *apiv1.Translation {
+	return &apiv1.Translation{
+		LanguageKey:    entity.LanguageKey,
+		Translation:    entity.Translation,
+		Locale:         requested.String(),
+		ResolvedLocale: entity.Locale.String(),
+		Status:         mapFromDBStatus(entity.CurrentStatus()),
+		PluralForms:    mapFromDBPluralForms(entity.PluralForms),
+		Comment:        entity.Comment,
+		Fuzzy:          entity.Fuzzy,
+	}
+}
+
+func mapFromDBSearchResult(entity *translation.SearchResult) *apiv1.SearchResult {
+	return &apiv1.SearchResult{
+		LanguageKey: entity.LanguageKey,
+		Locale:      entity.Locale.String(),
+		Translation: entity.Translation,
+		PluralForms: mapFromDBPluralForms(entity.PluralForms),
+		Snippet:     entity.Snippet,
+		Score:       entity.Score,
+	}
+}
+
+func mapFromDBPluralForms(forms translation.PluralForms) map[string]string {
+	if len(forms) == 0 {
+		return nil
+	}
+
+	result := make(map[string]string, len(forms))
+	for category, text := range forms {
+		result[string(category)] = text
+	}
+	return result
+}
+
+func mapToDBPluralForms(forms map[string]string) translation.PluralForms {
+	if len(forms) == 0 {
+		return nil
+	}
+
+	result := make(translation.PluralForms, len(forms))
+	for category, text := range forms {
+		result[translation.PluralCategory(category)] = text
+	}
+	return result
+}
+
+func mapFromDBLocale(entity *translation.LocaleDefinition) *apiv1.Locale {
+	result := &apiv1.Locale{
+		Code:    entity.Code.String(),
+		Enabled: entity.Enabled,
+	}
+	if entity.Fallback != nil {
+		result.Fallback = entity.Fallback.String()
+	}
+	return result
+}
+
+func mapFromDBChange(entity *translation.TranslationChange) *apiv1.TranslationChange {
+	change := &apiv1.TranslationChange{
+		Version: entity.Version,
+		Type:    mapFromDBOperation(entity.Operation),
+		Translation: &apiv1.Translation{
+			LanguageKey:    entity.LanguageKey,
+			Locale:         entity.Locale.String(),
+			ResolvedLocale: entity.Locale.String(),
+		},
+	}
+
+	if entity.Translation != nil {
+		change.Translation.Translation = *entity.Translation
+	}
+
+	return change
+}
+
+func mapFromDBNamespace(entity *translation.Namespace) *apiv1.Namespace {
+	result := &apiv1.Namespace{
+		Name:        entity.Name,
+		Description: entity.Description,
+		CreatedAt:   timestamppb.New(entity.CreatedAt),
+		UpdatedAt:   timestamppb.New(entity.UpdatedAt),
+	}
+	if entity.DefaultLocale != nil {
+		result.DefaultLocale = entity.DefaultLocale.String()
+	}
+	for _, locale := range entity.Locales {
+		result.Locales = append(result.Locales, locale.String())
+	}
+	return result
+}
+
+func mapFromDBRelease(entity *translation.Release) *apiv1.Release {
+	return &apiv1.Release{
+		Id:          int32(entity.ID),
+		Description: entity.Description,
+		CreatedAt:   timestamppb.New(entity.CreatedAt),
+		Latest:      entity.Latest,
 	}
 }
 
-func mapFromDBLocale(locale translation.Locale) apiv1.Locale {
-	switch locale {
-	case translation.LocaleDEDE:
-		return apiv1.Locale_LOCALE_DE_DE
-	case translation.LocaleENGB:
-		return apiv1.Locale_LOCALE_EN_GB
+func mapFromDBRevision(entity *translation.Revision) *apiv1.Revision {
+	return &apiv1.Revision{
+		Id:          entity.ID,
+		LanguageKey: entity.LanguageKey,
+		Locale:      entity.Locale.String(),
+		Type:        mapFromDBOperation(entity.Operation),
+		Translation: stringValue(entity.Translation),
+		ChangedBy:   stringValue(entity.ChangedBy),
+		ChangedAt:   timestamppb.New(entity.ChangedAt),
+		PluralForms: mapFromDBPluralForms(entity.PluralForms),
+	}
+}
+
+func mapFromDBOperation(operation string) apiv1.ChangeType {
+	switch operation {
+	case translation.OperationInsert:
+		return apiv1.ChangeType_CHANGE_TYPE_CREATED
+	case translation.OperationUpdate:
+		return apiv1.ChangeType_CHANGE_TYPE_UPDATED
+	case translation.OperationDelete:
+		return apiv1.ChangeType_CHANGE_TYPE_DELETED
 	default:
-		return apiv1.Locale_LOCALE_UNSPECIFIED
+		return apiv1.ChangeType_CHANGE_TYPE_UNSPECIFIED
+	}
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func mapFromDBDraft(entity *translation.Draft) *apiv1.Draft {
+	return &apiv1.Draft{
+		LanguageKey: entity.LanguageKey,
+		Locale:      entity.Locale.String(),
+		Translation: entity.Translation,
+		Status:      mapFromDBStatus(entity.Status),
+		Comment:     stringValue(entity.Comment),
+		ChangedBy:   stringValue(entity.ChangedBy),
+		ReviewedBy:  stringValue(entity.ReviewedBy),
+		CreatedAt:   timestamppb.New(entity.CreatedAt),
+		UpdatedAt:   timestamppb.New(entity.UpdatedAt),
+		PluralForms: mapFromDBPluralForms(entity.PluralForms),
+	}
+}
+
+var dbStatuses = map[translation.Status]apiv1.TranslationStatus{
+	translation.StatusDraft:     apiv1.TranslationStatus_TRANSLATION_STATUS_DRAFT,
+	translation.StatusInReview:  apiv1.TranslationStatus_TRANSLATION_STATUS_IN_REVIEW,
+	translation.StatusApproved:  apiv1.TranslationStatus_TRANSLATION_STATUS_APPROVED,
+	translation.StatusPublished: apiv1.TranslationStatus_TRANSLATION_STATUS_PUBLISHED,
+}
+
+func mapFromDBStatus(value translation.Status) apiv1.TranslationStatus {
+	return dbStatuses[value]
+}
+
+// mapToDBStatus returns an empty status for TRANSLATION_STATUS_UNSPECIFIED.
+func mapToDBStatus(value apiv1.TranslationStatus) translation.Status {
+	for dbStatus, apiStatus := range dbStatuses {
+		if apiStatus == value {
+			return dbStatus
+		}
 	}
+	return ""
 }
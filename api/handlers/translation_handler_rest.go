@@ -1,96 +1,1124 @@
 package handlers
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"log/slog"
 	"net/http"
+	"strings"
+	"time"
 
 	api "github.com/henok321/translation-service/gen"
 	"github.com/henok321/translation-service/pkg/translation"
 	"gorm.io/gorm"
 )
 
-func NewTranslationRESTHandler(db *gorm.DB) api.ServerInterface {
-	return &TranslationRESTHandler{repo: translation.NewRepository(db)}
+// defaultCacheControl lets caches store bundles but revalidate them with the
+// ETag on every use.
+const defaultCacheControl = "no-cache"
+
+// immutableCacheControl is sent with bundles of a release pinned by number.
+const immutableCacheControl = "public, max-age=31536000, immutable"
+
+// previewCacheControl keeps drafts out of shared caches.
+const previewCacheControl = "private, no-cache"
+
+// RESTConfig holds the settings of the REST API.
+type RESTConfig struct {
+	// CacheControl is sent with translation bundles, defaults to "no-cache".
+	CacheControl string
+}
+
+func NewTranslationRESTHandler(repo translation.Repository, locales translation.LocaleRegistry, namespaces translation.NamespaceRegistry, releases translation.ReleaseStore, revisions translation.RevisionStore, drafts translation.DraftStore, catalogs translation.CatalogStore, search translation.SearchStore, feed *translation.ChangeFeed, config RESTConfig) api.ServerInterface {
+	cacheControl := config.CacheControl
+	if cacheControl == "" {
+		cacheControl = defaultCacheControl
+	}
+
+	return &TranslationRESTHandler{
+		repo:         repo,
+		locales:      locales,
+		namespaces:   namespaces,
+		namespace:    translation.DefaultNamespace,
+		releases:     releases,
+		revisions:    revisions,
+		drafts:       drafts,
+		catalogs:     catalogs,
+		search:       search,
+		feed:         feed,
+		cacheControl: cacheControl,
+	}
+}
+
+type TranslationRESTHandler struct {
+	repo         translation.Repository
+	locales      translation.LocaleRegistry
+	namespaces   translation.NamespaceRegistry
+	namespace    string
+	releases     translation.ReleaseStore
+	revisions    translation.RevisionStore
+	drafts       translation.DraftStore
+	catalogs     translation.CatalogStore
+	search       translation.SearchStore
+	feed         *translation.ChangeFeed
+	cacheControl string
+}
+
+func (t TranslationRESTHandler) GetTranslationKey(w http.ResponseWriter, _ *http.Request, key string, params api.GetTranslationKeyParams) {
+	namespace, ok := t.namespaceSettings(w)
+	if !ok {
+		return
+	}
+
+	locale, ok := t.requestLocale(w, namespace, params.Locale, params.AcceptLanguage)
+	if !ok {
+		return
+	}
+
+	chain, ok := t.fallbackChain(w, namespace, locale)
+	if !ok {
+		return
+	}
+
+	repo, ok := t.readRepository(w, params.Release, params.Preview)
+	if !ok {
+		return
+	}
+
+	translationEntity, err := repo.GetTranslationByKey(key, chain...)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	response := toAPITranslation(translationEntity, locale)
+	if params.Count != nil {
+		text, err := translationEntity.PluralForm(*params.Count)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		response.Translation = &text
+	}
+
+	w.Header().Set("Content-Language", translationEntity.Locale.LanguageTag())
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (t TranslationRESTHandler) GetTranslations(w http.ResponseWriter, r *http.Request, params api.GetTranslationsParams) {
+	namespace, ok := t.namespaceSettings(w)
+	if !ok {
+		return
+	}
+
+	format, ok := negotiateBundleFormat(w, r, params.Format)
+	if !ok {
+		return
+	}
+
+	locale, ok := t.requestLocale(w, namespace, params.Locale, params.AcceptLanguage)
+	if !ok {
+		return
+	}
+
+	chain, ok := t.fallbackChain(w, namespace, locale)
+	if !ok {
+		return
+	}
+
+	options := translation.ListOptions{}
+	if params.PageSize != nil {
+		if *params.PageSize < 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		options.PageSize = *params.PageSize
+	}
+	if params.PageToken != nil {
+		options.PageToken = *params.PageToken
+	}
+	if params.OrderBy != nil {
+		options.OrderBy = string(*params.OrderBy)
+	}
+	if params.KeyPrefix != nil {
+		options.KeyPrefix = *params.KeyPrefix
+	}
+
+	repo, ok := t.readRepository(w, params.Release, params.Preview)
+	if !ok {
+		return
+	}
+
+	page, err := repo.ListTranslations(options, chain...)
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	var lastModified time.Time
+	for i := range page.Translations {
+		if page.Translations[i].UpdatedAt.After(lastModified) {
+			lastModified = page.Translations[i].UpdatedAt
+		}
+	}
+
+	if page.NextPageToken != "" {
+		w.Header().Set("X-Next-Page-Token", page.NextPageToken)
+	}
+	w.Header().Set("Content-Language", locale.LanguageTag())
+	cacheControl := t.bundleCacheControl(params.Release, params.Preview)
+
+	if format.write != nil {
+		var body bytes.Buffer
+		if err := format.write(&body, page.Translations, locale); err != nil {
+			writeRepositoryError(w, err)
+			return
+		}
+		writeBundleBody(w, params.IfNoneMatch, params.IfModifiedSince, lastModified, cacheControl, format.contentType, body.Bytes())
+		return
+	}
+
+	response := []api.Translation{}
+	for i := range page.Translations {
+		response = append(response, toAPITranslation(&page.Translations[i], locale))
+	}
+	t.writeBundle(w, params.IfNoneMatch, params.IfModifiedSince, lastModified, cacheControl, response)
+}
+
+func (t TranslationRESTHandler) PostTranslationsBatchGet(w http.ResponseWriter, r *http.Request, params api.PostTranslationsBatchGetParams) {
+	namespace, ok := t.namespaceSettings(w)
+	if !ok {
+		return
+	}
+
+	var body api.PostTranslationsBatchGetJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	locale, ok := t.requestLocale(w, namespace, params.Locale, params.AcceptLanguage)
+	if !ok {
+		return
+	}
+
+	chain, ok := t.fallbackChain(w, namespace, locale)
+	if !ok {
+		return
+	}
+
+	repo, ok := t.readRepository(w, params.Release, nil)
+	if !ok {
+		return
+	}
+
+	translationEntities, err := repo.GetTranslationsByKeys(body.Keys, chain...)
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	response := api.BatchGetResult{
+		Translations: []api.Translation{},
+		MissingKeys:  translation.MissingKeys(body.Keys, translationEntities),
+	}
+
+	for i := range translationEntities {
+		response.Translations = append(response.Translations, toAPITranslation(&translationEntities[i], locale))
+	}
+
+	w.Header().Set("Content-Language", locale.LanguageTag())
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (t TranslationRESTHandler) GetTranslationsChanges(w http.ResponseWriter, _ *http.Request, params api.GetTranslationsChangesParams) {
+	namespace, ok := t.namespaceSettings(w)
+	if !ok {
+		return
+	}
+
+	locale, ok := t.requestLocale(w, namespace, params.Locale, params.AcceptLanguage)
+	if !ok {
+		return
+	}
+
+	var since int64
+	if params.Since != nil {
+		since = *params.Since
+	}
+	pageSize := translation.MaxPageSize
+	if params.PageSize != nil {
+		if *params.PageSize < 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		pageSize = *params.PageSize
+	}
+
+	if err := t.feed.ValidateVersion(since); err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	chain, ok := t.fallbackChain(w, namespace, locale)
+	if !ok {
+		return
+	}
+
+	delta, err := t.feed.Delta(since, pageSize, chain...)
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	response := api.TranslationDelta{
+		Translations: []api.Translation{},
+		DeletedKeys:  []string{},
+		Version:      delta.Version,
+		HasMore:      delta.HasMore,
+	}
+	for i := range delta.Translations {
+		response.Translations = append(response.Translations, toAPITranslation(&delta.Translations[i], locale))
+	}
+	response.DeletedKeys = append(response.DeletedKeys, delta.DeletedKeys...)
+
+	w.Header().Set("Content-Language", locale.LanguageTag())
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (t TranslationRESTHandler) GetNamespacesNamespaceTranslationsChanges(w http.ResponseWriter, r *http.Request, namespace string, params api.GetNamespacesNamespaceTranslationsChangesParams) {
+	t.inNamespace(namespace).GetTranslationsChanges(w, r, api.GetTranslationsChangesParams(params))
+}
+
+func (t TranslationRESTHandler) PostTranslation(w http.ResponseWriter, r *http.Request, params api.PostTranslationParams) {
+	namespace, ok := t.namespaceSettings(w)
+	if !ok {
+		return
+	}
+
+	var body api.PostTranslationJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	locale, ok := t.parseLocale(w, namespace, body.Locale)
+	if !ok {
+		return
+	}
+
+	entity := &translation.Translation{
+		LanguageKey: body.LanguageKey,
+		Locale:      locale,
+		Translation: body.Translation,
+		PluralForms: toDBPluralForms(body.PluralForms),
+	}
+
+	if err := t.repo.CreateTranslation(entity, stringValue(params.XAuthor)); err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toAPITranslation(entity, entity.Locale))
+}
+
+func (t TranslationRESTHandler) PutTranslationKey(w http.ResponseWriter, r *http.Request, key string, params api.PutTranslationKeyParams) {
+	namespace, ok := t.namespaceSettings(w)
+	if !ok {
+		return
+	}
+
+	locale, ok := t.parseLocale(w, namespace, params.Locale)
+	if !ok {
+		return
+	}
+
+	var body api.PutTranslationKeyJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	translationEntity, err := t.repo.UpdateTranslation(key, locale, body.Translation, toDBPluralForms(body.PluralForms), stringValue(params.XAuthor))
+	if err == nil {
+		writeJSON(w, http.StatusOK, toAPITranslation(translationEntity, translationEntity.Locale))
+		return
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	translationEntity = &translation.Translation{
+		LanguageKey: key,
+		Locale:      locale,
+		Translation: body.Translation,
+		PluralForms: toDBPluralForms(body.PluralForms),
+	}
+
+	if err := t.repo.CreateTranslation(translationEntity, stringValue(params.XAuthor)); err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toAPITranslation(translationEntity, translationEntity.Locale))
+}
+
+func (t TranslationRESTHandler) PatchTranslationKey(w http.ResponseWriter, r *http.Request, key string, params api.PatchTranslationKeyParams) {
+	namespace, ok := t.namespaceSettings(w)
+	if !ok {
+		return
+	}
+
+	locale, ok := t.parseLocale(w, namespace, params.Locale)
+	if !ok {
+		return
+	}
+
+	var body api.PatchTranslationKeyJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	translationEntity, err := translation.PatchTranslation(t.repo, key, locale, translation.TranslationPatch{
+		Translation: body.Translation,
+		PluralForms: toDBPluralForms(body.PluralForms),
+	}, stringValue(params.XAuthor))
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toAPITranslation(translationEntity, translationEntity.Locale))
 }
 
-type TranslationRESTHandler struct {
-	repo translation.Repository
+func (t TranslationRESTHandler) DeleteTranslationKey(w http.ResponseWriter, _ *http.Request, key string, params api.DeleteTranslationKeyParams) {
+	namespace, ok := t.namespaceSettings(w)
+	if !ok {
+		return
+	}
+
+	locale, ok := t.parseLocale(w, namespace, params.Locale)
+	if !ok {
+		return
+	}
+
+	if err := t.repo.DeleteTranslation(key, locale, stringValue(params.XAuthor)); err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (t TranslationRESTHandler) PostTranslationKeyRender(w http.ResponseWriter, r *http.Request, key string, params api.PostTranslationKeyRenderParams) {
+	namespace, ok := t.namespaceSettings(w)
+	if !ok {
+		return
+	}
+
+	var body api.PostTranslationKeyRenderJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	locale, ok := t.requestLocale(w, namespace, params.Locale, params.AcceptLanguage)
+	if !ok {
+		return
+	}
+
+	chain, ok := t.fallbackChain(w, namespace, locale)
+	if !ok {
+		return
+	}
+
+	repo, ok := t.readRepository(w, params.Release, params.Preview)
+	if !ok {
+		return
+	}
+
+	translationEntity, err := repo.GetTranslationByKey(key, chain...)
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	var args map[string]any
+	if body.Args != nil {
+		args = *body.Args
+	}
+
+	var location *time.Location
+	if body.TimeZone != nil {
+		location, err = translation.LoadTimeZone(*body.TimeZone)
+		if err != nil {
+			writeRenderError(w, err)
+			return
+		}
+	}
+
+	text, err := translation.RenderMessage(translationEntity.Locale, translationEntity.Translation, args, location)
+	if err != nil {
+		writeRenderError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Language", translationEntity.Locale.LanguageTag())
+	writeJSON(w, http.StatusOK, api.RenderResult{
+		LanguageKey:    translationEntity.LanguageKey,
+		Locale:         locale.String(),
+		ResolvedLocale: translationEntity.Locale.String(),
+		Text:           text,
+	})
+}
+
+func (t TranslationRESTHandler) GetNamespacesNamespaceTranslations(w http.ResponseWriter, r *http.Request, namespace string, params api.GetNamespacesNamespaceTranslationsParams) {
+	listParams := api.GetTranslationsParams{
+		Locale:          params.Locale,
+		AcceptLanguage:  params.AcceptLanguage,
+		Release:         params.Release,
+		Preview:         params.Preview,
+		PageSize:        params.PageSize,
+		PageToken:       params.PageToken,
+		KeyPrefix:       params.KeyPrefix,
+		IfNoneMatch:     params.IfNoneMatch,
+		IfModifiedSince: params.IfModifiedSince,
+	}
+	if params.OrderBy != nil {
+		orderBy := api.GetTranslationsParamsOrderBy(*params.OrderBy)
+		listParams.OrderBy = &orderBy
+	}
+	if params.Format != nil {
+		format := api.GetTranslationsParamsFormat(*params.Format)
+		listParams.Format = &format
+	}
+
+	t.inNamespace(namespace).GetTranslations(w, r, listParams)
+}
+
+func (t TranslationRESTHandler) PostNamespacesNamespaceTranslation(w http.ResponseWriter, r *http.Request, namespace string, params api.PostNamespacesNamespaceTranslationParams) {
+	t.inNamespace(namespace).PostTranslation(w, r, api.PostTranslationParams(params))
+}
+
+func (t TranslationRESTHandler) GetNamespacesNamespaceTranslationKey(w http.ResponseWriter, r *http.Request, namespace, key string, params api.GetNamespacesNamespaceTranslationKeyParams) {
+	t.inNamespace(namespace).GetTranslationKey(w, r, key, api.GetTranslationKeyParams(params))
+}
+
+func (t TranslationRESTHandler) PutNamespacesNamespaceTranslationKey(w http.ResponseWriter, r *http.Request, namespace, key string, params api.PutNamespacesNamespaceTranslationKeyParams) {
+	t.inNamespace(namespace).PutTranslationKey(w, r, key, api.PutTranslationKeyParams(params))
+}
+
+func (t TranslationRESTHandler) PatchNamespacesNamespaceTranslationKey(w http.ResponseWriter, r *http.Request, namespace, key string, params api.PatchNamespacesNamespaceTranslationKeyParams) {
+	t.inNamespace(namespace).PatchTranslationKey(w, r, key, api.PatchTranslationKeyParams(params))
+}
+
+func (t TranslationRESTHandler) DeleteNamespacesNamespaceTranslationKey(w http.ResponseWriter, r *http.Request, namespace, key string, params api.DeleteNamespacesNamespaceTranslationKeyParams) {
+	t.inNamespace(namespace).DeleteTranslationKey(w, r, key, api.DeleteTranslationKeyParams(params))
+}
+
+func (t TranslationRESTHandler) GetTranslationKeyHistory(w http.ResponseWriter, _ *http.Request, key string, params api.GetTranslationKeyHistoryParams) {
+	namespace, ok := t.namespaceSettings(w)
+	if !ok {
+		return
+	}
+
+	locale, ok := t.parseLocale(w, namespace, params.Locale)
+	if !ok {
+		return
+	}
+
+	revisionEntities, err := t.revisions.ListRevisions(key, locale)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	response := []api.Revision{}
+
+	for i := range revisionEntities {
+		response = append(response, toAPIRevision(&revisionEntities[i]))
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (t TranslationRESTHandler) PostTranslationKeyRestore(w http.ResponseWriter, r *http.Request, key string, params api.PostTranslationKeyRestoreParams) {
+	namespace, ok := t.namespaceSettings(w)
+	if !ok {
+		return
+	}
+
+	var body api.PostTranslationKeyRestoreJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	revision, err := t.revisions.GetRevision(body.Revision)
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	// The namespace may have disabled the locale since the revision was made.
+	if _, ok := t.parseLocale(w, namespace, revision.Locale.String()); !ok {
+		return
+	}
+
+	translationEntity, err := translation.RestoreRevision(t.repo, revision, key, stringValue(params.XAuthor))
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toAPITranslation(translationEntity, translationEntity.Locale))
+}
+
+func (t TranslationRESTHandler) GetDrafts(w http.ResponseWriter, _ *http.Request, params api.GetDraftsParams) {
+	if _, ok := t.namespaceSettings(w); !ok {
+		return
+	}
+
+	var status translation.Status
+	if params.Status != nil {
+		status = translation.Status(*params.Status)
+	}
+
+	draftEntities, err := t.drafts.ListDrafts(status)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	response := []api.Draft{}
+
+	for i := range draftEntities {
+		response = append(response, toAPIDraft(&draftEntities[i]))
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (t TranslationRESTHandler) GetTranslationKeyDraft(w http.ResponseWriter, _ *http.Request, key string, params api.GetTranslationKeyDraftParams) {
+	namespace, ok := t.namespaceSettings(w)
+	if !ok {
+		return
+	}
+
+	locale, ok := t.parseLocale(w, namespace, params.Locale)
+	if !ok {
+		return
+	}
+
+	draftEntity, err := t.drafts.GetDraft(key, locale)
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toAPIDraft(draftEntity))
+}
+
+func (t TranslationRESTHandler) PutTranslationKeyDraft(w http.ResponseWriter, r *http.Request, key string, params api.PutTranslationKeyDraftParams) {
+	namespace, ok := t.namespaceSettings(w)
+	if !ok {
+		return
+	}
+
+	locale, ok := t.parseLocale(w, namespace, params.Locale)
+	if !ok {
+		return
+	}
+
+	var body api.PutTranslationKeyDraftJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	draftEntity, err := t.drafts.SaveDraft(key, locale, body.Translation, toDBPluralForms(body.PluralForms), stringValue(params.XAuthor))
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toAPIDraft(draftEntity))
+}
+
+func (t TranslationRESTHandler) DeleteTranslationKeyDraft(w http.ResponseWriter, _ *http.Request, key string, params api.DeleteTranslationKeyDraftParams) {
+	namespace, ok := t.namespaceSettings(w)
+	if !ok {
+		return
+	}
+
+	locale, ok := t.parseLocale(w, namespace, params.Locale)
+	if !ok {
+		return
+	}
+
+	if err := t.drafts.DiscardDraft(key, locale); err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (t TranslationRESTHandler) PostTranslationKeyDraftSubmit(w http.ResponseWriter, _ *http.Request, key string, params api.PostTranslationKeyDraftSubmitParams) {
+	namespace, ok := t.namespaceSettings(w)
+	if !ok {
+		return
+	}
+
+	locale, ok := t.parseLocale(w, namespace, params.Locale)
+	if !ok {
+		return
+	}
+
+	draftEntity, err := t.drafts.Submit(key, locale)
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toAPIDraft(draftEntity))
+}
+
+func (t TranslationRESTHandler) PostTranslationKeyDraftApprove(w http.ResponseWriter, r *http.Request, key string, params api.PostTranslationKeyDraftApproveParams) {
+	namespace, ok := t.namespaceSettings(w)
+	if !ok {
+		return
+	}
+
+	locale, ok := t.parseLocale(w, namespace, params.Locale)
+	if !ok {
+		return
+	}
+
+	var body api.PostTranslationKeyDraftApproveJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	draftEntity, err := t.drafts.Approve(key, locale, stringValue(params.XAuthor), stringValue(body.Comment))
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toAPIDraft(draftEntity))
+}
+
+func (t TranslationRESTHandler) PostTranslationKeyDraftReject(w http.ResponseWriter, r *http.Request, key string, params api.PostTranslationKeyDraftRejectParams) {
+	namespace, ok := t.namespaceSettings(w)
+	if !ok {
+		return
+	}
+
+	locale, ok := t.parseLocale(w, namespace, params.Locale)
+	if !ok {
+		return
+	}
+
+	var body api.PostTranslationKeyDraftRejectJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	draftEntity, err := t.drafts.Reject(key, locale, stringValue(params.XAuthor), stringValue(body.Comment))
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toAPIDraft(draftEntity))
 }
 
-func (t TranslationRESTHandler) GetTranslationKey(w http.ResponseWriter, _ *http.Request, key string, params api.GetTranslationKeyParams) {
-	locale, ok := parseLocale(*params.Locale)
+func (t TranslationRESTHandler) PostTranslationKeyDraftPublish(w http.ResponseWriter, _ *http.Request, key string, params api.PostTranslationKeyDraftPublishParams) {
+	namespace, ok := t.namespaceSettings(w)
+	if !ok {
+		return
+	}
 
+	locale, ok := t.parseLocale(w, namespace, params.Locale)
 	if !ok {
+		return
+	}
+
+	translationEntity, err := t.drafts.Publish(key, locale, stringValue(params.XAuthor))
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+	t.invalidate(locale)
+
+	writeJSON(w, http.StatusOK, toAPITranslation(translationEntity, translationEntity.Locale))
+}
+
+func (t TranslationRESTHandler) GetNamespacesNamespaceTranslationKeyHistory(w http.ResponseWriter, r *http.Request, namespace, key string, params api.GetNamespacesNamespaceTranslationKeyHistoryParams) {
+	t.inNamespace(namespace).GetTranslationKeyHistory(w, r, key, api.GetTranslationKeyHistoryParams(params))
+}
+
+func (t TranslationRESTHandler) PostNamespacesNamespaceTranslationKeyRestore(w http.ResponseWriter, r *http.Request, namespace, key string, params api.PostNamespacesNamespaceTranslationKeyRestoreParams) {
+	t.inNamespace(namespace).PostTranslationKeyRestore(w, r, key, api.PostTranslationKeyRestoreParams(params))
+}
+
+func (t TranslationRESTHandler) GetNamespacesNamespaceDrafts(w http.ResponseWriter, r *http.Request, namespace string, params api.GetNamespacesNamespaceDraftsParams) {
+	t.inNamespace(namespace).GetDrafts(w, r, api.GetDraftsParams(params))
+}
+
+func (t TranslationRESTHandler) GetNamespacesNamespaceTranslationKeyDraft(w http.ResponseWriter, r *http.Request, namespace, key string, params api.GetNamespacesNamespaceTranslationKeyDraftParams) {
+	t.inNamespace(namespace).GetTranslationKeyDraft(w, r, key, api.GetTranslationKeyDraftParams(params))
+}
+
+func (t TranslationRESTHandler) PutNamespacesNamespaceTranslationKeyDraft(w http.ResponseWriter, r *http.Request, namespace, key string, params api.PutNamespacesNamespaceTranslationKeyDraftParams) {
+	t.inNamespace(namespace).PutTranslationKeyDraft(w, r, key, api.PutTranslationKeyDraftParams(params))
+}
+
+func (t TranslationRESTHandler) DeleteNamespacesNamespaceTranslationKeyDraft(w http.ResponseWriter, r *http.Request, namespace, key string, params api.DeleteNamespacesNamespaceTranslationKeyDraftParams) {
+	t.inNamespace(namespace).DeleteTranslationKeyDraft(w, r, key, api.DeleteTranslationKeyDraftParams(params))
+}
+
+func (t TranslationRESTHandler) PostNamespacesNamespaceTranslationKeyDraftSubmit(w http.ResponseWriter, r *http.Request, namespace, key string, params api.PostNamespacesNamespaceTranslationKeyDraftSubmitParams) {
+	t.inNamespace(namespace).PostTranslationKeyDraftSubmit(w, r, key, api.PostTranslationKeyDraftSubmitParams(params))
+}
+
+func (t TranslationRESTHandler) PostNamespacesNamespaceTranslationKeyDraftApprove(w http.ResponseWriter, r *http.Request, namespace, key string, params api.PostNamespacesNamespaceTranslationKeyDraftApproveParams) {
+	t.inNamespace(namespace).PostTranslationKeyDraftApprove(w, r, key, api.PostTranslationKeyDraftApproveParams(params))
+}
+
+func (t TranslationRESTHandler) PostNamespacesNamespaceTranslationKeyDraftReject(w http.ResponseWriter, r *http.Request, namespace, key string, params api.PostNamespacesNamespaceTranslationKeyDraftRejectParams) {
+	t.inNamespace(namespace).PostTranslationKeyDraftReject(w, r, key, api.PostTranslationKeyDraftRejectParams(params))
+}
+
+func (t TranslationRESTHandler) PostNamespacesNamespaceTranslationKeyDraftPublish(w http.ResponseWriter, r *http.Request, namespace, key string, params api.PostNamespacesNamespaceTranslationKeyDraftPublishParams) {
+	t.inNamespace(namespace).PostTranslationKeyDraftPublish(w, r, key, api.PostTranslationKeyDraftPublishParams(params))
+}
+
+func (t TranslationRESTHandler) GetLocales(w http.ResponseWriter, _ *http.Request, params api.GetLocalesParams) {
+	includeDisabled := params.IncludeDisabled != nil && *params.IncludeDisabled
+
+	localeEntities, err := t.locales.GetLocales(includeDisabled)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	response := []api.Locale{}
+
+	for i := range localeEntities {
+		response = append(response, toAPILocale(&localeEntities[i]))
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (t TranslationRESTHandler) PostLocale(w http.ResponseWriter, r *http.Request) {
+	var body api.PostLocaleJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
-	translationEntity, err := t.repo.GetTranslationByKey(key, locale)
+	fallback := ""
+	if body.Fallback != nil {
+		fallback = *body.Fallback
+	}
+
+	localeEntity, err := t.locales.AddLocale(body.Code, fallback)
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toAPILocale(localeEntity))
+}
+
+func (t TranslationRESTHandler) PatchLocaleCode(w http.ResponseWriter, r *http.Request, code string) {
+	var body api.PatchLocaleCodeJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	localeEntity, err := t.locales.UpdateLocale(code, translation.LocaleUpdate{
+		Enabled:  body.Enabled,
+		Fallback: body.Fallback,
+	})
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toAPILocale(localeEntity))
+}
+
+func (t TranslationRESTHandler) GetNamespaces(w http.ResponseWriter, _ *http.Request) {
+	namespaceEntities, err := t.namespaces.GetNamespaces()
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			w.WriteHeader(http.StatusNotFound)
-			return
-		}
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	localeStr := locale.String()
+	response := []api.Namespace{}
 
-	response := api.Translation{
-		Id:          &translationEntity.ID,
-		LanguageKey: &translationEntity.LanguageKey,
-		Locale:      &localeStr,
-		Translation: &translationEntity.Translation,
+	for i := range namespaceEntities {
+		response = append(response, toAPINamespace(&namespaceEntities[i]))
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	writeJSON(w, http.StatusOK, response)
+}
 
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		slog.Error("failed to encode response", "error", err)
+func (t TranslationRESTHandler) PostNamespace(w http.ResponseWriter, r *http.Request) {
+	var body api.PostNamespaceJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	namespaceEntity, err := t.namespaces.CreateNamespace(body.Name, translation.NamespaceUpdate{
+		Description:   body.Description,
+		DefaultLocale: body.DefaultLocale,
+		Locales:       body.Locales,
+	})
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
 	}
+
+	writeJSON(w, http.StatusCreated, toAPINamespace(namespaceEntity))
 }
 
-func (t TranslationRESTHandler) GetTranslations(w http.ResponseWriter, _ *http.Request, params api.GetTranslationsParams) {
-	locale, ok := parseLocale(*params.Locale)
+func (t TranslationRESTHandler) GetNamespaceNamespace(w http.ResponseWriter, _ *http.Request, namespace string) {
+	namespaceEntity, err := t.namespaces.GetNamespace(namespace)
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
 
-	if !ok {
+	writeJSON(w, http.StatusOK, toAPINamespace(namespaceEntity))
+}
+
+func (t TranslationRESTHandler) PatchNamespaceNamespace(w http.ResponseWriter, r *http.Request, namespace string) {
+	var body api.PatchNamespaceNamespaceJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
-	translationEntities, err := t.repo.GetTranslations(locale)
+	namespaceEntity, err := t.namespaces.UpdateNamespace(namespace, translation.NamespaceUpdate{
+		Description:   body.Description,
+		DefaultLocale: body.DefaultLocale,
+		Locales:       body.Locales,
+	})
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toAPINamespace(namespaceEntity))
+}
+
+func (t TranslationRESTHandler) DeleteNamespaceNamespace(w http.ResponseWriter, _ *http.Request, namespace string) {
+	if err := t.namespaces.DeleteNamespace(namespace); err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (t TranslationRESTHandler) GetReleases(w http.ResponseWriter, _ *http.Request) {
+	releaseEntities, err := t.releases.ListReleases()
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	response := []api.Translation{}
+	response := []api.Release{}
+
+	for i := range releaseEntities {
+		response = append(response, toAPIRelease(&releaseEntities[i]))
+	}
 
-	localeStr := locale.String()
+	writeJSON(w, http.StatusOK, response)
+}
 
-	for _, entity := range translationEntities {
-		response = append(response, api.Translation{
-			Id:          &entity.ID,
-			LanguageKey: &entity.LanguageKey,
-			Locale:      &localeStr,
-			Translation: &entity.Translation,
-		})
+func (t TranslationRESTHandler) PostRelease(w http.ResponseWriter, r *http.Request) {
+	var body api.PostReleaseJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	description := ""
+	if body.Description != nil {
+		description = *body.Description
+	}
 
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		slog.Error("failed to encode response", "error", err)
+	releaseEntity, err := t.releases.CreateRelease(description)
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toAPIRelease(releaseEntity))
+}
+
+func (t TranslationRESTHandler) GetReleaseRelease(w http.ResponseWriter, _ *http.Request, release string) {
+	releaseEntity, err := t.releases.GetRelease(release)
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toAPIRelease(releaseEntity))
+}
+
+func (t TranslationRESTHandler) PostReleaseReleaseRollback(w http.ResponseWriter, _ *http.Request, release string) {
+	releaseEntity, err := t.releases.RollbackRelease(release)
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toAPIRelease(releaseEntity))
+}
+
+// readRepository returns the repository of the requested release or preview,
+// or the live translations if neither is requested.
+func (t TranslationRESTHandler) readRepository(w http.ResponseWriter, release *string, preview *bool) (translation.Repository, bool) {
+	if preview != nil && *preview {
+		if release != nil && *release != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return nil, false
+		}
+		return t.drafts.PreviewRepository(), true
+	}
+	if release == nil || *release == "" {
+		return t.repo, true
+	}
+
+	repo, err := t.releases.ReleaseRepository(*release)
+	if err != nil {
+		writeRepositoryError(w, err)
+		return nil, false
+	}
+	return repo.InNamespace(t.namespace), true
+}
+
+// inNamespace returns a copy of the handler serving the translations of the
+// namespace.
+func (t TranslationRESTHandler) inNamespace(namespace string) TranslationRESTHandler {
+	t.namespace = namespace
+	t.repo = t.repo.InNamespace(namespace)
+	t.revisions = t.revisions.InNamespace(namespace)
+	t.drafts = t.drafts.InNamespace(namespace)
+	t.feed = t.feed.InNamespace(namespace)
+	return t
+}
+
+// invalidate drops the cached translations of locale after a write that
+// bypasses the repository, such as an import or a published draft. Its cache
+// would only learn about the changes through the change listener.
+func (t TranslationRESTHandler) invalidate(locale translation.Locale) {
+	if handler, ok := t.repo.(translation.ChangeHandler); ok {
+		handler.TranslationChanged(translation.Change{Namespace: t.namespace, Locale: locale})
+	}
+}
+
+// namespaceSettings loads the namespace served by the handler and writes the
+// error response itself if it does not exist. Handlers load it once and pass
+// it to the locale helpers.
+func (t TranslationRESTHandler) namespaceSettings(w http.ResponseWriter) (*translation.Namespace, bool) {
+	namespace, err := t.namespaces.GetNamespace(t.namespace)
+	if err != nil {
+		writeRepositoryError(w, err)
+		return nil, false
+	}
+	return namespace, true
+}
+
+// requestLocale determines the locale of a read request. An explicit locale
+// query parameter wins, otherwise the Accept-Language header is negotiated
+// against the locales enabled in the namespace with the default locale of
+// the namespace as last resort.
+func (t TranslationRESTHandler) requestLocale(w http.ResponseWriter, namespace *translation.Namespace, requested, acceptLanguage *string) (translation.Locale, bool) {
+	w.Header().Add("Vary", "Accept-Language")
+
+	if requested != nil {
+		return t.parseLocale(w, namespace, *requested)
+	}
+
+	if acceptLanguage != nil {
+		localeEntities, err := t.locales.GetLocales(false)
+		if err != nil {
+			slog.Error("failed to list locales", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return "", false
+		}
+
+		supported := make([]translation.Locale, 0, len(localeEntities))
+		for _, localeEntity := range localeEntities {
+			supported = append(supported, localeEntity.Code)
+		}
+
+		if locale, ok := translation.NegotiateLocale(*acceptLanguage, namespace.FilterLocales(supported)); ok {
+			return locale, true
+		}
+	}
+
+	return t.parseLocale(w, namespace, namespace.EffectiveDefaultLocale().String())
+}
+
+// parseLocale resolves code against the locale registry and the locales
+// enabled in the namespace, and writes the error response itself if the
+// locale cannot be used.
+func (t TranslationRESTHandler) parseLocale(w http.ResponseWriter, namespace *translation.Namespace, code string) (translation.Locale, bool) {
+	locale, err := t.locales.ParseLocale(code)
+	if err != nil {
+		if errors.Is(err, translation.ErrUnsupportedLocale) {
+			w.WriteHeader(http.StatusBadRequest)
+			return "", false
+		}
+		slog.Error("failed to resolve locale", "locale", code, "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return "", false
+	}
+
+	if !namespace.Enables(locale) {
+		w.WriteHeader(http.StatusBadRequest)
+		return "", false
+	}
+
+	return locale, true
+}
+
+// fallbackChain returns the fallback chain of locale restricted to the locales
+// enabled in the namespace.
+func (t TranslationRESTHandler) fallbackChain(w http.ResponseWriter, namespace *translation.Namespace, locale translation.Locale) ([]translation.Locale, bool) {
+	chain, err := t.locales.FallbackChain(locale)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return nil, false
 	}
+
+	return namespace.FilterLocales(chain), true
 }
 
-func SetupRouter(database *gorm.DB) http.Handler {
-	translationHandler := NewTranslationRESTHandler(database)
+func SetupRouter(repo translation.Repository, locales translation.LocaleRegistry, namespaces translation.NamespaceRegistry, releases translation.ReleaseStore, revisions translation.RevisionStore, drafts translation.DraftStore, catalogs translation.CatalogStore, search translation.SearchStore, feed *translation.ChangeFeed, config RESTConfig) http.Handler {
+	translationHandler := NewTranslationRESTHandler(repo, locales, namespaces, releases, revisions, drafts, catalogs, search, feed, config)
 
 	router := api.HandlerWithOptions(translationHandler, api.StdHTTPServerOptions{
 		BaseURL: "/api/v1",
@@ -110,13 +1138,259 @@ func SetupRouter(database *gorm.DB) http.Handler {
 	return router
 }
 
-func parseLocale(s string) (translation.Locale, bool) {
-	switch s {
-	case "en_GB":
-		return translation.LocaleENGB, true
-	case "de_DE":
-		return translation.LocaleDEDE, true
+// toAPITranslation maps a translation served for the requested locale, the
+// entity locale may differ if it was resolved through a fallback.
+func toAPITranslation(entity *translation.Translation, requested translation.Locale) api.Translation {
+	localeStr := requested.String()
+	resolvedLocaleStr := entity.Locale.String()
+	status := api.TranslationStatus(entity.CurrentStatus())
+
+	return api.Translation{
+		Id:             &entity.ID,
+		LanguageKey:    &entity.LanguageKey,
+		Locale:         &localeStr,
+		ResolvedLocale: &resolvedLocaleStr,
+		Translation:    &entity.Translation,
+		Status:         &status,
+		PluralForms:    toAPIPluralForms(entity.PluralForms),
+		Comment:        &entity.Comment,
+		Fuzzy:          &entity.Fuzzy,
+	}
+}
+
+func toAPIPluralForms(forms translation.PluralForms) *api.PluralForms {
+	if len(forms) == 0 {
+		return nil
+	}
+
+	value := func(category translation.PluralCategory) *string {
+		if text, ok := forms[category]; ok {
+			return &text
+		}
+		return nil
+	}
+
+	return &api.PluralForms{
+		Zero:  value(translation.PluralZero),
+		One:   value(translation.PluralOne),
+		Two:   value(translation.PluralTwo),
+		Few:   value(translation.PluralFew),
+		Many:  value(translation.PluralMany),
+		Other: value(translation.PluralOther),
+	}
+}
+
+func toDBPluralForms(forms *api.PluralForms) translation.PluralForms {
+	if forms == nil {
+		return nil
+	}
+
+	result := translation.PluralForms{}
+	for category, text := range map[translation.PluralCategory]*string{
+		translation.PluralZero:  forms.Zero,
+		translation.PluralOne:   forms.One,
+		translation.PluralTwo:   forms.Two,
+		translation.PluralFew:   forms.Few,
+		translation.PluralMany:  forms.Many,
+		translation.PluralOther: forms.Other,
+	} {
+		if text != nil {
+			result[category] = *text
+		}
+	}
+	return result
+}
+
+func toAPIDraft(entity *translation.Draft) api.Draft {
+	return api.Draft{
+		LanguageKey: entity.LanguageKey,
+		Locale:      entity.Locale.String(),
+		Translation: entity.Translation,
+		PluralForms: toAPIPluralForms(entity.PluralForms),
+		Status:      api.TranslationStatus(entity.Status),
+		Comment:     entity.Comment,
+		ChangedBy:   entity.ChangedBy,
+		ReviewedBy:  entity.ReviewedBy,
+		CreatedAt:   entity.CreatedAt,
+		UpdatedAt:   entity.UpdatedAt,
+	}
+}
+
+func toAPIRevision(entity *translation.Revision) api.Revision {
+	return api.Revision{
+		Id:          entity.ID,
+		LanguageKey: entity.LanguageKey,
+		Locale:      entity.Locale.String(),
+		Type:        toAPIChangeType(entity.Operation),
+		Translation: entity.Translation,
+		PluralForms: toAPIPluralForms(entity.PluralForms),
+		ChangedBy:   entity.ChangedBy,
+		ChangedAt:   entity.ChangedAt,
+	}
+}
+
+func toAPIRelease(entity *translation.Release) api.Release {
+	return api.Release{
+		Id:          &entity.ID,
+		Description: &entity.Description,
+		CreatedAt:   &entity.CreatedAt,
+		Latest:      &entity.Latest,
+	}
+}
+
+func toAPINamespace(entity *translation.Namespace) api.Namespace {
+	result := api.Namespace{
+		Name:        entity.Name,
+		Description: entity.Description,
+		Locales:     []string{},
+		CreatedAt:   entity.CreatedAt,
+		UpdatedAt:   entity.UpdatedAt,
+	}
+	if entity.DefaultLocale != nil {
+		defaultLocale := entity.DefaultLocale.String()
+		result.DefaultLocale = &defaultLocale
+	}
+	for _, locale := range entity.Locales {
+		result.Locales = append(result.Locales, locale.String())
+	}
+	return result
+}
+
+func toAPILocale(entity *translation.LocaleDefinition) api.Locale {
+	code := entity.Code.String()
+
+	result := api.Locale{
+		Code:    &code,
+		Enabled: &entity.Enabled,
+	}
+	if entity.Fallback != nil {
+		fallback := entity.Fallback.String()
+		result.Fallback = &fallback
+	}
+	return result
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, response any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		slog.Error("failed to encode response", "error", err)
+	}
+}
+
+// writeBundle writes a cacheable JSON response, see writeBundleBody.
+func (t TranslationRESTHandler) writeBundle(w http.ResponseWriter, ifNoneMatch, ifModifiedSince *string, lastModified time.Time, cacheControl string, response any) {
+	body, err := json.Marshal(response)
+	if err != nil {
+		slog.Error("failed to encode response", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	body = append(body, '\n')
+
+	writeBundleBody(w, ifNoneMatch, ifModifiedSince, lastModified, cacheControl, "application/json", body)
+}
+
+// writeBundleBody writes a cacheable response with a strong ETag derived from
+// the body, or 304 Not Modified if the copy of the client is still current.
+func writeBundleBody(w http.ResponseWriter, ifNoneMatch, ifModifiedSince *string, lastModified time.Time, cacheControl, contentType string, body []byte) {
+	sum := sha256.Sum256(body)
+	etag := `"` + base64.RawURLEncoding.EncodeToString(sum[:]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", cacheControl)
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(ifNoneMatch, ifModifiedSince, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		slog.Error("failed to write response", "error", err)
+	}
+}
+
+// bundleCacheControl returns the Cache-Control header of a bundle read from
+// the release or preview.
+func (t TranslationRESTHandler) bundleCacheControl(release *string, preview *bool) string {
+	switch {
+	case preview != nil && *preview:
+		return previewCacheControl
+	case release != nil && *release != "" && *release != translation.LatestRelease:
+		return immutableCacheControl
 	default:
-		return "", false
+		return t.cacheControl
+	}
+}
+
+// notModified evaluates the preconditions of RFC 9110, If-Modified-Since only
+// counts without If-None-Match.
+func notModified(ifNoneMatch, ifModifiedSince *string, etag string, lastModified time.Time) bool {
+	if ifNoneMatch != nil {
+		for _, candidate := range strings.Split(*ifNoneMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ifModifiedSince != nil && !lastModified.IsZero() {
+		since, err := http.ParseTime(*ifModifiedSince)
+		return err == nil && !lastModified.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
+
+func writeRepositoryError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, translation.ErrInvalidTranslation),
+		errors.Is(err, translation.ErrUnsupportedLocale),
+		errors.Is(err, translation.ErrInvalidLocale),
+		errors.Is(err, translation.ErrInvalidListOptions),
+		errors.Is(err, translation.ErrUnknownVersion),
+		errors.Is(err, translation.ErrInvalidRelease),
+		errors.Is(err, translation.ErrInvalidRevision),
+		errors.Is(err, translation.ErrInvalidNamespace),
+		errors.Is(err, translation.ErrInvalidSearch):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, translation.ErrTranslationExists),
+		errors.Is(err, translation.ErrLocaleExists),
+		errors.Is(err, translation.ErrReleaseImmutable),
+		errors.Is(err, translation.ErrInvalidTransition),
+		errors.Is(err, translation.ErrPreviewReadOnly),
+		errors.Is(err, translation.ErrNamespaceExists),
+		errors.Is(err, translation.ErrNamespaceNotEmpty):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Is(err, translation.ErrReviewRequired):
+		w.WriteHeader(http.StatusForbidden)
+	case errors.Is(err, gorm.ErrRecordNotFound),
+		errors.Is(err, translation.ErrUnknownNamespace):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		slog.Error("failed to handle translation request", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// writeRenderError reports why a message could not be rendered: the caller
+// passed missing or mistyped arguments, or the stored value is no valid
+// message.
+func writeRenderError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, translation.ErrInvalidArgument):
+		writeJSON(w, http.StatusBadRequest, api.Error{Message: err.Error()})
+	case errors.Is(err, translation.ErrInvalidMessage):
+		writeJSON(w, http.StatusUnprocessableEntity, api.Error{Message: err.Error()})
+	default:
+		writeRepositoryError(w, err)
 	}
 }
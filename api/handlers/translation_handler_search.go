@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+
+	api "github.com/henok321/translation-service/gen"
+	"github.com/henok321/translation-service/pkg/translation"
+)
+
+// GetTranslationsSearch searches the translations of the namespace by text
+// and key, all enabled locales unless a locale is given.
+func (t TranslationRESTHandler) GetTranslationsSearch(w http.ResponseWriter, _ *http.Request, params api.GetTranslationsSearchParams) {
+	namespace, ok := t.namespaceSettings(w)
+	if !ok {
+		return
+	}
+
+	options := translation.SearchOptions{
+		Query:     params.Q,
+		PageToken: stringValue(params.PageToken),
+	}
+	if params.PageSize != nil {
+		options.PageSize = *params.PageSize
+	}
+	if params.Locale != nil {
+		locale, ok := t.parseLocale(w, namespace, *params.Locale)
+		if !ok {
+			return
+		}
+		options.Locale = locale
+	}
+
+	page, err := t.search.SearchTranslations(t.namespace, options)
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	if page.NextPageToken != "" {
+		w.Header().Set("X-Next-Page-Token", page.NextPageToken)
+	}
+
+	response := make([]api.SearchResult, 0, len(page.Results))
+	for _, result := range page.Results {
+		response = append(response, toAPISearchResult(result))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (t TranslationRESTHandler) GetNamespacesNamespaceTranslationsSearch(w http.ResponseWriter, r *http.Request, namespace string, params api.GetNamespacesNamespaceTranslationsSearchParams) {
+	t.inNamespace(namespace).GetTranslationsSearch(w, r, api.GetTranslationsSearchParams(params))
+}
+
+func toAPISearchResult(result translation.SearchResult) api.SearchResult {
+	return api.SearchResult{
+		LanguageKey: result.LanguageKey,
+		Locale:      result.Locale.String(),
+		Translation: result.Translation,
+		PluralForms: toAPIPluralForms(result.PluralForms),
+		Snippet:     result.Snippet,
+		Score:       result.Score,
+	}
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	api "github.com/henok321/translation-service/gen"
+	"github.com/henok321/translation-service/pkg/translation"
+)
+
+const (
+	// sseHeartbeatInterval keeps idle streams and proxies alive. Every write
+	// extends the write deadline by sseWriteTimeout, so streams outlive the
+	// WriteTimeout of the server while stuck clients are still dropped.
+	sseHeartbeatInterval = 5 * time.Second
+	sseWriteTimeout      = 10 * time.Second
+)
+
+// GetTranslationsEvents streams the changes of a locale as Server-Sent Events.
+// The id of every event is its version, so browsers resume with Last-Event-ID
+// after a reconnect.
+func (t TranslationRESTHandler) GetTranslationsEvents(w http.ResponseWriter, r *http.Request, params api.GetTranslationsEventsParams) {
+	namespace, ok := t.namespaceSettings(w)
+	if !ok {
+		return
+	}
+
+	locale, ok := t.requestLocale(w, namespace, params.Locale, params.AcceptLanguage)
+	if !ok {
+		return
+	}
+
+	var version int64
+	if params.LastEventID != nil {
+		lastEventID, err := strconv.ParseInt(*params.LastEventID, 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := t.feed.ValidateVersion(lastEventID); err != nil {
+			writeRepositoryError(w, err)
+			return
+		}
+		version = lastEventID
+	}
+
+	// Subscribe first, so no change between reading and waiting goes unnoticed.
+	subscription := t.feed.Subscribe(locale)
+	defer subscription.Close()
+
+	var snapshot []translation.Translation
+	if params.LastEventID == nil {
+		var err error
+		if snapshot, version, err = t.feed.Snapshot(locale); err != nil {
+			slog.Error("failed to get translations", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Content-Language", locale.LanguageTag())
+	w.WriteHeader(http.StatusOK)
+
+	stream := eventStream{w: w, controller: http.NewResponseController(w)}
+
+	if params.LastEventID == nil {
+		translations := []api.Translation{}
+		for i := range snapshot {
+			translations = append(translations, toAPITranslation(&snapshot[i], locale))
+		}
+		if err := stream.send("snapshot", version, translations); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		changes, err := t.feed.ChangesSince(version, translation.MaxPageSize, locale)
+		if err != nil {
+			slog.Error("failed to get changes", "error", err)
+			return
+		}
+
+		for i := range changes {
+			change := toAPITranslationChange(&changes[i])
+			if err := stream.send(string(change.Type), change.Version, change); err != nil {
+				return
+			}
+			version = change.Version
+		}
+
+		if len(changes) == translation.MaxPageSize {
+			continue
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-subscription.Done():
+			return
+		case <-subscription.Changed():
+		case <-heartbeat.C:
+			if err := stream.comment("heartbeat"); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (t TranslationRESTHandler) GetNamespacesNamespaceTranslationsEvents(w http.ResponseWriter, r *http.Request, namespace string, params api.GetNamespacesNamespaceTranslationsEventsParams) {
+	t.inNamespace(namespace).GetTranslationsEvents(w, r, api.GetTranslationsEventsParams(params))
+}
+
+type eventStream struct {
+	w          io.Writer
+	controller *http.ResponseController
+}
+
+func (s eventStream) send(event string, id int64, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return s.write(fmt.Sprintf("event: %s\nid: %d\ndata: %s\n\n", event, id, payload))
+}
+
+func (s eventStream) comment(text string) error {
+	return s.write(": " + text + "\n\n")
+}
+
+func (s eventStream) write(message string) error {
+	err := s.controller.SetWriteDeadline(time.Now().Add(sseWriteTimeout))
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+
+	if _, err := io.WriteString(s.w, message); err != nil {
+		return err
+	}
+
+	return s.controller.Flush()
+}
+
+func toAPITranslationChange(entity *translation.TranslationChange) api.TranslationChange {
+	return api.TranslationChange{
+		Version:     entity.Version,
+		Type:        toAPIChangeType(entity.Operation),
+		LanguageKey: entity.LanguageKey,
+		Locale:      entity.Locale.String(),
+		Translation: entity.Translation,
+	}
+}
+
+func toAPIChangeType(operation string) api.ChangeType {
+	switch operation {
+	case translation.OperationInsert:
+		return api.Created
+	case translation.OperationUpdate:
+		return api.Updated
+	default:
+		return api.Deleted
+	}
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+
+	api "github.com/henok321/translation-service/gen"
+	"github.com/henok321/translation-service/pkg/translation"
+)
+
+const xliffContentType = "application/xliff+xml"
+
+// GetXliffLocale exports the translations of the namespace from the source
+// locale into a target locale.
+func (t TranslationRESTHandler) GetXliffLocale(w http.ResponseWriter, _ *http.Request, code string, params api.GetXliffLocaleParams) {
+	namespace, ok := t.namespaceSettings(w)
+	if !ok {
+		return
+	}
+
+	version := api.GetXliffLocaleParamsVersionN20
+	if params.Version != nil {
+		version = *params.Version
+	}
+	if version != api.GetXliffLocaleParamsVersionN12 && version != api.GetXliffLocaleParamsVersionN20 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	target, ok := t.parseLocale(w, namespace, code)
+	if !ok {
+		return
+	}
+
+	var source translation.Locale
+	if params.Source != nil {
+		if source, ok = t.parseLocale(w, namespace, *params.Source); !ok {
+			return
+		}
+	} else {
+		source = namespace.EffectiveDefaultLocale()
+	}
+	if source == target {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	document, err := t.catalogs.ExportXLIFF(t.namespace, source, target, string(version))
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	var body bytes.Buffer
+	if err := translation.WriteXLIFF(&body, document); err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	writeCatalog(w, xliffContentType, t.namespace+"."+source.String()+"-"+target.String()+".xlf", body.Bytes())
+}
+
+// PostXliffLocale merges the targets of an XLIFF document into the
+// translations of a locale.
+func (t TranslationRESTHandler) PostXliffLocale(w http.ResponseWriter, r *http.Request, code string, params api.PostXliffLocaleParams) {
+	namespace, ok := t.namespaceSettings(w)
+	if !ok {
+		return
+	}
+
+	locale, ok := t.parseLocale(w, namespace, code)
+	if !ok {
+		return
+	}
+
+	document, err := translation.ParseXLIFF(http.MaxBytesReader(w, r.Body, maxCatalogSize))
+	if err != nil {
+		writeCatalogError(w, err)
+		return
+	}
+
+	report, err := t.catalogs.ImportXLIFF(t.namespace, locale, document, stringValue(params.XAuthor))
+	if err != nil {
+		writeCatalogError(w, err)
+		return
+	}
+
+	t.invalidate(locale)
+
+	writeJSON(w, http.StatusOK, toAPIImportReport(report))
+}
+
+func (t TranslationRESTHandler) GetNamespacesNamespaceXliffLocale(w http.ResponseWriter, r *http.Request, namespace, locale string, params api.GetNamespacesNamespaceXliffLocaleParams) {
+	exportParams := api.GetXliffLocaleParams{Source: params.Source}
+	if params.Version != nil {
+		version := api.GetXliffLocaleParamsVersion(*params.Version)
+		exportParams.Version = &version
+	}
+
+	t.inNamespace(namespace).GetXliffLocale(w, r, locale, exportParams)
+}
+
+func (t TranslationRESTHandler) PostNamespacesNamespaceXliffLocale(w http.ResponseWriter, r *http.Request, namespace, locale string, params api.PostNamespacesNamespaceXliffLocaleParams) {
+	t.inNamespace(namespace).PostXliffLocale(w, r, locale, api.PostXliffLocaleParams(params))
+}
@@ -0,0 +1,398 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/henok321/translation-service/pkg/translation"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const usage = `Usage: cli <command> <subcommand> [flags]
+
+Commands:
+  gettext import -locale <locale> [-namespace <namespace>] [-author <author>] <file>
+  gettext export -locale <locale> [-namespace <namespace>] [-format po|mo] [-o <file>]
+  gettext export -format pot [-namespace <namespace>] [-o <file>]
+  xliff import -locale <locale> [-namespace <namespace>] [-author <author>] <file>
+  xliff export -locale <locale> [-source <locale>] [-namespace <namespace>] [-version 1.2|2.0] [-o <file>]
+  csv import [-namespace <namespace>] [-author <author>] [-dry-run] [-fingerprint <fingerprint>] <file>
+  csv export [-namespace <namespace>] [-locales <locale>,...] [-o <file>]
+
+The database is read from DATABASE_URL, "-" reads or writes stdin and stdout.
+Imports other than dry runs are rejected if REQUIRE_REVIEW is true.
+`
+
+// errUsage is returned for invalid command lines, usage has been printed.
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	err := run(os.Args[1:])
+	if errors.Is(err, errUsage) {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		return errUsage
+	}
+
+	switch args[0] + " " + args[1] {
+	case "gettext import":
+		return gettextImport(args[2:])
+	case "gettext export":
+		return gettextExport(args[2:])
+	case "xliff import":
+		return xliffImport(args[2:])
+	case "xliff export":
+		return xliffExport(args[2:])
+	case "csv import":
+		return csvImport(args[2:])
+	case "csv export":
+		return csvExport(args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		return errUsage
+	}
+}
+
+func gettextImport(args []string) error {
+	flags := flag.NewFlagSet("gettext import", flag.ContinueOnError)
+	locale := flags.String("locale", "", "locale of the catalog")
+	namespace := flags.String("namespace", translation.DefaultNamespace, "namespace to import into")
+	author := flags.String("author", "", "author recorded in the revision history")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+	if *locale == "" || flags.NArg() != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		return errUsage
+	}
+
+	data, err := readInput(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	catalog, err := translation.ParseCatalog(data)
+	if err != nil {
+		return err
+	}
+
+	database, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	code, err := translation.NewLocaleRegistry(database).ParseLocale(*locale)
+	if err != nil {
+		return err
+	}
+
+	report, err := newCatalogStore(database).ImportCatalog(*namespace, code, catalog, *author)
+	if err != nil {
+		return err
+	}
+
+	printReport(report)
+
+	return nil
+}
+
+func gettextExport(args []string) error {
+	flags := flag.NewFlagSet("gettext export", flag.ContinueOnError)
+	locale := flags.String("locale", "", "locale to export, not used by templates")
+	namespace := flags.String("namespace", translation.DefaultNamespace, "namespace to export")
+	format := flags.String("format", "po", "po, mo or pot")
+	output := flags.String("o", "-", "output file")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+	if flags.NArg() != 0 || (*locale == "") != (*format == "pot") {
+		fmt.Fprint(os.Stderr, usage)
+		return errUsage
+	}
+
+	write := translation.WritePO
+	switch *format {
+	case "po", "pot":
+	case "mo":
+		write = translation.WriteMO
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		return errUsage
+	}
+
+	database, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	store := newCatalogStore(database)
+
+	var catalog *translation.Catalog
+	if *format == "pot" {
+		catalog, err = store.ExportTemplate(*namespace)
+	} else {
+		var code translation.Locale
+		code, err = translation.NewLocaleRegistry(database).ParseLocale(*locale)
+		if err != nil {
+			return err
+		}
+		catalog, err = store.ExportCatalog(*namespace, code)
+	}
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	if err := write(&body, catalog); err != nil {
+		return err
+	}
+
+	return writeOutput(*output, body.Bytes())
+}
+
+func xliffImport(args []string) error {
+	flags := flag.NewFlagSet("xliff import", flag.ContinueOnError)
+	locale := flags.String("locale", "", "target locale of the document")
+	namespace := flags.String("namespace", translation.DefaultNamespace, "namespace to import into")
+	author := flags.String("author", "", "author recorded in the revision history")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+	if *locale == "" || flags.NArg() != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		return errUsage
+	}
+
+	data, err := readInput(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	document, err := translation.ParseXLIFF(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	database, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	code, err := translation.NewLocaleRegistry(database).ParseLocale(*locale)
+	if err != nil {
+		return err
+	}
+
+	report, err := newCatalogStore(database).ImportXLIFF(*namespace, code, document, *author)
+	if err != nil {
+		return err
+	}
+
+	printReport(report)
+
+	return nil
+}
+
+func xliffExport(args []string) error {
+	flags := flag.NewFlagSet("xliff export", flag.ContinueOnError)
+	locale := flags.String("locale", "", "target locale to export")
+	source := flags.String("source", "", "source locale, defaults to the default locale of the namespace")
+	namespace := flags.String("namespace", translation.DefaultNamespace, "namespace to export")
+	version := flags.String("version", translation.XLIFF20, "XLIFF version, 1.2 or 2.0")
+	output := flags.String("o", "-", "output file")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+	if *locale == "" || flags.NArg() != 0 || (*version != translation.XLIFF12 && *version != translation.XLIFF20) {
+		fmt.Fprint(os.Stderr, usage)
+		return errUsage
+	}
+
+	database, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	registry := translation.NewLocaleRegistry(database)
+
+	target, err := registry.ParseLocale(*locale)
+	if err != nil {
+		return err
+	}
+
+	var sourceLocale translation.Locale
+	if *source != "" {
+		sourceLocale, err = registry.ParseLocale(*source)
+	} else {
+		var settings *translation.Namespace
+		settings, err = translation.NewNamespaceRegistry(database).GetNamespace(*namespace)
+		if settings != nil {
+			sourceLocale = settings.EffectiveDefaultLocale()
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	document, err := newCatalogStore(database).ExportXLIFF(*namespace, sourceLocale, target, *version)
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	if err := translation.WriteXLIFF(&body, document); err != nil {
+		return err
+	}
+
+	return writeOutput(*output, body.Bytes())
+}
+
+func csvImport(args []string) error {
+	flags := flag.NewFlagSet("csv import", flag.ContinueOnError)
+	namespace := flags.String("namespace", translation.DefaultNamespace, "namespace to import into")
+	author := flags.String("author", "", "author recorded in the revision history")
+	dryRun := flags.Bool("dry-run", false, "only print the diff, nothing is written")
+	fingerprint := flags.String("fingerprint", "", "fingerprint of a dry run the diff must still match")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		return errUsage
+	}
+
+	data, err := readInput(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	table, err := translation.ParseCSV(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	database, err := openDatabase()
+	if err != nil {
+		return err
+	}
+
+	diff, err := newCatalogStore(database).ImportCSV(*namespace, table, *author, *dryRun, *fingerprint)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range diff.Added {
+		fmt.Printf("+ %s %s: %s\n", strconv.Quote(change.Key), change.Locale, strconv.Quote(change.Imported))
+	}
+	for _, change := range diff.Changed {
+		fmt.Printf("~ %s %s: %s -> %s\n", strconv.Quote(change.Key), change.Locale, strconv.Quote(change.Current), strconv.Quote(change.Imported))
+	}
+	for _, change := range diff.Removed {
+		fmt.Printf("- %s %s: %s\n", strconv.Quote(change.Key), change.Locale, strconv.Quote(change.Current))
+	}
+	fmt.Printf("%d added, %d changed, %d removed, %d unchanged\n", len(diff.Added), len(diff.Changed), len(diff.Removed), len(diff.Unchanged))
+	if *dryRun {
+		fmt.Printf("dry run, nothing was written, apply it with -fingerprint %s\n", diff.Fingerprint)
+	}
+
+	return nil
+}
+
+func csvExport(args []string) error {
+	flags := flag.NewFlagSet("csv export", flag.ContinueOnError)
+	namespace := flags.String("namespace", translation.DefaultNamespace, "namespace to export")
+	locales := flags.String("locales", "", "comma separated locale columns, defaults to the locales of the namespace")
+	output := flags.String("o", "-", "output file")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+	if flags.NArg() != 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return errUsage
+	}
+
+	var columns []translation.Locale
+	if *locales != "" {
+		for _, code := range strings.Split(*locales, ",") {
+			columns = append(columns, translation.Locale(strings.TrimSpace(code)))
+		}
+	}
+
+	database, err := openDatabase()
+	if err != nil {
+		return err
+	}
+
+	table, err := newCatalogStore(database).ExportCSV(*namespace, columns)
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	if err := translation.WriteCSV(&body, table); err != nil {
+		return err
+	}
+
+	return writeOutput(*output, body.Bytes())
+}
+
+func printReport(report *translation.ImportReport) {
+	for _, skipped := range report.Skipped {
+		fmt.Printf("skipped %s: %s\n", strconv.Quote(skipped.Key), skipped.Reason)
+	}
+	for _, conflict := range report.Conflicts {
+		fmt.Printf("conflict %s: changed to %s since the export, %s not imported\n", strconv.Quote(conflict.Key), strconv.Quote(conflict.Current), strconv.Quote(conflict.Imported))
+	}
+	fmt.Printf("%d created, %d updated, %d skipped, %d conflicts\n", len(report.Created), len(report.Updated), len(report.Skipped), len(report.Conflicts))
+}
+
+// newCatalogStore enforces the review of the servers, which would otherwise be
+// bypassed by importing through the CLI.
+func newCatalogStore(database *gorm.DB) translation.CatalogStore {
+	store := translation.NewCatalogStore(database)
+	if os.Getenv("REQUIRE_REVIEW") == "true" {
+		store = translation.RequireReviewedImports(store)
+	}
+	return store
+}
+
+func openDatabase() (*gorm.DB, error) {
+	// Keep stdout free for exported catalogs.
+	databaseLogger := logger.New(log.New(os.Stderr, "", log.LstdFlags), logger.Config{
+		SlowThreshold: time.Second,
+		LogLevel:      logger.Warn,
+	})
+
+	database, err := gorm.Open(postgres.Open(os.Getenv("DATABASE_URL")), &gorm.Config{Logger: databaseLogger})
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to database: %w", err)
+	}
+	return database, nil
+}
+
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+func writeOutput(path string, data []byte) error {
+	if path == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log/slog"
 	"net"
 	"os"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/henok321/translation-service/api/handlers"
 	apiv1 "github.com/henok321/translation-service/gen/go/translation/v1"
+	"github.com/henok321/translation-service/pkg/translation"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/health/grpc_health_v1"
@@ -72,22 +74,44 @@ func main() {
 
 	stopHealth := SetupHealthServer(healthServer, database)
 
-	grpcServer := SetupGRPCServer(database, healthServer, lis)
+	listenerCtx, stopListener := context.WithCancel(context.Background())
+	defer stopListener()
+
+	repo := translation.NewCachedRepository(translation.NewRepository(database))
+	feed := translation.NewChangeFeed(database)
+	namespaces := translation.NewCachedNamespaceRegistry(translation.NewNamespaceRegistry(database))
+	listener := translation.NewChangeListener(database)
+	listener.AddHandler(repo)
+	listener.AddHandler(feed)
+	listener.AddHandler(namespaces)
+	go listener.Run(listenerCtx)
+
+	// With REQUIRE_REVIEW values only go live through reviewed drafts.
+	var handlerRepo translation.Repository = repo
+	if os.Getenv("REQUIRE_REVIEW") == "true" {
+		handlerRepo = translation.RequireReview(repo)
+	}
+
+	grpcServer := SetupGRPCServer(handlers.NewTranslationGRPCHandler(handlerRepo, translation.NewLocaleRegistry(database), namespaces, translation.NewReleaseStore(database), translation.NewRevisionStore(database), translation.NewDraftStore(database), translation.NewSearchStore(database), feed), healthServer, lis)
 
 	<-sigChan
 	slog.Info("Shutdown signal received, shutting down gracefully...")
+	slog.Info("Translation cache statistics", "stats", repo.Stats())
 
 	healthServer.Shutdown()
 	close(stopHealth)
 
+	// Watch streams never end on their own and would block the graceful stop.
+	feed.Close()
+
 	grpcServer.GracefulStop()
 
 	slog.Info("Servers exited")
 }
 
-func SetupGRPCServer(database *gorm.DB, healthServer *health.Server, lis net.Listener) *grpc.Server {
+func SetupGRPCServer(translationServer apiv1.TranslationServiceServer, healthServer *health.Server, lis net.Listener) *grpc.Server {
 	grpcServer := grpc.NewServer()
-	apiv1.RegisterTranslationServiceServer(grpcServer, handlers.NewTranslationGRPCHandler(database))
+	apiv1.RegisterTranslationServiceServer(grpcServer, translationServer)
 	grpc_health_v1.RegisterHealthServer(grpcServer, healthServer)
 
 	reflection.Register(grpcServer)
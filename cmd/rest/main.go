@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"log/slog"
 	"net/http"
 	"os"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/henok321/translation-service/api/handlers"
+	"github.com/henok321/translation-service/pkg/translation"
 	"github.com/rs/cors"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -48,15 +50,50 @@ func main() {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
-	router := handlers.SetupRouter(database)
+	listenerCtx, stopListener := context.WithCancel(context.Background())
+	defer stopListener()
+
+	repo := translation.NewCachedRepository(translation.NewRepository(database))
+	feed := translation.NewChangeFeed(database)
+	namespaces := translation.NewCachedNamespaceRegistry(translation.NewNamespaceRegistry(database))
+	listener := translation.NewChangeListener(database)
+	listener.AddHandler(repo)
+	listener.AddHandler(feed)
+	listener.AddHandler(namespaces)
+	go listener.Run(listenerCtx)
+
+	// With REQUIRE_REVIEW values only go live through reviewed drafts.
+	var handlerRepo translation.Repository = repo
+	catalogs := translation.NewCatalogStore(database)
+	if os.Getenv("REQUIRE_REVIEW") == "true" {
+		handlerRepo = translation.RequireReview(repo)
+		catalogs = translation.RequireReviewedImports(catalogs)
+	}
+
+	router := handlers.SetupRouter(handlerRepo, translation.NewLocaleRegistry(database), namespaces, translation.NewReleaseStore(database), translation.NewRevisionStore(database), translation.NewDraftStore(database), catalogs, translation.NewSearchStore(database), feed, handlers.RESTConfig{
+		CacheControl: os.Getenv("CACHE_CONTROL"),
+	})
+
+	mux := http.NewServeMux()
+	// Only the cache counters are exposed, unlike expvar, which also serves the
+	// command line and the memory statistics of the process.
+	mux.HandleFunc("GET /debug/cache", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(repo.Stats()); err != nil {
+			slog.Error("Writing cache statistics failed", "error", err)
+		}
+	})
+	mux.Handle("/", router)
 
 	server := &http.Server{
 		Addr:         ":8080",
-		Handler:      cors.AllowAll().Handler(router),
+		Handler:      cors.AllowAll().Handler(mux),
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  15 * time.Second,
 	}
+	// Event streams never end on their own and would block the shutdown.
+	server.RegisterOnShutdown(feed.Close)
 
 	go func() {
 		slog.Info("Starting server", "address", ":8080")
@@ -0,0 +1,20 @@
+-- +goose Up
+
+-- Locales become data instead of an enum type, so the enum has to go before
+-- the table of the same name can be created.
+ALTER TABLE translation ALTER COLUMN locale TYPE text USING locale::text;
+
+DROP TYPE locale;
+
+CREATE TABLE locale
+(
+    code text PRIMARY KEY,
+    enabled boolean NOT NULL DEFAULT TRUE,
+    created_at timestamp with time zone NOT NULL DEFAULT NOW(),
+    updated_at timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO locale (code) VALUES ('de_DE'), ('en_GB');
+
+ALTER TABLE translation
+ADD CONSTRAINT translation_locale_fkey FOREIGN KEY (locale) REFERENCES locale (code);
@@ -0,0 +1,4 @@
+-- +goose Up
+
+ALTER TABLE locale
+ADD COLUMN fallback text REFERENCES locale (code);
@@ -0,0 +1,40 @@
+-- +goose Up
+
+-- Publishes every change of the translation table on the translation_changed
+-- channel so that caches of all replicas can be invalidated.
+-- +goose StatementBegin
+CREATE FUNCTION notify_translation_change() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'TRUNCATE' THEN
+        PERFORM pg_notify('translation_changed', json_build_object('operation', TG_OP)::text);
+        RETURN NULL;
+    END IF;
+
+    IF TG_OP = 'INSERT' OR TG_OP = 'UPDATE' THEN
+        PERFORM pg_notify('translation_changed', json_build_object(
+            'operation', TG_OP,
+            'locale', NEW.locale,
+            'language_key', NEW.language_key
+        )::text);
+    END IF;
+
+    IF TG_OP = 'DELETE' OR (TG_OP = 'UPDATE' AND (OLD.locale, OLD.language_key) IS DISTINCT FROM (NEW.locale, NEW.language_key)) THEN
+        PERFORM pg_notify('translation_changed', json_build_object(
+            'operation', 'DELETE',
+            'locale', OLD.locale,
+            'language_key', OLD.language_key
+        )::text);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER translation_notify
+AFTER INSERT OR UPDATE OR DELETE ON translation
+FOR EACH ROW EXECUTE FUNCTION notify_translation_change();
+
+CREATE TRIGGER translation_notify_truncate
+AFTER TRUNCATE ON translation
+FOR EACH STATEMENT EXECUTE FUNCTION notify_translation_change();
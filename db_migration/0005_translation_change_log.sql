@@ -0,0 +1,78 @@
+-- +goose Up
+
+-- Every change of the translation table is recorded with a version from a
+-- global sequence. Deleted translations stay as tombstones, so clients can
+-- resume from the last version they have seen.
+CREATE TABLE translation_change
+(
+    version bigserial PRIMARY KEY,
+    operation text NOT NULL,
+    locale text NOT NULL,
+    language_key text NOT NULL,
+    translation text,
+    changed_at timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX translation_change_locale_version ON translation_change (locale, version);
+
+-- Seeds the log with the current state, so every existing translation has a
+-- version.
+INSERT INTO translation_change (operation, locale, language_key, translation)
+SELECT 'INSERT', locale, language_key, translation FROM translation ORDER BY id;
+
+-- +goose StatementBegin
+CREATE FUNCTION record_translation_change(change_operation text, change_locale text, change_language_key text, change_translation text) RETURNS void AS $$
+DECLARE
+    change_version bigint;
+BEGIN
+    INSERT INTO translation_change (operation, locale, language_key, translation)
+    VALUES (change_operation, change_locale, change_language_key, change_translation)
+    RETURNING version INTO change_version;
+
+    PERFORM pg_notify('translation_changed', json_build_object(
+        'operation', change_operation,
+        'locale', change_locale,
+        'language_key', change_language_key,
+        'version', change_version
+    )::text);
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_translation_change() RETURNS trigger AS $$
+DECLARE
+    deleted record;
+BEGIN
+    -- Writers are serialized until they commit, so versions become visible in
+    -- ascending order and a reader never skips a version committed later.
+    PERFORM pg_advisory_xact_lock(hashtext('translation_change'));
+
+    IF TG_OP = 'TRUNCATE' THEN
+        FOR deleted IN SELECT locale, language_key FROM translation LOOP
+            PERFORM record_translation_change('DELETE', deleted.locale, deleted.language_key, NULL);
+        END LOOP;
+        RETURN NULL;
+    END IF;
+
+    IF TG_OP = 'DELETE' OR (TG_OP = 'UPDATE' AND (OLD.locale, OLD.language_key) IS DISTINCT FROM (NEW.locale, NEW.language_key)) THEN
+        PERFORM record_translation_change('DELETE', OLD.locale, OLD.language_key, NULL);
+    END IF;
+
+    IF TG_OP = 'INSERT' OR (TG_OP = 'UPDATE' AND (OLD.locale, OLD.language_key) IS DISTINCT FROM (NEW.locale, NEW.language_key)) THEN
+        PERFORM record_translation_change('INSERT', NEW.locale, NEW.language_key, NEW.translation);
+    ELSIF TG_OP = 'UPDATE' THEN
+        PERFORM record_translation_change('UPDATE', NEW.locale, NEW.language_key, NEW.translation);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- Truncated rows are only visible before the truncation.
+DROP TRIGGER translation_notify_truncate ON translation;
+
+CREATE TRIGGER translation_notify_truncate
+BEFORE TRUNCATE ON translation
+FOR EACH STATEMENT EXECUTE FUNCTION notify_translation_change();
@@ -0,0 +1,30 @@
+-- +goose Up
+
+-- A release is an immutable snapshot of all translations. Readers either pin
+-- a release by its number or follow the latest tag, which a rollback moves
+-- back to an earlier release.
+CREATE TABLE release
+(
+    id serial PRIMARY KEY,
+    description text NOT NULL DEFAULT '',
+    created_at timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE release_translation
+(
+    release_id integer NOT NULL REFERENCES release (id),
+    id integer NOT NULL,
+    language_key text NOT NULL,
+    locale text NOT NULL,
+    translation text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (release_id, language_key, locale)
+);
+
+CREATE TABLE release_tag
+(
+    name text PRIMARY KEY,
+    release_id integer NOT NULL REFERENCES release (id),
+    updated_at timestamp with time zone NOT NULL DEFAULT NOW()
+);
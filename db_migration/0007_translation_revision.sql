@@ -0,0 +1,60 @@
+-- +goose Up
+
+-- Every value a translation ever had, including deletions with a NULL
+-- translation. Writers pass their name in the transaction setting
+-- translation.changed_by to be recorded as author.
+CREATE TABLE translation_revision
+(
+    id bigserial PRIMARY KEY,
+    language_key text NOT NULL,
+    locale text NOT NULL,
+    operation text NOT NULL,
+    translation text,
+    changed_by text,
+    changed_at timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX translation_revision_language_key_locale ON translation_revision (language_key, locale, id);
+
+INSERT INTO translation_revision (language_key, locale, operation, translation, changed_at)
+SELECT language_key, locale, 'INSERT', translation, updated_at FROM translation ORDER BY id;
+
+-- +goose StatementBegin
+CREATE FUNCTION record_translation_revision() RETURNS trigger AS $$
+DECLARE
+    author text := NULLIF(current_setting('translation.changed_by', true), '');
+    deleted record;
+BEGIN
+    IF TG_OP = 'TRUNCATE' THEN
+        FOR deleted IN SELECT locale, language_key FROM translation LOOP
+            INSERT INTO translation_revision (language_key, locale, operation, changed_by)
+            VALUES (deleted.language_key, deleted.locale, 'DELETE', author);
+        END LOOP;
+        RETURN NULL;
+    END IF;
+
+    IF TG_OP = 'DELETE' OR (TG_OP = 'UPDATE' AND (OLD.locale, OLD.language_key) IS DISTINCT FROM (NEW.locale, NEW.language_key)) THEN
+        INSERT INTO translation_revision (language_key, locale, operation, changed_by)
+        VALUES (OLD.language_key, OLD.locale, 'DELETE', author);
+    END IF;
+
+    IF TG_OP = 'INSERT' OR (TG_OP = 'UPDATE' AND (OLD.locale, OLD.language_key) IS DISTINCT FROM (NEW.locale, NEW.language_key)) THEN
+        INSERT INTO translation_revision (language_key, locale, operation, translation, changed_by)
+        VALUES (NEW.language_key, NEW.locale, 'INSERT', NEW.translation, author);
+    ELSIF TG_OP = 'UPDATE' THEN
+        INSERT INTO translation_revision (language_key, locale, operation, translation, changed_by)
+        VALUES (NEW.language_key, NEW.locale, 'UPDATE', NEW.translation, author);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER translation_revision
+AFTER INSERT OR UPDATE OR DELETE ON translation
+FOR EACH ROW EXECUTE FUNCTION record_translation_revision();
+
+CREATE TRIGGER translation_revision_truncate
+BEFORE TRUNCATE ON translation
+FOR EACH STATEMENT EXECUTE FUNCTION record_translation_revision();
@@ -0,0 +1,21 @@
+-- +goose Up
+
+-- Work in progress on a translation. The translation table keeps serving the
+-- published value until an approved draft is published, which moves its text
+-- into the translation table and removes the draft.
+CREATE TABLE translation_draft
+(
+    id bigserial PRIMARY KEY,
+    language_key text NOT NULL,
+    locale text NOT NULL,
+    translation text NOT NULL,
+    status text NOT NULL DEFAULT 'draft',
+    comment text,
+    changed_by text,
+    reviewed_by text,
+    created_at timestamp with time zone NOT NULL DEFAULT NOW(),
+    updated_at timestamp with time zone NOT NULL DEFAULT NOW(),
+    CONSTRAINT translation_draft_unique_key UNIQUE (language_key, locale),
+    CONSTRAINT translation_draft_locale_fkey FOREIGN KEY (locale) REFERENCES locale (code),
+    CONSTRAINT translation_draft_status_check CHECK (status IN ('draft', 'in_review', 'approved'))
+);
@@ -0,0 +1,137 @@
+-- +goose Up
+
+-- Namespaces partition the translation keys of different projects. Existing
+-- translations move to the default namespace. A namespace without locales
+-- enables every enabled locale, one without a default locale falls back to the
+-- service default.
+CREATE TABLE namespace
+(
+    name text PRIMARY KEY,
+    description text NOT NULL DEFAULT '',
+    default_locale text REFERENCES locale (code),
+    created_at timestamp with time zone NOT NULL DEFAULT NOW(),
+    updated_at timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE namespace_locale
+(
+    namespace text NOT NULL REFERENCES namespace (name) ON DELETE CASCADE,
+    locale text NOT NULL REFERENCES locale (code),
+    PRIMARY KEY (namespace, locale)
+);
+
+INSERT INTO namespace (name) VALUES ('default');
+
+ALTER TABLE translation
+ADD COLUMN namespace text NOT NULL DEFAULT 'default',
+ADD CONSTRAINT translation_namespace_fkey FOREIGN KEY (namespace) REFERENCES namespace (name),
+DROP CONSTRAINT translation_unique_key,
+ADD CONSTRAINT translation_unique_key UNIQUE (namespace, language_key, locale);
+
+ALTER TABLE translation_draft
+ADD COLUMN namespace text NOT NULL DEFAULT 'default',
+ADD CONSTRAINT translation_draft_namespace_fkey FOREIGN KEY (namespace) REFERENCES namespace (name),
+DROP CONSTRAINT translation_draft_unique_key,
+ADD CONSTRAINT translation_draft_unique_key UNIQUE (namespace, language_key, locale);
+
+ALTER TABLE release_translation
+ADD COLUMN namespace text NOT NULL DEFAULT 'default',
+DROP CONSTRAINT release_translation_pkey,
+ADD PRIMARY KEY (release_id, namespace, language_key, locale);
+
+ALTER TABLE translation_change ADD COLUMN namespace text NOT NULL DEFAULT 'default';
+
+DROP INDEX translation_change_locale_version;
+
+CREATE INDEX translation_change_namespace_locale_version ON translation_change (namespace, locale, version);
+
+ALTER TABLE translation_revision ADD COLUMN namespace text NOT NULL DEFAULT 'default';
+
+DROP INDEX translation_revision_language_key_locale;
+
+CREATE INDEX translation_revision_namespace_language_key_locale ON translation_revision (namespace, language_key, locale, id);
+
+DROP FUNCTION record_translation_change(text, text, text, text);
+
+-- +goose StatementBegin
+CREATE FUNCTION record_translation_change(change_operation text, change_namespace text, change_locale text, change_language_key text, change_translation text) RETURNS void AS $$
+DECLARE
+    change_version bigint;
+BEGIN
+    INSERT INTO translation_change (operation, namespace, locale, language_key, translation)
+    VALUES (change_operation, change_namespace, change_locale, change_language_key, change_translation)
+    RETURNING version INTO change_version;
+
+    PERFORM pg_notify('translation_changed', json_build_object(
+        'operation', change_operation,
+        'namespace', change_namespace,
+        'locale', change_locale,
+        'language_key', change_language_key,
+        'version', change_version
+    )::text);
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_translation_change() RETURNS trigger AS $$
+DECLARE
+    deleted record;
+BEGIN
+    -- Writers are serialized until they commit, so versions become visible in
+    -- ascending order and a reader never skips a version committed later.
+    PERFORM pg_advisory_xact_lock(hashtext('translation_change'));
+
+    IF TG_OP = 'TRUNCATE' THEN
+        FOR deleted IN SELECT namespace, locale, language_key FROM translation LOOP
+            PERFORM record_translation_change('DELETE', deleted.namespace, deleted.locale, deleted.language_key, NULL);
+        END LOOP;
+        RETURN NULL;
+    END IF;
+
+    IF TG_OP = 'DELETE' OR (TG_OP = 'UPDATE' AND (OLD.namespace, OLD.locale, OLD.language_key) IS DISTINCT FROM (NEW.namespace, NEW.locale, NEW.language_key)) THEN
+        PERFORM record_translation_change('DELETE', OLD.namespace, OLD.locale, OLD.language_key, NULL);
+    END IF;
+
+    IF TG_OP = 'INSERT' OR (TG_OP = 'UPDATE' AND (OLD.namespace, OLD.locale, OLD.language_key) IS DISTINCT FROM (NEW.namespace, NEW.locale, NEW.language_key)) THEN
+        PERFORM record_translation_change('INSERT', NEW.namespace, NEW.locale, NEW.language_key, NEW.translation);
+    ELSIF TG_OP = 'UPDATE' THEN
+        PERFORM record_translation_change('UPDATE', NEW.namespace, NEW.locale, NEW.language_key, NEW.translation);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION record_translation_revision() RETURNS trigger AS $$
+DECLARE
+    author text := NULLIF(current_setting('translation.changed_by', true), '');
+    deleted record;
+BEGIN
+    IF TG_OP = 'TRUNCATE' THEN
+        FOR deleted IN SELECT namespace, locale, language_key FROM translation LOOP
+            INSERT INTO translation_revision (namespace, language_key, locale, operation, changed_by)
+            VALUES (deleted.namespace, deleted.language_key, deleted.locale, 'DELETE', author);
+        END LOOP;
+        RETURN NULL;
+    END IF;
+
+    IF TG_OP = 'DELETE' OR (TG_OP = 'UPDATE' AND (OLD.namespace, OLD.locale, OLD.language_key) IS DISTINCT FROM (NEW.namespace, NEW.locale, NEW.language_key)) THEN
+        INSERT INTO translation_revision (namespace, language_key, locale, operation, changed_by)
+        VALUES (OLD.namespace, OLD.language_key, OLD.locale, 'DELETE', author);
+    END IF;
+
+    IF TG_OP = 'INSERT' OR (TG_OP = 'UPDATE' AND (OLD.namespace, OLD.locale, OLD.language_key) IS DISTINCT FROM (NEW.namespace, NEW.locale, NEW.language_key)) THEN
+        INSERT INTO translation_revision (namespace, language_key, locale, operation, translation, changed_by)
+        VALUES (NEW.namespace, NEW.language_key, NEW.locale, 'INSERT', NEW.translation, author);
+    ELSIF TG_OP = 'UPDATE' THEN
+        INSERT INTO translation_revision (namespace, language_key, locale, operation, translation, changed_by)
+        VALUES (NEW.namespace, NEW.language_key, NEW.locale, 'UPDATE', NEW.translation, author);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
@@ -0,0 +1,45 @@
+-- +goose Up
+
+-- Plural variants of a translation by CLDR plural category, e.g.
+-- {"one": "# item", "other": "# items"}. The translation column keeps the
+-- "other" form, so readers unaware of plurals still get a usable text. NULL
+-- for translations without plural variants.
+ALTER TABLE translation ADD COLUMN plural_forms jsonb;
+
+ALTER TABLE translation_draft ADD COLUMN plural_forms jsonb;
+
+ALTER TABLE release_translation ADD COLUMN plural_forms jsonb;
+
+ALTER TABLE translation_revision ADD COLUMN plural_forms jsonb;
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION record_translation_revision() RETURNS trigger AS $$
+DECLARE
+    author text := NULLIF(current_setting('translation.changed_by', true), '');
+    deleted record;
+BEGIN
+    IF TG_OP = 'TRUNCATE' THEN
+        FOR deleted IN SELECT namespace, locale, language_key FROM translation LOOP
+            INSERT INTO translation_revision (namespace, language_key, locale, operation, changed_by)
+            VALUES (deleted.namespace, deleted.language_key, deleted.locale, 'DELETE', author);
+        END LOOP;
+        RETURN NULL;
+    END IF;
+
+    IF TG_OP = 'DELETE' OR (TG_OP = 'UPDATE' AND (OLD.namespace, OLD.locale, OLD.language_key) IS DISTINCT FROM (NEW.namespace, NEW.locale, NEW.language_key)) THEN
+        INSERT INTO translation_revision (namespace, language_key, locale, operation, changed_by)
+        VALUES (OLD.namespace, OLD.language_key, OLD.locale, 'DELETE', author);
+    END IF;
+
+    IF TG_OP = 'INSERT' OR (TG_OP = 'UPDATE' AND (OLD.namespace, OLD.locale, OLD.language_key) IS DISTINCT FROM (NEW.namespace, NEW.locale, NEW.language_key)) THEN
+        INSERT INTO translation_revision (namespace, language_key, locale, operation, translation, plural_forms, changed_by)
+        VALUES (NEW.namespace, NEW.language_key, NEW.locale, 'INSERT', NEW.translation, NEW.plural_forms, author);
+    ELSIF TG_OP = 'UPDATE' THEN
+        INSERT INTO translation_revision (namespace, language_key, locale, operation, translation, plural_forms, changed_by)
+        VALUES (NEW.namespace, NEW.language_key, NEW.locale, 'UPDATE', NEW.translation, NEW.plural_forms, author);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+
+-- Translator comment and fuzzy flag of gettext catalogs. A fuzzy translation
+-- is a guess awaiting review, it is served like any other but left out of
+-- compiled MO files. Editing the text through the service clears the flag.
+ALTER TABLE translation ADD COLUMN comment text NOT NULL DEFAULT '';
+ALTER TABLE translation ADD COLUMN fuzzy boolean NOT NULL DEFAULT false;
+
+ALTER TABLE release_translation ADD COLUMN comment text NOT NULL DEFAULT '';
+ALTER TABLE release_translation ADD COLUMN fuzzy boolean NOT NULL DEFAULT false;
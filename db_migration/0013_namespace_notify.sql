@@ -0,0 +1,33 @@
+-- +goose Up
+
+-- Publishes every change of the namespace settings on the translation_changed
+-- channel so that the namespace caches of all replicas can be invalidated.
+-- The first trigger argument names the column holding the namespace.
+-- +goose StatementBegin
+CREATE FUNCTION notify_namespace_change() RETURNS trigger AS $$
+DECLARE
+    changed record;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed := OLD;
+    ELSE
+        changed := NEW;
+    END IF;
+
+    PERFORM pg_notify('translation_changed', json_build_object(
+        'operation', 'NAMESPACE',
+        'namespace', to_jsonb(changed) ->> TG_ARGV[0]
+    )::text);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER namespace_notify
+AFTER INSERT OR UPDATE OR DELETE ON namespace
+FOR EACH ROW EXECUTE FUNCTION notify_namespace_change('name');
+
+CREATE TRIGGER namespace_locale_notify
+AFTER INSERT OR UPDATE OR DELETE ON namespace_locale
+FOR EACH ROW EXECUTE FUNCTION notify_namespace_change('namespace');
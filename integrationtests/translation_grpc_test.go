@@ -3,18 +3,25 @@ package integrationtests
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"log/slog"
 	"net"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/henok321/translation-service/api/handlers"
 	apiv1 "github.com/henok321/translation-service/gen/go/translation/v1"
+	"github.com/henok321/translation-service/pkg/translation"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
 	pg "gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -33,8 +40,18 @@ func setupTestGRPCServer() (client apiv1.TranslationServiceClient, teardown func
 		os.Exit(1)
 	}
 
+	ctx, stopListener := context.WithCancel(context.Background())
+	repo := translation.NewCachedRepository(translation.NewRepository(database))
+	feed := translation.NewChangeFeed(database)
+	namespaces := translation.NewCachedNamespaceRegistry(translation.NewNamespaceRegistry(database))
+	listener := translation.NewChangeListener(database)
+	listener.AddHandler(repo)
+	listener.AddHandler(feed)
+	listener.AddHandler(namespaces)
+	go listener.Run(ctx)
+
 	grpcServer := grpc.NewServer()
-	apiv1.RegisterTranslationServiceServer(grpcServer, handlers.NewTranslationGRPCHandler(database))
+	apiv1.RegisterTranslationServiceServer(grpcServer, handlers.NewTranslationGRPCHandler(repo, translation.NewLocaleRegistry(database), namespaces, translation.NewReleaseStore(database), translation.NewRevisionStore(database), translation.NewDraftStore(database), translation.NewSearchStore(database), feed))
 
 	go func() {
 		if err := grpcServer.Serve(lis); err != nil {
@@ -56,7 +73,9 @@ func setupTestGRPCServer() (client apiv1.TranslationServiceClient, teardown func
 		if err != nil {
 			slog.Error("Closing connection failed", "error", err)
 		}
+		feed.Close()
 		grpcServer.GracefulStop()
+		stopListener()
 	}
 
 	return client, teardown
@@ -83,22 +102,22 @@ func TestTranslationGRPC(t *testing.T) {
 
 	testCases := map[string]struct {
 		languageKey string
-		locale      apiv1.Locale
+		locale      string
 		expectedErr codes.Code
 	}{
 		"valid translation": {
 			languageKey: "test_lk_0",
-			locale:      apiv1.Locale_LOCALE_EN_GB,
+			locale:      "en_GB",
 			expectedErr: codes.OK,
 		},
 		"unknown translation": {
 			languageKey: "invalid_key",
-			locale:      apiv1.Locale_LOCALE_EN_GB,
+			locale:      "en_GB",
 			expectedErr: codes.NotFound,
 		},
 		"invalid language key": {
 			languageKey: "",
-			locale:      apiv1.Locale_LOCALE_DE_DE,
+			locale:      "de_DE",
 			expectedErr: codes.InvalidArgument,
 		},
 	}
@@ -123,3 +142,1190 @@ func TestTranslationGRPC(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslationGRPCWrite(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	client, teardownServer := setupTestGRPCServer()
+
+	defer teardownServer()
+
+	ctx := context.Background()
+
+	t.Run("create translation", func(t *testing.T) {
+		result, err := client.CreateTranslation(ctx, &apiv1.CreateTranslationRequest{
+			LanguageKey: "test_lk_new",
+			Locale:      "en_GB",
+			Translation: "New one",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "test_lk_new", result.GetTranslation().GetLanguageKey())
+		assert.Equal(t, "New one", result.GetTranslation().GetTranslation())
+	})
+
+	createErrors := map[string]struct {
+		languageKey string
+		locale      string
+		expectedErr codes.Code
+	}{
+		"duplicate translation": {
+			languageKey: "test_lk_0",
+			locale:      "en_GB",
+			expectedErr: codes.AlreadyExists,
+		},
+		"missing language key": {
+			languageKey: "",
+			locale:      "en_GB",
+			expectedErr: codes.InvalidArgument,
+		},
+		"missing locale": {
+			languageKey: "test_lk_new",
+			locale:      "",
+			expectedErr: codes.InvalidArgument,
+		},
+	}
+
+	for name, tc := range createErrors {
+		t.Run(name, func(t *testing.T) {
+			_, err := client.CreateTranslation(ctx, &apiv1.CreateTranslationRequest{
+				LanguageKey: tc.languageKey,
+				Locale:      tc.locale,
+				Translation: "value",
+			})
+			assert.Equal(t, tc.expectedErr, status.Code(err))
+		})
+	}
+
+	t.Run("update translation", func(t *testing.T) {
+		result, err := client.UpdateTranslation(ctx, &apiv1.UpdateTranslationRequest{
+			LanguageKey: "test_lk_1",
+			Locale:      "en_GB",
+			Translation: "Updated one",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Updated one", result.GetTranslation().GetTranslation())
+
+		stored, err := client.GetTranslationByKeyAndLocale(ctx, &apiv1.GetTranslationByKeyAndLocaleRequest{
+			LanguageKey: "test_lk_1",
+			Locale:      "en_GB",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Updated one", stored.GetTranslation().GetTranslation())
+	})
+
+	t.Run("update unknown translation", func(t *testing.T) {
+		_, err := client.UpdateTranslation(ctx, &apiv1.UpdateTranslationRequest{
+			LanguageKey: "invalid_key",
+			Locale:      "en_GB",
+			Translation: "value",
+		})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("delete translation", func(t *testing.T) {
+		_, err := client.DeleteTranslation(ctx, &apiv1.DeleteTranslationRequest{
+			LanguageKey: "test_lk_2",
+			Locale:      "de_DE",
+		})
+		require.NoError(t, err)
+
+		_, err = client.DeleteTranslation(ctx, &apiv1.DeleteTranslationRequest{
+			LanguageKey: "test_lk_2",
+			Locale:      "de_DE",
+		})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestLocaleGRPC(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	client, teardownServer := setupTestGRPCServer()
+
+	defer teardownServer()
+
+	ctx := context.Background()
+
+	t.Run("seeded locales", func(t *testing.T) {
+		result, err := client.ListLocales(ctx, &apiv1.ListLocalesRequest{})
+		require.NoError(t, err)
+
+		localeCodes := []string{}
+		for _, locale := range result.GetLocales() {
+			localeCodes = append(localeCodes, locale.GetCode())
+		}
+		assert.Equal(t, []string{"de_DE", "en_GB"}, localeCodes)
+	})
+
+	t.Run("add locale", func(t *testing.T) {
+		result, err := client.AddLocale(ctx, &apiv1.AddLocaleRequest{Code: "fr_FR"})
+		require.NoError(t, err)
+		assert.True(t, result.GetLocale().GetEnabled())
+
+		_, err = client.CreateTranslation(ctx, &apiv1.CreateTranslationRequest{
+			LanguageKey: "test_lk_0",
+			Locale:      "fr_FR",
+			Translation: "Service de traduction",
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("add duplicate locale", func(t *testing.T) {
+		_, err := client.AddLocale(ctx, &apiv1.AddLocaleRequest{Code: "fr_FR"})
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+
+	t.Run("add malformed locale", func(t *testing.T) {
+		_, err := client.AddLocale(ctx, &apiv1.AddLocaleRequest{Code: "fr-fr"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("disable locale", func(t *testing.T) {
+		result, err := client.UpdateLocale(ctx, &apiv1.UpdateLocaleRequest{Code: "fr_FR", Enabled: proto.Bool(false)})
+		require.NoError(t, err)
+		assert.False(t, result.GetLocale().GetEnabled())
+
+		_, err = client.GetTranslationByKeyAndLocale(ctx, &apiv1.GetTranslationByKeyAndLocaleRequest{
+			LanguageKey: "test_lk_0",
+			Locale:      "fr_FR",
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		all, err := client.ListLocales(ctx, &apiv1.ListLocalesRequest{IncludeDisabled: true})
+		require.NoError(t, err)
+		assert.Len(t, all.GetLocales(), 3)
+	})
+
+	t.Run("update unknown locale", func(t *testing.T) {
+		_, err := client.UpdateLocale(ctx, &apiv1.UpdateLocaleRequest{Code: "ja_JP", Enabled: proto.Bool(true)})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestFallbackGRPC(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	client, teardownServer := setupTestGRPCServer()
+
+	defer teardownServer()
+
+	ctx := context.Background()
+
+	_, err = client.AddLocale(ctx, &apiv1.AddLocaleRequest{Code: "de_AT", Fallback: "de_DE"})
+	require.NoError(t, err)
+
+	_, err = client.UpdateLocale(ctx, &apiv1.UpdateLocaleRequest{Code: "de_DE", Fallback: proto.String("en_GB")})
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		languageKey    string
+		resolvedLocale string
+		translation    string
+	}{
+		"served by fallback": {
+			languageKey:    "test_lk_2",
+			resolvedLocale: "de_DE",
+			translation:    "Noch einer",
+		},
+		"served by last fallback": {
+			languageKey:    "test_lk_1",
+			resolvedLocale: "en_GB",
+			translation:    "Another one",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.GetTranslationByKeyAndLocale(ctx, &apiv1.GetTranslationByKeyAndLocaleRequest{
+				LanguageKey: tc.languageKey,
+				Locale:      "de_AT",
+			})
+			require.NoError(t, err)
+			assert.Equal(t, "de_AT", result.GetTranslation().GetLocale())
+			assert.Equal(t, tc.resolvedLocale, result.GetTranslation().GetResolvedLocale())
+			assert.Equal(t, tc.translation, result.GetTranslation().GetTranslation())
+		})
+	}
+
+	t.Run("fallback cycle", func(t *testing.T) {
+		_, err := client.UpdateLocale(ctx, &apiv1.UpdateLocaleRequest{Code: "en_GB", Fallback: proto.String("de_AT")})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("unknown fallback", func(t *testing.T) {
+		_, err := client.AddLocale(ctx, &apiv1.AddLocaleRequest{Code: "fr_FR", Fallback: "fr_BE"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestListTranslationsGRPC(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	client, teardownServer := setupTestGRPCServer()
+
+	defer teardownServer()
+
+	ctx := context.Background()
+
+	t.Run("list translations", func(t *testing.T) {
+		result, err := client.ListTranslations(ctx, &apiv1.ListTranslationsRequest{Locale: "en_GB"})
+		require.NoError(t, err)
+		assert.Len(t, result.GetTranslations().GetTranslations(), 2)
+	})
+
+	t.Run("paginate translations", func(t *testing.T) {
+		keys := []string{}
+		pageToken := ""
+
+		for {
+			result, err := client.ListTranslations(ctx, &apiv1.ListTranslationsRequest{
+				Locale:    "en_GB",
+				PageSize:  1,
+				PageToken: pageToken,
+				OrderBy:   "key desc",
+			})
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(result.GetTranslations().GetTranslations()), 1)
+
+			for _, entry := range result.GetTranslations().GetTranslations() {
+				keys = append(keys, entry.GetLanguageKey())
+			}
+
+			pageToken = result.GetNextPageToken()
+			if pageToken == "" {
+				break
+			}
+		}
+
+		assert.Equal(t, []string{"test_lk_1", "test_lk_0"}, keys)
+	})
+
+	t.Run("filter translations by key prefix", func(t *testing.T) {
+		result, err := client.ListTranslations(ctx, &apiv1.ListTranslationsRequest{Locale: "de_DE", KeyPrefix: "test_lk_2"})
+		require.NoError(t, err)
+		require.Len(t, result.GetTranslations().GetTranslations(), 1)
+		assert.Equal(t, "test_lk_2", result.GetTranslations().GetTranslations()[0].GetLanguageKey())
+	})
+
+	t.Run("invalid list options", func(t *testing.T) {
+		_, err := client.ListTranslations(ctx, &apiv1.ListTranslationsRequest{Locale: "en_GB", OrderBy: "translation"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = client.ListTranslations(ctx, &apiv1.ListTranslationsRequest{Locale: "en_GB", PageToken: "garbage"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = client.ListTranslations(ctx, &apiv1.ListTranslationsRequest{Locale: "en_GB", PageSize: translation.MaxPageSize + 1})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("list translations of unsupported locale", func(t *testing.T) {
+		_, err := client.ListTranslations(ctx, &apiv1.ListTranslationsRequest{Locale: "fr_FR"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("batch get translations", func(t *testing.T) {
+		result, err := client.BatchGetTranslations(ctx, &apiv1.BatchGetTranslationsRequest{
+			LanguageKeys: []string{"test_lk_0", "test_lk_2", "invalid_key", "test_lk_1"},
+			Locale:       "en_GB",
+		})
+		require.NoError(t, err)
+
+		keys := []string{}
+		for _, entry := range result.GetTranslations() {
+			keys = append(keys, entry.GetLanguageKey())
+		}
+		assert.ElementsMatch(t, []string{"test_lk_0", "test_lk_1"}, keys)
+		assert.Equal(t, []string{"test_lk_2", "invalid_key"}, result.GetMissingKeys())
+	})
+
+	t.Run("batch get without keys", func(t *testing.T) {
+		_, err := client.BatchGetTranslations(ctx, &apiv1.BatchGetTranslationsRequest{Locale: "en_GB"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("grouped translations", func(t *testing.T) {
+		result, err := client.GetGroupedTranslations(ctx, &apiv1.GetGroupedTranslationsRequest{Locales: []string{"de_DE", "en_GB"}})
+		require.NoError(t, err)
+
+		groups := result.GetGroupedTranslations().GetGroups()
+		require.Len(t, groups, 2)
+		assert.Equal(t, "de_DE", groups[0].GetLocale())
+		assert.Len(t, groups[0].GetTranslations().GetTranslations(), 2)
+		assert.Equal(t, "en_GB", groups[1].GetLocale())
+		assert.Len(t, groups[1].GetTranslations().GetTranslations(), 2)
+	})
+
+	t.Run("grouped translations without locales", func(t *testing.T) {
+		_, err := client.GetGroupedTranslations(ctx, &apiv1.GetGroupedTranslationsRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+// groupedTranslation returns the translation of key in a locale group.
+func groupedTranslation(group *apiv1.LocaleGroup, key string) string {
+	for _, entry := range group.GetTranslations().GetTranslations() {
+		if entry.GetLanguageKey() == key {
+			return entry.GetTranslation()
+		}
+	}
+	return ""
+}
+
+func TestWatchTranslationsGRPC(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	client, teardownServer := setupTestGRPCServer()
+
+	defer teardownServer()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var resumeVersion int64
+
+	t.Run("watch translations", func(t *testing.T) {
+		stream, err := client.WatchTranslations(ctx, &apiv1.WatchTranslationsRequest{Locale: "en_GB"})
+		require.NoError(t, err)
+
+		snapshot, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Len(t, snapshot.GetSnapshot().GetTranslations(), 2)
+
+		_, err = client.UpdateTranslation(ctx, &apiv1.UpdateTranslationRequest{LanguageKey: "test_lk_0", Translation: "Updated", Locale: "en_GB"})
+		require.NoError(t, err)
+
+		// Changes of other locales are not streamed.
+		_, err = client.DeleteTranslation(ctx, &apiv1.DeleteTranslationRequest{LanguageKey: "test_lk_2", Locale: "de_DE"})
+		require.NoError(t, err)
+
+		_, err = client.DeleteTranslation(ctx, &apiv1.DeleteTranslationRequest{LanguageKey: "test_lk_1", Locale: "en_GB"})
+		require.NoError(t, err)
+
+		updated, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, apiv1.ChangeType_CHANGE_TYPE_UPDATED, updated.GetChange().GetType())
+		assert.Equal(t, "Updated", updated.GetChange().GetTranslation().GetTranslation())
+		assert.Greater(t, updated.GetVersion(), snapshot.GetVersion())
+
+		deleted, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, apiv1.ChangeType_CHANGE_TYPE_DELETED, deleted.GetChange().GetType())
+		assert.Equal(t, "test_lk_1", deleted.GetChange().GetTranslation().GetLanguageKey())
+
+		resumeVersion = updated.GetVersion()
+	})
+
+	t.Run("resume watching translations", func(t *testing.T) {
+		stream, err := client.WatchTranslations(ctx, &apiv1.WatchTranslationsRequest{Locale: "en_GB", SinceVersion: resumeVersion})
+		require.NoError(t, err)
+
+		deleted, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, apiv1.ChangeType_CHANGE_TYPE_DELETED, deleted.GetChange().GetType())
+		assert.Equal(t, "test_lk_1", deleted.GetChange().GetTranslation().GetLanguageKey())
+	})
+
+	t.Run("resume from unknown version", func(t *testing.T) {
+		stream, err := client.WatchTranslations(ctx, &apiv1.WatchTranslationsRequest{Locale: "en_GB", SinceVersion: 1 << 40})
+		require.NoError(t, err)
+
+		_, err = stream.Recv()
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("watch unsupported locale", func(t *testing.T) {
+		stream, err := client.WatchTranslations(ctx, &apiv1.WatchTranslationsRequest{Locale: "fr_FR"})
+		require.NoError(t, err)
+
+		_, err = stream.Recv()
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestGetChangesGRPC(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	client, teardownServer := setupTestGRPCServer()
+
+	defer teardownServer()
+
+	ctx := context.Background()
+
+	initial, err := client.GetChanges(ctx, &apiv1.GetChangesRequest{Locale: "de_DE"})
+	require.NoError(t, err)
+	assert.Len(t, initial.GetTranslations(), 2)
+
+	_, err = client.DeleteTranslation(ctx, &apiv1.DeleteTranslationRequest{LanguageKey: "test_lk_2", Locale: "de_DE"})
+	require.NoError(t, err)
+
+	t.Run("get changes", func(t *testing.T) {
+		result, err := client.GetChanges(ctx, &apiv1.GetChangesRequest{Locale: "de_DE", SinceVersion: initial.GetVersion()})
+		require.NoError(t, err)
+		assert.Empty(t, result.GetTranslations())
+		assert.Equal(t, []string{"test_lk_2"}, result.GetDeletedKeys())
+		assert.Greater(t, result.GetVersion(), initial.GetVersion())
+
+		unchanged, err := client.GetChanges(ctx, &apiv1.GetChangesRequest{Locale: "de_DE", SinceVersion: result.GetVersion()})
+		require.NoError(t, err)
+		assert.Empty(t, unchanged.GetTranslations())
+		assert.Empty(t, unchanged.GetDeletedKeys())
+		assert.Equal(t, result.GetVersion(), unchanged.GetVersion())
+	})
+
+	t.Run("get changes since unknown version", func(t *testing.T) {
+		_, err := client.GetChanges(ctx, &apiv1.GetChangesRequest{Locale: "de_DE", SinceVersion: 1 << 40})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestReleaseGRPC(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	client, teardownServer := setupTestGRPCServer()
+
+	defer teardownServer()
+
+	ctx := context.Background()
+
+	t.Run("latest release before the first release", func(t *testing.T) {
+		_, err := client.GetRelease(ctx, &apiv1.GetReleaseRequest{Release: "latest"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	created, err := client.CreateRelease(ctx, &apiv1.CreateReleaseRequest{Description: "initial"})
+	require.NoError(t, err)
+	assert.True(t, created.GetRelease().GetLatest())
+
+	_, err = client.DeleteTranslation(ctx, &apiv1.DeleteTranslationRequest{LanguageKey: "test_lk_0", Locale: "de_DE"})
+	require.NoError(t, err)
+
+	t.Run("read pinned release", func(t *testing.T) {
+		result, err := client.GetTranslationByKeyAndLocale(ctx, &apiv1.GetTranslationByKeyAndLocaleRequest{
+			LanguageKey: "test_lk_0",
+			Locale:      "de_DE",
+			Release:     fmt.Sprint(created.GetRelease().GetId()),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Übersetzungs-Dienst", result.GetTranslation().GetTranslation())
+	})
+
+	t.Run("list latest release", func(t *testing.T) {
+		result, err := client.ListTranslations(ctx, &apiv1.ListTranslationsRequest{Locale: "de_DE", Release: "latest"})
+		require.NoError(t, err)
+		assert.Len(t, result.GetTranslations().GetTranslations(), 2)
+	})
+
+	t.Run("grouped latest release", func(t *testing.T) {
+		result, err := client.GetGroupedTranslations(ctx, &apiv1.GetGroupedTranslationsRequest{Locales: []string{"de_DE"}, Release: "latest"})
+		require.NoError(t, err)
+
+		groups := result.GetGroupedTranslations().GetGroups()
+		require.Len(t, groups, 1)
+		assert.Equal(t, "Übersetzungs-Dienst", groupedTranslation(groups[0], "test_lk_0"))
+	})
+
+	t.Run("list releases", func(t *testing.T) {
+		result, err := client.ListReleases(ctx, &apiv1.ListReleasesRequest{})
+		require.NoError(t, err)
+		require.Len(t, result.GetReleases(), 1)
+		assert.Equal(t, "initial", result.GetReleases()[0].GetDescription())
+	})
+
+	t.Run("roll back to unknown release", func(t *testing.T) {
+		_, err := client.RollbackRelease(ctx, &apiv1.RollbackReleaseRequest{Release: "42"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("invalid release", func(t *testing.T) {
+		_, err := client.ListTranslations(ctx, &apiv1.ListTranslationsRequest{Locale: "de_DE", Release: "-1"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestRevisionGRPC(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	client, teardownServer := setupTestGRPCServer()
+
+	defer teardownServer()
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-author", "alice")
+
+	_, err = client.DeleteTranslation(ctx, &apiv1.DeleteTranslationRequest{LanguageKey: "test_lk_0", Locale: "de_DE"})
+	require.NoError(t, err)
+
+	revisions, err := client.ListRevisions(ctx, &apiv1.ListRevisionsRequest{LanguageKey: "test_lk_0", Locale: "de_DE"})
+	require.NoError(t, err)
+	require.Len(t, revisions.GetRevisions(), 2)
+
+	deletion := revisions.GetRevisions()[0]
+	assert.Equal(t, apiv1.ChangeType_CHANGE_TYPE_DELETED, deletion.GetType())
+	assert.Equal(t, "alice", deletion.GetChangedBy())
+
+	t.Run("restore deletion", func(t *testing.T) {
+		_, err := client.RestoreRevision(ctx, &apiv1.RestoreRevisionRequest{LanguageKey: "test_lk_0", Revision: deletion.GetId()})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("restore deleted translation", func(t *testing.T) {
+		result, err := client.RestoreRevision(ctx, &apiv1.RestoreRevisionRequest{LanguageKey: "test_lk_0", Revision: revisions.GetRevisions()[1].GetId()})
+		require.NoError(t, err)
+		assert.Equal(t, "Übersetzungs-Dienst", result.GetTranslation().GetTranslation())
+
+		restored, err := client.ListRevisions(ctx, &apiv1.ListRevisionsRequest{LanguageKey: "test_lk_0", Locale: "de_DE"})
+		require.NoError(t, err)
+		require.Len(t, restored.GetRevisions(), 3)
+		assert.Equal(t, apiv1.ChangeType_CHANGE_TYPE_CREATED, restored.GetRevisions()[0].GetType())
+	})
+}
+
+func TestDraftWorkflowGRPC(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	client, teardownServer := setupTestGRPCServer()
+
+	defer teardownServer()
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-author", "alice")
+
+	saved, err := client.SaveDraft(ctx, &apiv1.SaveDraftRequest{LanguageKey: "test_lk_0", Locale: "de_DE", Translation: "Entwurf"})
+	require.NoError(t, err)
+	assert.Equal(t, apiv1.TranslationStatus_TRANSLATION_STATUS_DRAFT, saved.GetDraft().GetStatus())
+	assert.Equal(t, "alice", saved.GetDraft().GetChangedBy())
+
+	reads := map[string]struct {
+		preview             bool
+		expectedTranslation string
+		expectedStatus      apiv1.TranslationStatus
+	}{
+		"published value by default": {
+			expectedTranslation: "Übersetzungs-Dienst",
+			expectedStatus:      apiv1.TranslationStatus_TRANSLATION_STATUS_PUBLISHED,
+		},
+		"draft in preview": {
+			preview:             true,
+			expectedTranslation: "Entwurf",
+			expectedStatus:      apiv1.TranslationStatus_TRANSLATION_STATUS_DRAFT,
+		},
+	}
+
+	for name, tc := range reads {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.GetTranslationByKeyAndLocale(ctx, &apiv1.GetTranslationByKeyAndLocaleRequest{LanguageKey: "test_lk_0", Locale: "de_DE", Preview: tc.preview})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedTranslation, result.GetTranslation().GetTranslation())
+			assert.Equal(t, tc.expectedStatus, result.GetTranslation().GetStatus())
+		})
+	}
+
+	t.Run("grouped drafts in preview", func(t *testing.T) {
+		result, err := client.GetGroupedTranslations(ctx, &apiv1.GetGroupedTranslationsRequest{Locales: []string{"de_DE"}, Preview: true})
+		require.NoError(t, err)
+
+		groups := result.GetGroupedTranslations().GetGroups()
+		require.Len(t, groups, 1)
+		assert.Equal(t, "Entwurf", groupedTranslation(groups[0], "test_lk_0"))
+	})
+
+	t.Run("publish before review", func(t *testing.T) {
+		_, err := client.PublishDraft(ctx, &apiv1.PublishDraftRequest{LanguageKey: "test_lk_0", Locale: "de_DE"})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("review and publish", func(t *testing.T) {
+		_, err := client.SubmitDraft(ctx, &apiv1.SubmitDraftRequest{LanguageKey: "test_lk_0", Locale: "de_DE"})
+		require.NoError(t, err)
+
+		inReview, err := client.ListDrafts(ctx, &apiv1.ListDraftsRequest{Status: apiv1.TranslationStatus_TRANSLATION_STATUS_IN_REVIEW})
+		require.NoError(t, err)
+		require.Len(t, inReview.GetDrafts(), 1)
+
+		approved, err := client.ApproveDraft(ctx, &apiv1.ApproveDraftRequest{LanguageKey: "test_lk_0", Locale: "de_DE", Comment: "Passt"})
+		require.NoError(t, err)
+		assert.Equal(t, "Passt", approved.GetDraft().GetComment())
+
+		published, err := client.PublishDraft(ctx, &apiv1.PublishDraftRequest{LanguageKey: "test_lk_0", Locale: "de_DE"})
+		require.NoError(t, err)
+		assert.Equal(t, "Entwurf", published.GetTranslation().GetTranslation())
+
+		_, err = client.GetDraft(ctx, &apiv1.GetDraftRequest{LanguageKey: "test_lk_0", Locale: "de_DE"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("preview of a release", func(t *testing.T) {
+		_, err := client.ListTranslations(ctx, &apiv1.ListTranslationsRequest{Locale: "de_DE", Release: "latest", Preview: true})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestNamespaceGRPC(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	client, teardownServer := setupTestGRPCServer()
+
+	defer teardownServer()
+
+	ctx := context.Background()
+
+	created, err := client.CreateNamespace(ctx, &apiv1.CreateNamespaceRequest{Name: "mobile", DefaultLocale: "de_DE", Locales: []string{"de_DE"}})
+	require.NoError(t, err)
+	assert.Equal(t, "de_DE", created.GetNamespace().GetDefaultLocale())
+
+	_, err = client.CreateNamespace(ctx, &apiv1.CreateNamespaceRequest{Name: "mobile"})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = client.CreateTranslation(ctx, &apiv1.CreateTranslationRequest{Namespace: "mobile", LanguageKey: "test_lk_0", Locale: "de_DE", Translation: "App-Dienst"})
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		namespace           string
+		locale              string
+		expectedErr         codes.Code
+		expectedTranslation string
+	}{
+		"default locale of the namespace": {
+			namespace:           "mobile",
+			expectedErr:         codes.OK,
+			expectedTranslation: "App-Dienst",
+		},
+		"default namespace": {
+			locale:              "de_DE",
+			expectedErr:         codes.OK,
+			expectedTranslation: "Übersetzungs-Dienst",
+		},
+		"locale not enabled in the namespace": {
+			namespace:   "mobile",
+			locale:      "en_GB",
+			expectedErr: codes.InvalidArgument,
+		},
+		"unknown namespace": {
+			namespace:   "unknown",
+			locale:      "de_DE",
+			expectedErr: codes.NotFound,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.GetTranslationByKeyAndLocale(ctx, &apiv1.GetTranslationByKeyAndLocaleRequest{Namespace: tc.namespace, LanguageKey: "test_lk_0", Locale: tc.locale})
+			require.Equal(t, tc.expectedErr, status.Code(err))
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.expectedTranslation, result.GetTranslation().GetTranslation())
+		})
+	}
+
+	t.Run("list translations of the namespace", func(t *testing.T) {
+		result, err := client.ListTranslations(ctx, &apiv1.ListTranslationsRequest{Namespace: "mobile", Locale: "de_DE"})
+		require.NoError(t, err)
+		require.Len(t, result.GetTranslations().GetTranslations(), 1)
+	})
+
+	t.Run("update namespace", func(t *testing.T) {
+		result, err := client.UpdateNamespace(ctx, &apiv1.UpdateNamespaceRequest{Name: "mobile", Description: proto.String("Mobile app"), DefaultLocale: proto.String(""), Locales: &apiv1.NamespaceLocales{}})
+		require.NoError(t, err)
+		assert.Equal(t, "Mobile app", result.GetNamespace().GetDescription())
+		assert.Empty(t, result.GetNamespace().GetLocales())
+	})
+
+	t.Run("settings changed by another replica", func(t *testing.T) {
+		_, err := client.ListTranslations(ctx, &apiv1.ListTranslationsRequest{Namespace: "mobile", Locale: "en_GB"})
+		require.NoError(t, err)
+
+		_, err = db.Exec("INSERT INTO namespace_locale (namespace, locale) VALUES ('mobile', 'de_DE')")
+		require.NoError(t, err)
+
+		assert.Eventually(t, func() bool {
+			_, err := client.ListTranslations(ctx, &apiv1.ListTranslationsRequest{Namespace: "mobile", Locale: "en_GB"})
+			return status.Code(err) == codes.InvalidArgument
+		}, 5*time.Second, 50*time.Millisecond)
+	})
+
+	t.Run("delete namespace", func(t *testing.T) {
+		_, err := client.DeleteNamespace(ctx, &apiv1.DeleteNamespaceRequest{Name: "mobile"})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+		_, err = client.DeleteTranslation(ctx, &apiv1.DeleteTranslationRequest{Namespace: "mobile", LanguageKey: "test_lk_0", Locale: "de_DE"})
+		require.NoError(t, err)
+
+		_, err = client.DeleteNamespace(ctx, &apiv1.DeleteNamespaceRequest{Name: "mobile"})
+		require.NoError(t, err)
+
+		_, err = client.GetNamespace(ctx, &apiv1.GetNamespaceRequest{Name: "mobile"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestNamespacedWorkflowGRPC(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	client, teardownServer := setupTestGRPCServer()
+
+	defer teardownServer()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err = client.CreateNamespace(ctx, &apiv1.CreateNamespaceRequest{Name: "mobile", Locales: []string{"de_DE"}})
+	require.NoError(t, err)
+
+	stream, err := client.WatchTranslations(ctx, &apiv1.WatchTranslationsRequest{Namespace: "mobile", Locale: "de_DE"})
+	require.NoError(t, err)
+
+	snapshot, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Empty(t, snapshot.GetSnapshot().GetTranslations())
+
+	// Changes of the default namespace are not streamed.
+	_, err = client.UpdateTranslation(ctx, &apiv1.UpdateTranslationRequest{LanguageKey: "test_lk_0", Translation: "Geändert", Locale: "de_DE"})
+	require.NoError(t, err)
+
+	_, err = client.SaveDraft(ctx, &apiv1.SaveDraftRequest{Namespace: "mobile", LanguageKey: "test_lk_0", Locale: "de_DE", Translation: "App-Dienst"})
+	require.NoError(t, err)
+
+	drafts, err := client.ListDrafts(ctx, &apiv1.ListDraftsRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, drafts.GetDrafts())
+
+	_, err = client.ListDrafts(ctx, &apiv1.ListDraftsRequest{Namespace: "unknown"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.SubmitDraft(ctx, &apiv1.SubmitDraftRequest{Namespace: "mobile", LanguageKey: "test_lk_0", Locale: "de_DE"})
+	require.NoError(t, err)
+
+	_, err = client.ApproveDraft(ctx, &apiv1.ApproveDraftRequest{Namespace: "mobile", LanguageKey: "test_lk_0", Locale: "de_DE"})
+	require.NoError(t, err)
+
+	published, err := client.PublishDraft(ctx, &apiv1.PublishDraftRequest{Namespace: "mobile", LanguageKey: "test_lk_0", Locale: "de_DE"})
+	require.NoError(t, err)
+	assert.Equal(t, "App-Dienst", published.GetTranslation().GetTranslation())
+
+	t.Run("watch translations of the namespace", func(t *testing.T) {
+		created, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, apiv1.ChangeType_CHANGE_TYPE_CREATED, created.GetChange().GetType())
+		assert.Equal(t, "App-Dienst", created.GetChange().GetTranslation().GetTranslation())
+	})
+
+	t.Run("list revisions of the namespace", func(t *testing.T) {
+		result, err := client.ListRevisions(ctx, &apiv1.ListRevisionsRequest{Namespace: "mobile", LanguageKey: "test_lk_0", Locale: "de_DE"})
+		require.NoError(t, err)
+		require.Len(t, result.GetRevisions(), 1)
+		assert.Equal(t, "App-Dienst", result.GetRevisions()[0].GetTranslation())
+	})
+
+	t.Run("get changes of the namespace", func(t *testing.T) {
+		result, err := client.GetChanges(ctx, &apiv1.GetChangesRequest{Namespace: "mobile", Locale: "de_DE", SinceVersion: snapshot.GetVersion()})
+		require.NoError(t, err)
+		require.Len(t, result.GetTranslations(), 1)
+		assert.Equal(t, "App-Dienst", result.GetTranslations()[0].GetTranslation())
+	})
+}
+
+func TestRenderGRPC(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	client, teardownServer := setupTestGRPCServer()
+
+	defer teardownServer()
+
+	ctx := context.Background()
+
+	_, err = client.CreateTranslation(ctx, &apiv1.CreateTranslationRequest{
+		LanguageKey: "finish_position",
+		Locale:      "en_GB",
+		Translation: "{name} finished {place, selectordinal, one {#st} two {#nd} few {#rd} other {#th}} on {day, date, ::MMMMd}",
+	})
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		args         map[string]*structpb.Value
+		timeZone     string
+		expectedErr  codes.Code
+		expectedText string
+	}{
+		"ordinal and date": {
+			args: map[string]*structpb.Value{
+				"name":  structpb.NewStringValue("Ann"),
+				"place": structpb.NewNumberValue(22),
+				"day":   structpb.NewStringValue("2025-03-05T12:00:00Z"),
+			},
+			expectedErr:  codes.OK,
+			expectedText: "Ann finished 22nd on 5 March",
+		},
+		"date in time zone": {
+			args: map[string]*structpb.Value{
+				"name":  structpb.NewStringValue("Ann"),
+				"place": structpb.NewNumberValue(1),
+				"day":   structpb.NewStringValue("2025-03-05T20:00:00Z"),
+			},
+			timeZone:     "Pacific/Auckland",
+			expectedErr:  codes.OK,
+			expectedText: "Ann finished 1st on 6 March",
+		},
+		"unknown time zone": {
+			args: map[string]*structpb.Value{
+				"name":  structpb.NewStringValue("Ann"),
+				"place": structpb.NewNumberValue(1),
+				"day":   structpb.NewStringValue("2025-03-05T20:00:00Z"),
+			},
+			timeZone:    "Local",
+			expectedErr: codes.InvalidArgument,
+		},
+		"missing argument": {
+			args: map[string]*structpb.Value{
+				"name":  structpb.NewStringValue("Ann"),
+				"place": structpb.NewNumberValue(3),
+			},
+			expectedErr: codes.InvalidArgument,
+		},
+		"mistyped argument": {
+			args: map[string]*structpb.Value{
+				"name":  structpb.NewStringValue("Ann"),
+				"place": structpb.NewNumberValue(3),
+				"day":   structpb.NewBoolValue(true),
+			},
+			expectedErr: codes.InvalidArgument,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.RenderTranslation(ctx, &apiv1.RenderTranslationRequest{LanguageKey: "finish_position", Locale: "en_GB", Args: tc.args, TimeZone: tc.timeZone})
+			require.Equal(t, tc.expectedErr, status.Code(err))
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.expectedText, result.GetText())
+		})
+	}
+
+	_, err = client.RenderTranslation(ctx, &apiv1.RenderTranslationRequest{LanguageKey: "unknown", Locale: "en_GB"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestPluralFormsGRPC(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	client, teardownServer := setupTestGRPCServer()
+
+	defer teardownServer()
+
+	ctx := context.Background()
+
+	_, err = client.AddLocale(ctx, &apiv1.AddLocaleRequest{Code: "ru_RU"})
+	require.NoError(t, err)
+
+	_, err = client.CreateTranslation(ctx, &apiv1.CreateTranslationRequest{
+		LanguageKey: "files",
+		Locale:      "ru_RU",
+		PluralForms: map[string]string{"one": "файл", "few": "файла", "other": "файла"},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	created, err := client.CreateTranslation(ctx, &apiv1.CreateTranslationRequest{
+		LanguageKey: "files",
+		Locale:      "ru_RU",
+		PluralForms: map[string]string{"one": "файл", "few": "файла", "many": "файлов", "other": "файла"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "файла", created.GetTranslation().GetTranslation())
+
+	_, err = client.CreateRelease(ctx, &apiv1.CreateReleaseRequest{})
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		count               string
+		release             string
+		expectedErr         codes.Code
+		expectedTranslation string
+	}{
+		"one": {
+			count:               "21",
+			expectedErr:         codes.OK,
+			expectedTranslation: "файл",
+		},
+		"few": {
+			count:               "3",
+			expectedErr:         codes.OK,
+			expectedTranslation: "файла",
+		},
+		"many": {
+			count:               "11",
+			expectedErr:         codes.OK,
+			expectedTranslation: "файлов",
+		},
+		"release": {
+			count:               "5",
+			release:             "latest",
+			expectedErr:         codes.OK,
+			expectedTranslation: "файлов",
+		},
+		"invalid count": {
+			count:       "five",
+			expectedErr: codes.InvalidArgument,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.GetTranslationByKeyAndLocale(ctx, &apiv1.GetTranslationByKeyAndLocaleRequest{LanguageKey: "files", Locale: "ru_RU", Count: tc.count, Release: tc.release})
+			require.Equal(t, tc.expectedErr, status.Code(err))
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.expectedTranslation, result.GetTranslation().GetTranslation())
+			assert.Len(t, result.GetTranslation().GetPluralForms(), 4)
+		})
+	}
+
+	t.Run("revision keeps the plural forms", func(t *testing.T) {
+		_, err := client.UpdateTranslation(ctx, &apiv1.UpdateTranslationRequest{LanguageKey: "files", Locale: "ru_RU", Translation: "файлы"})
+		require.NoError(t, err)
+
+		revisions, err := client.ListRevisions(ctx, &apiv1.ListRevisionsRequest{LanguageKey: "files", Locale: "ru_RU"})
+		require.NoError(t, err)
+		require.Len(t, revisions.GetRevisions(), 2)
+		assert.Empty(t, revisions.GetRevisions()[0].GetPluralForms())
+		assert.Equal(t, "файлов", revisions.GetRevisions()[1].GetPluralForms()["many"])
+
+		restored, err := client.RestoreRevision(ctx, &apiv1.RestoreRevisionRequest{LanguageKey: "files", Revision: revisions.GetRevisions()[1].GetId()})
+		require.NoError(t, err)
+		assert.Equal(t, "файлов", restored.GetTranslation().GetPluralForms()["many"])
+	})
+}
+
+func TestSearchGRPC(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	client, teardownServer := setupTestGRPCServer()
+
+	defer teardownServer()
+
+	ctx := context.Background()
+
+	testCases := map[string]struct {
+		request      *apiv1.SearchTranslationsRequest
+		expectedErr  codes.Code
+		expectedKeys []string
+	}{
+		"text": {
+			request:      &apiv1.SearchTranslationsRequest{Query: "services", Locale: "en_GB"},
+			expectedErr:  codes.OK,
+			expectedKeys: []string{"test_lk_0 en_GB"},
+		},
+		"keys": {
+			request:      &apiv1.SearchTranslationsRequest{Query: "test_lk"},
+			expectedErr:  codes.OK,
+			expectedKeys: []string{"test_lk_0 de_DE", "test_lk_0 en_GB", "test_lk_1 en_GB", "test_lk_2 de_DE"},
+		},
+		"missing query": {
+			request:     &apiv1.SearchTranslationsRequest{},
+			expectedErr: codes.InvalidArgument,
+		},
+		"unsupported locale": {
+			request:     &apiv1.SearchTranslationsRequest{Query: "services", Locale: "xx_XX"},
+			expectedErr: codes.InvalidArgument,
+		},
+		"unknown namespace": {
+			request:     &apiv1.SearchTranslationsRequest{Query: "services", Namespace: "unknown"},
+			expectedErr: codes.NotFound,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.SearchTranslations(ctx, tc.request)
+			require.Equal(t, tc.expectedErr, status.Code(err))
+			if err != nil {
+				return
+			}
+
+			keys := []string{}
+			for _, entry := range result.GetResults() {
+				keys = append(keys, entry.GetLanguageKey()+" "+entry.GetLocale())
+			}
+			assert.Equal(t, tc.expectedKeys, keys)
+		})
+	}
+
+	t.Run("paginate results", func(t *testing.T) {
+		first, err := client.SearchTranslations(ctx, &apiv1.SearchTranslationsRequest{Query: "test_lk", PageSize: 3})
+		require.NoError(t, err)
+		require.Len(t, first.GetResults(), 3)
+		require.NotEmpty(t, first.GetNextPageToken())
+
+		second, err := client.SearchTranslations(ctx, &apiv1.SearchTranslationsRequest{Query: "test_lk", PageSize: 3, PageToken: first.GetNextPageToken()})
+		require.NoError(t, err)
+		require.Len(t, second.GetResults(), 1)
+		assert.Equal(t, "test_lk_2", second.GetResults()[0].GetLanguageKey())
+		assert.Empty(t, second.GetNextPageToken())
+	})
+}
@@ -1,17 +1,23 @@
 package integrationtests
 
 import (
+	"bufio"
 	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
+	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/henok321/translation-service/api/handlers"
 	api "github.com/henok321/translation-service/gen"
+	"github.com/henok321/translation-service/pkg/translation"
 	pg "gorm.io/driver/postgres"
 	"gorm.io/gorm"
 
@@ -20,6 +26,16 @@ import (
 )
 
 func setupTestRESTServer() (server *httptest.Server, client *api.Client, teardown func(*httptest.Server)) {
+	return setupRESTServer(false)
+}
+
+// setupReviewedRESTServer starts a server that publishes values only through
+// reviewed drafts, as deployed with REQUIRE_REVIEW.
+func setupReviewedRESTServer() (server *httptest.Server, client *api.Client, teardown func(*httptest.Server)) {
+	return setupRESTServer(true)
+}
+
+func setupRESTServer(requireReview bool) (server *httptest.Server, client *api.Client, teardown func(*httptest.Server)) {
 	url := os.Getenv("DATABASE_URL")
 	database, err := gorm.Open(pg.Open(url), &gorm.Config{})
 	if err != nil {
@@ -27,11 +43,30 @@ func setupTestRESTServer() (server *httptest.Server, client *api.Client, teardow
 		os.Exit(1)
 	}
 
-	router := handlers.SetupRouter(database)
+	ctx, stopListener := context.WithCancel(context.Background())
+	repo := translation.NewCachedRepository(translation.NewRepository(database))
+	feed := translation.NewChangeFeed(database)
+	namespaces := translation.NewCachedNamespaceRegistry(translation.NewNamespaceRegistry(database))
+	listener := translation.NewChangeListener(database)
+	listener.AddHandler(repo)
+	listener.AddHandler(feed)
+	listener.AddHandler(namespaces)
+	go listener.Run(ctx)
+
+	var handlerRepo translation.Repository = repo
+	catalogs := translation.NewCatalogStore(database)
+	if requireReview {
+		handlerRepo = translation.RequireReview(repo)
+		catalogs = translation.RequireReviewedImports(catalogs)
+	}
+
+	router := handlers.SetupRouter(handlerRepo, translation.NewLocaleRegistry(database), namespaces, translation.NewReleaseStore(database), translation.NewRevisionStore(database), translation.NewDraftStore(database), catalogs, translation.NewSearchStore(database), feed, handlers.RESTConfig{})
 
 	server = httptest.NewServer(router)
 	teardown = func(*httptest.Server) {
+		feed.Close()
 		server.Close()
+		stopListener()
 	}
 
 	slog.Info("Starting application", "url", server.URL)
@@ -165,3 +200,3159 @@ func TestTranslationREST(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslationRESTWrite(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	server, client, teardownServer := setupTestRESTServer()
+
+	defer teardownServer(server)
+
+	ctx := context.Background()
+
+	postTranslation := map[string]struct {
+		body        api.TranslationInput
+		expectedErr int
+	}{
+		"valid translation": {
+			body:        api.TranslationInput{LanguageKey: "test_lk_new", Locale: "en_GB", Translation: "New one"},
+			expectedErr: 201,
+		},
+		"duplicate translation": {
+			body:        api.TranslationInput{LanguageKey: "test_lk_0", Locale: "en_GB", Translation: "Duplicate"},
+			expectedErr: 409,
+		},
+		"missing language key": {
+			body:        api.TranslationInput{Locale: "en_GB", Translation: "value"},
+			expectedErr: 400,
+		},
+		"unsupported locale": {
+			body:        api.TranslationInput{LanguageKey: "test_lk_new", Locale: "fr-FR", Translation: "value"},
+			expectedErr: 400,
+		},
+	}
+
+	for name, tc := range postTranslation {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.PostTranslation(ctx, &api.PostTranslationParams{}, tc.body)
+			require.NoError(t, err)
+			defer result.Body.Close()
+
+			assert.Equal(t, tc.expectedErr, result.StatusCode)
+		})
+	}
+
+	t.Run("put replaces existing translation", func(t *testing.T) {
+		result, err := client.PutTranslationKey(ctx, "test_lk_1", &api.PutTranslationKeyParams{Locale: "en_GB"}, api.TranslationValue{Translation: "Replaced"})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+
+		var body api.Translation
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+		assert.Equal(t, "Replaced", *body.Translation)
+	})
+
+	t.Run("put creates missing translation", func(t *testing.T) {
+		result, err := client.PutTranslationKey(ctx, "test_lk_1", &api.PutTranslationKeyParams{Locale: "de_DE"}, api.TranslationValue{Translation: "Noch ein neuer"})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		assert.Equal(t, 201, result.StatusCode)
+	})
+
+	t.Run("patch updates translation", func(t *testing.T) {
+		value := "Patched"
+		result, err := client.PatchTranslationKey(ctx, "test_lk_0", &api.PatchTranslationKeyParams{Locale: "de_DE"}, api.TranslationPatch{Translation: &value})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+
+		var body api.Translation
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+		assert.Equal(t, "Patched", *body.Translation)
+	})
+
+	t.Run("patch unknown translation", func(t *testing.T) {
+		value := "Patched"
+		result, err := client.PatchTranslationKey(ctx, "invalid_key", &api.PatchTranslationKeyParams{Locale: "de_DE"}, api.TranslationPatch{Translation: &value})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		assert.Equal(t, 404, result.StatusCode)
+	})
+
+	t.Run("delete translation", func(t *testing.T) {
+		result, err := client.DeleteTranslationKey(ctx, "test_lk_2", &api.DeleteTranslationKeyParams{Locale: "de_DE"})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		assert.Equal(t, 204, result.StatusCode)
+
+		result, err = client.DeleteTranslationKey(ctx, "test_lk_2", &api.DeleteTranslationKeyParams{Locale: "de_DE"})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		assert.Equal(t, 404, result.StatusCode)
+	})
+}
+
+func TestLocaleREST(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	server, client, teardownServer := setupTestRESTServer()
+
+	defer teardownServer(server)
+
+	ctx := context.Background()
+
+	postLocale := map[string]struct {
+		code        string
+		expectedErr int
+	}{
+		"valid locale": {
+			code:        "ja_JP",
+			expectedErr: 201,
+		},
+		"existing locale": {
+			code:        "en_GB",
+			expectedErr: 409,
+		},
+		"malformed locale": {
+			code:        "ja-jp",
+			expectedErr: 400,
+		},
+	}
+
+	for name, tc := range postLocale {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.PostLocale(ctx, api.LocaleInput{Code: tc.code})
+			require.NoError(t, err)
+			defer result.Body.Close()
+
+			assert.Equal(t, tc.expectedErr, result.StatusCode)
+		})
+	}
+
+	t.Run("disabled locale is not served", func(t *testing.T) {
+		enabled := false
+		result, err := client.PatchLocaleCode(ctx, "de_DE", api.LocalePatch{Enabled: &enabled})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+
+		locale := "de_DE"
+		translations, err := client.GetTranslations(ctx, &api.GetTranslationsParams{Locale: &locale})
+		require.NoError(t, err)
+		defer translations.Body.Close()
+
+		assert.Equal(t, 400, translations.StatusCode)
+	})
+
+	t.Run("list locales", func(t *testing.T) {
+		result, err := client.GetLocales(ctx, &api.GetLocalesParams{})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		var body []api.Locale
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+
+		localeCodes := []string{}
+		for _, locale := range body {
+			localeCodes = append(localeCodes, *locale.Code)
+		}
+		assert.Equal(t, []string{"en_GB", "ja_JP"}, localeCodes)
+	})
+}
+
+func TestFallbackREST(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	server, client, teardownServer := setupTestRESTServer()
+
+	defer teardownServer(server)
+
+	ctx := context.Background()
+
+	fallback := "en_GB"
+	result, err := client.PatchLocaleCode(ctx, "de_DE", api.LocalePatch{Fallback: &fallback})
+	require.NoError(t, err)
+	result.Body.Close()
+	require.Equal(t, 200, result.StatusCode)
+
+	locale := "de_DE"
+
+	t.Run("translation served by fallback", func(t *testing.T) {
+		result, err := client.GetTranslationKey(ctx, "test_lk_1", &api.GetTranslationKeyParams{Locale: &locale})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+
+		var body api.Translation
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+		assert.Equal(t, "de_DE", *body.Locale)
+		assert.Equal(t, "en_GB", *body.ResolvedLocale)
+	})
+
+	t.Run("list merges fallback translations", func(t *testing.T) {
+		result, err := client.GetTranslations(ctx, &api.GetTranslationsParams{Locale: &locale})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		var body []api.Translation
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+
+		resolved := map[string]string{}
+		for _, entry := range body {
+			resolved[*entry.LanguageKey] = *entry.ResolvedLocale
+		}
+		assert.Equal(t, map[string]string{"test_lk_0": "de_DE", "test_lk_1": "en_GB", "test_lk_2": "de_DE"}, resolved)
+	})
+}
+
+func TestAcceptLanguageREST(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	server, client, teardownServer := setupTestRESTServer()
+
+	defer teardownServer(server)
+
+	testCases := map[string]struct {
+		acceptLanguage          *string
+		locale                  *string
+		expectedContentLanguage string
+		expectedTranslation     string
+	}{
+		"no preference": {
+			expectedContentLanguage: "en-GB",
+			expectedTranslation:     "Translation Service",
+		},
+		"region stripped": {
+			acceptLanguage:          ptr("de-AT, en;q=0.5"),
+			expectedContentLanguage: "de-DE",
+			expectedTranslation:     "Übersetzungs-Dienst",
+		},
+		"quality values": {
+			acceptLanguage:          ptr("en-US;q=0.4, de-DE;q=0.9"),
+			expectedContentLanguage: "de-DE",
+			expectedTranslation:     "Übersetzungs-Dienst",
+		},
+		"excluded wildcard match": {
+			acceptLanguage:          ptr("en;q=0, *;q=0.1"),
+			expectedContentLanguage: "de-DE",
+			expectedTranslation:     "Übersetzungs-Dienst",
+		},
+		"unsupported language": {
+			acceptLanguage:          ptr("ja-JP"),
+			expectedContentLanguage: "en-GB",
+			expectedTranslation:     "Translation Service",
+		},
+		"locale parameter wins": {
+			acceptLanguage:          ptr("de-DE"),
+			locale:                  ptr("en_GB"),
+			expectedContentLanguage: "en-GB",
+			expectedTranslation:     "Translation Service",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.GetTranslationKey(context.Background(), "test_lk_0", &api.GetTranslationKeyParams{
+				Locale:         tc.locale,
+				AcceptLanguage: tc.acceptLanguage,
+			})
+			require.NoError(t, err)
+			defer result.Body.Close()
+
+			require.Equal(t, 200, result.StatusCode)
+			assert.Equal(t, tc.expectedContentLanguage, result.Header.Get("Content-Language"))
+			assert.Contains(t, result.Header.Values("Vary"), "Accept-Language")
+
+			var body api.Translation
+			require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+			assert.Equal(t, tc.expectedTranslation, *body.Translation)
+		})
+	}
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+func TestPaginationREST(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	server, client, teardownServer := setupTestRESTServer()
+
+	defer teardownServer(server)
+
+	ctx := context.Background()
+
+	t.Run("paginate by update time", func(t *testing.T) {
+		orderBy := api.GetTranslationsParamsOrderBy("updated_at")
+		params := &api.GetTranslationsParams{Locale: ptr("en_GB"), PageSize: ptr(1), OrderBy: &orderBy}
+		pages := 0
+
+		for {
+			result, err := client.GetTranslations(ctx, params)
+			require.NoError(t, err)
+			defer result.Body.Close()
+
+			require.Equal(t, 200, result.StatusCode)
+
+			var body []api.Translation
+			require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+			require.Len(t, body, 1)
+			pages++
+
+			nextPageToken := result.Header.Get("X-Next-Page-Token")
+			if nextPageToken == "" {
+				break
+			}
+			params.PageToken = &nextPageToken
+		}
+
+		assert.Equal(t, 2, pages)
+	})
+
+	t.Run("invalid page size", func(t *testing.T) {
+		result, err := client.GetTranslations(ctx, &api.GetTranslationsParams{Locale: ptr("en_GB"), PageSize: ptr(0)})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		assert.Equal(t, 400, result.StatusCode)
+	})
+
+	t.Run("page size above the maximum", func(t *testing.T) {
+		result, err := client.GetTranslations(ctx, &api.GetTranslationsParams{Locale: ptr("en_GB"), PageSize: ptr(translation.MaxPageSize + 1)})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		assert.Equal(t, 400, result.StatusCode)
+	})
+
+	t.Run("page token of another query", func(t *testing.T) {
+		first, err := client.GetTranslations(ctx, &api.GetTranslationsParams{Locale: ptr("en_GB"), PageSize: ptr(1)})
+		require.NoError(t, err)
+		defer first.Body.Close()
+
+		result, err := client.GetTranslations(ctx, &api.GetTranslationsParams{
+			Locale:    ptr("en_GB"),
+			PageSize:  ptr(1),
+			KeyPrefix: ptr("test"),
+			PageToken: ptr(first.Header.Get("X-Next-Page-Token")),
+		})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		assert.Equal(t, 400, result.StatusCode)
+	})
+}
+
+func TestBatchGetREST(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	server, client, teardownServer := setupTestRESTServer()
+
+	defer teardownServer(server)
+
+	testCases := map[string]struct {
+		keys            []string
+		expectedErr     int
+		expectedFound   []string
+		expectedMissing []string
+	}{
+		"found and missing keys": {
+			keys:            []string{"test_lk_0", "test_lk_1", "test_lk_1", "invalid_key"},
+			expectedErr:     200,
+			expectedFound:   []string{"test_lk_0"},
+			expectedMissing: []string{"test_lk_1", "invalid_key"},
+		},
+		"no keys": {
+			keys:        []string{},
+			expectedErr: 400,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.PostTranslationsBatchGet(context.Background(), &api.PostTranslationsBatchGetParams{Locale: ptr("de_DE")}, api.BatchGetRequest{Keys: tc.keys})
+			require.NoError(t, err)
+			defer result.Body.Close()
+
+			require.Equal(t, tc.expectedErr, result.StatusCode)
+			if result.StatusCode != 200 {
+				return
+			}
+
+			var body api.BatchGetResult
+			require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+
+			found := []string{}
+			for _, entry := range body.Translations {
+				found = append(found, *entry.LanguageKey)
+			}
+			assert.Equal(t, tc.expectedFound, found)
+			assert.Equal(t, tc.expectedMissing, body.MissingKeys)
+		})
+	}
+}
+
+func TestCacheInvalidationREST(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	server, client, teardownServer := setupTestRESTServer()
+
+	defer teardownServer(server)
+
+	getTranslation := func(key string) (int, string) {
+		result, err := client.GetTranslationKey(context.Background(), key, &api.GetTranslationKeyParams{Locale: ptr("de_DE")})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		if result.StatusCode != 200 {
+			return result.StatusCode, ""
+		}
+
+		var body api.Translation
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+		return result.StatusCode, *body.Translation
+	}
+
+	testCases := map[string]struct {
+		statement        string
+		key              string
+		expectedStatus   int
+		expectedResponse string
+	}{
+		"update outside the service": {
+			statement:        "UPDATE translation SET translation = 'Geändert' WHERE language_key = 'test_lk_0' AND locale = 'de_DE'",
+			key:              "test_lk_0",
+			expectedStatus:   200,
+			expectedResponse: "Geändert",
+		},
+		"delete outside the service": {
+			statement:      "DELETE FROM translation WHERE language_key = 'test_lk_2' AND locale = 'de_DE'",
+			key:            "test_lk_2",
+			expectedStatus: 404,
+		},
+		"insert outside the service": {
+			statement:        "INSERT INTO translation (language_key, locale, translation, created_at, updated_at) VALUES ('test_lk_3', 'de_DE', 'Neu', NOW(), NOW())",
+			key:              "test_lk_3",
+			expectedStatus:   200,
+			expectedResponse: "Neu",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// Warm up the cache before changing the database behind its back.
+			getTranslation(tc.key)
+
+			_, err := db.Exec(tc.statement)
+			require.NoError(t, err)
+
+			assert.Eventually(t, func() bool {
+				status, text := getTranslation(tc.key)
+				return status == tc.expectedStatus && text == tc.expectedResponse
+			}, 5*time.Second, 50*time.Millisecond)
+		})
+	}
+}
+
+type serverSentEvent struct {
+	event string
+	id    string
+	data  string
+}
+
+// readEvent returns the next event of the stream, skipping comments.
+func readEvent(t *testing.T, scanner *bufio.Scanner) serverSentEvent {
+	event := serverSentEvent{}
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if event.event != "" {
+				return event
+			}
+		case strings.HasPrefix(line, "event: "):
+			event.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "id: "):
+			event.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			event.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+	require.NoError(t, scanner.Err())
+	t.Fatal("event stream ended")
+	return event
+}
+
+func TestTranslationEventsREST(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	server, client, teardownServer := setupTestRESTServer()
+
+	defer teardownServer(server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	lastEventID := ""
+
+	t.Run("stream translation changes", func(t *testing.T) {
+		result, err := client.GetTranslationsEvents(ctx, &api.GetTranslationsEventsParams{Locale: ptr("en_GB")})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+		assert.Equal(t, "text/event-stream", result.Header.Get("Content-Type"))
+
+		scanner := bufio.NewScanner(result.Body)
+
+		snapshot := readEvent(t, scanner)
+		assert.Equal(t, "snapshot", snapshot.event)
+
+		var translations []api.Translation
+		require.NoError(t, json.Unmarshal([]byte(snapshot.data), &translations))
+		assert.Len(t, translations, 2)
+
+		updateResult, err := client.PutTranslationKey(ctx, "test_lk_0", &api.PutTranslationKeyParams{Locale: "en_GB"}, api.TranslationValue{Translation: "Updated"})
+		require.NoError(t, err)
+		updateResult.Body.Close()
+
+		deleteResult, err := client.DeleteTranslationKey(ctx, "test_lk_1", &api.DeleteTranslationKeyParams{Locale: "en_GB"})
+		require.NoError(t, err)
+		deleteResult.Body.Close()
+
+		updated := readEvent(t, scanner)
+		assert.Equal(t, "updated", updated.event)
+
+		var change api.TranslationChange
+		require.NoError(t, json.Unmarshal([]byte(updated.data), &change))
+		assert.Equal(t, "test_lk_0", change.LanguageKey)
+		assert.Equal(t, "Updated", *change.Translation)
+		assert.Equal(t, updated.id, fmt.Sprint(change.Version))
+
+		deleted := readEvent(t, scanner)
+		assert.Equal(t, "deleted", deleted.event)
+
+		lastEventID = updated.id
+	})
+
+	t.Run("resume with Last-Event-ID", func(t *testing.T) {
+		result, err := client.GetTranslationsEvents(ctx, &api.GetTranslationsEventsParams{Locale: ptr("en_GB"), LastEventID: ptr(lastEventID)})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+
+		deleted := readEvent(t, bufio.NewScanner(result.Body))
+		assert.Equal(t, "deleted", deleted.event)
+		assert.Contains(t, deleted.data, "test_lk_1")
+	})
+
+	t.Run("resume with unknown Last-Event-ID", func(t *testing.T) {
+		for _, lastEventID := range []string{"garbage", "1099511627776"} {
+			result, err := client.GetTranslationsEvents(ctx, &api.GetTranslationsEventsParams{Locale: ptr("en_GB"), LastEventID: ptr(lastEventID)})
+			require.NoError(t, err)
+			result.Body.Close()
+
+			assert.Equal(t, 400, result.StatusCode)
+		}
+	})
+}
+
+func TestTranslationChangesREST(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	server, client, teardownServer := setupTestRESTServer()
+
+	defer teardownServer(server)
+
+	ctx := context.Background()
+
+	getChanges := func(t *testing.T, params *api.GetTranslationsChangesParams) (int, api.TranslationDelta) {
+		result, err := client.GetTranslationsChanges(ctx, params)
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		var body api.TranslationDelta
+		if result.StatusCode == 200 {
+			require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+		}
+		return result.StatusCode, body
+	}
+
+	status, initial := getChanges(t, &api.GetTranslationsChangesParams{Locale: ptr("en_GB")})
+	require.Equal(t, 200, status)
+	assert.Len(t, initial.Translations, 2)
+	assert.Empty(t, initial.DeletedKeys)
+	assert.False(t, initial.HasMore)
+
+	updateResult, err := client.PutTranslationKey(ctx, "test_lk_0", &api.PutTranslationKeyParams{Locale: "en_GB"}, api.TranslationValue{Translation: "Updated"})
+	require.NoError(t, err)
+	updateResult.Body.Close()
+
+	deleteResult, err := client.DeleteTranslationKey(ctx, "test_lk_1", &api.DeleteTranslationKeyParams{Locale: "en_GB"})
+	require.NoError(t, err)
+	deleteResult.Body.Close()
+
+	testCases := map[string]struct {
+		params              *api.GetTranslationsChangesParams
+		expectedStatus      int
+		expectedKeys        []string
+		expectedDeletedKeys []string
+		expectedHasMore     bool
+	}{
+		"changes since the initial sync": {
+			params:              &api.GetTranslationsChangesParams{Locale: ptr("en_GB"), Since: ptr(initial.Version)},
+			expectedStatus:      200,
+			expectedKeys:        []string{"test_lk_0"},
+			expectedDeletedKeys: []string{"test_lk_1"},
+		},
+		"first page of the changes": {
+			params:              &api.GetTranslationsChangesParams{Locale: ptr("en_GB"), Since: ptr(initial.Version), PageSize: ptr(1)},
+			expectedStatus:      200,
+			expectedKeys:        []string{"test_lk_0"},
+			expectedDeletedKeys: []string{},
+			expectedHasMore:     true,
+		},
+		"changes of another locale": {
+			params:              &api.GetTranslationsChangesParams{Locale: ptr("de_DE"), Since: ptr(initial.Version)},
+			expectedStatus:      200,
+			expectedKeys:        []string{},
+			expectedDeletedKeys: []string{},
+		},
+		"unknown version": {
+			params:         &api.GetTranslationsChangesParams{Locale: ptr("en_GB"), Since: ptr(int64(1 << 40))},
+			expectedStatus: 400,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			status, delta := getChanges(t, tc.params)
+			require.Equal(t, tc.expectedStatus, status)
+			if status != 200 {
+				return
+			}
+
+			keys := []string{}
+			for _, entry := range delta.Translations {
+				keys = append(keys, *entry.LanguageKey)
+			}
+			assert.Equal(t, tc.expectedKeys, keys)
+			assert.Equal(t, tc.expectedDeletedKeys, delta.DeletedKeys)
+			assert.Equal(t, tc.expectedHasMore, delta.HasMore)
+			assert.Greater(t, delta.Version, initial.Version)
+		})
+	}
+}
+
+func TestConditionalRequestsREST(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	server, client, teardownServer := setupTestRESTServer()
+
+	defer teardownServer(server)
+
+	ctx := context.Background()
+
+	initial, err := client.GetTranslations(ctx, &api.GetTranslationsParams{Locale: ptr("en_GB")})
+	require.NoError(t, err)
+	initial.Body.Close()
+
+	require.Equal(t, 200, initial.StatusCode)
+	etag := initial.Header.Get("ETag")
+	lastModified := initial.Header.Get("Last-Modified")
+	require.NotEmpty(t, etag)
+	require.NotEmpty(t, lastModified)
+	assert.Equal(t, "no-cache", initial.Header.Get("Cache-Control"))
+
+	testCases := map[string]struct {
+		params         *api.GetTranslationsParams
+		expectedStatus int
+	}{
+		"matching ETag": {
+			params:         &api.GetTranslationsParams{Locale: ptr("en_GB"), IfNoneMatch: ptr(etag)},
+			expectedStatus: 304,
+		},
+		"matching weak ETag in a list": {
+			params:         &api.GetTranslationsParams{Locale: ptr("en_GB"), IfNoneMatch: ptr(`"other", W/` + etag)},
+			expectedStatus: 304,
+		},
+		"ETag of another locale": {
+			params:         &api.GetTranslationsParams{Locale: ptr("de_DE"), IfNoneMatch: ptr(etag)},
+			expectedStatus: 200,
+		},
+		"not modified since": {
+			params:         &api.GetTranslationsParams{Locale: ptr("en_GB"), IfModifiedSince: ptr(lastModified)},
+			expectedStatus: 304,
+		},
+		"modified since": {
+			params:         &api.GetTranslationsParams{Locale: ptr("en_GB"), IfModifiedSince: ptr("Mon, 01 Jan 2001 00:00:00 GMT")},
+			expectedStatus: 200,
+		},
+		"If-None-Match takes precedence": {
+			params:         &api.GetTranslationsParams{Locale: ptr("en_GB"), IfNoneMatch: ptr(`"other"`), IfModifiedSince: ptr(lastModified)},
+			expectedStatus: 200,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.GetTranslations(ctx, tc.params)
+			require.NoError(t, err)
+			defer result.Body.Close()
+
+			assert.Equal(t, tc.expectedStatus, result.StatusCode)
+			assert.NotEmpty(t, result.Header.Get("ETag"))
+		})
+	}
+
+	t.Run("ETag changes with the bundle", func(t *testing.T) {
+		deleteResult, err := client.DeleteTranslationKey(ctx, "test_lk_1", &api.DeleteTranslationKeyParams{Locale: "en_GB"})
+		require.NoError(t, err)
+		deleteResult.Body.Close()
+
+		result, err := client.GetTranslations(ctx, &api.GetTranslationsParams{Locale: ptr("en_GB"), IfNoneMatch: ptr(etag)})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		assert.Equal(t, 200, result.StatusCode)
+		assert.NotEqual(t, etag, result.Header.Get("ETag"))
+	})
+}
+
+func TestReleaseREST(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	server, client, teardownServer := setupTestRESTServer()
+
+	defer teardownServer(server)
+
+	ctx := context.Background()
+
+	createRelease := func(t *testing.T, description string) api.Release {
+		result, err := client.PostRelease(ctx, api.ReleaseInput{Description: ptr(description)})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 201, result.StatusCode)
+
+		var release api.Release
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&release))
+		return release
+	}
+
+	updateTranslation := func(t *testing.T, text string) {
+		result, err := client.PutTranslationKey(ctx, "test_lk_0", &api.PutTranslationKeyParams{Locale: "en_GB"}, api.TranslationValue{Translation: text})
+		require.NoError(t, err)
+		result.Body.Close()
+	}
+
+	first := createRelease(t, "first")
+	assert.True(t, *first.Latest)
+	updateTranslation(t, "Second release")
+
+	second := createRelease(t, "second")
+	updateTranslation(t, "Unreleased")
+
+	rollbackResult, err := client.PostReleaseReleaseRollback(ctx, fmt.Sprint(*first.Id))
+	require.NoError(t, err)
+	rollbackResult.Body.Close()
+	require.Equal(t, 200, rollbackResult.StatusCode)
+
+	testCases := map[string]struct {
+		release              *string
+		expectedStatus       int
+		expectedTranslation  string
+		expectedCacheControl string
+	}{
+		"live translations": {
+			expectedStatus:       200,
+			expectedTranslation:  "Unreleased",
+			expectedCacheControl: "no-cache",
+		},
+		"pinned release": {
+			release:              ptr(fmt.Sprint(*second.Id)),
+			expectedStatus:       200,
+			expectedTranslation:  "Second release",
+			expectedCacheControl: "public, max-age=31536000, immutable",
+		},
+		"latest release after rollback": {
+			release:              ptr("latest"),
+			expectedStatus:       200,
+			expectedTranslation:  "Translation Service",
+			expectedCacheControl: "no-cache",
+		},
+		"invalid release": {
+			release:        ptr("first"),
+			expectedStatus: 400,
+		},
+		"unknown release": {
+			release:        ptr("99"),
+			expectedStatus: 404,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.GetTranslations(ctx, &api.GetTranslationsParams{Locale: ptr("en_GB"), KeyPrefix: ptr("test_lk_0"), Release: tc.release})
+			require.NoError(t, err)
+			defer result.Body.Close()
+
+			require.Equal(t, tc.expectedStatus, result.StatusCode)
+			if result.StatusCode != 200 {
+				return
+			}
+
+			var translations []api.Translation
+			require.NoError(t, json.NewDecoder(result.Body).Decode(&translations))
+			require.Len(t, translations, 1)
+			assert.Equal(t, tc.expectedTranslation, *translations[0].Translation)
+			assert.Equal(t, tc.expectedCacheControl, result.Header.Get("Cache-Control"))
+		})
+	}
+
+	t.Run("list releases", func(t *testing.T) {
+		result, err := client.GetReleases(ctx)
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		var releases []api.Release
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&releases))
+		require.Len(t, releases, 2)
+		assert.Equal(t, *second.Id, *releases[0].Id)
+		assert.False(t, *releases[0].Latest)
+		assert.Equal(t, "first", *releases[1].Description)
+		assert.True(t, *releases[1].Latest)
+	})
+
+	t.Run("get latest release", func(t *testing.T) {
+		result, err := client.GetReleaseRelease(ctx, "latest")
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		var release api.Release
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&release))
+		assert.Equal(t, *first.Id, *release.Id)
+	})
+}
+
+func TestRevisionREST(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	server, client, teardownServer := setupTestRESTServer()
+
+	defer teardownServer(server)
+
+	ctx := context.Background()
+
+	history := func(t *testing.T) []api.Revision {
+		result, err := client.GetTranslationKeyHistory(ctx, "test_lk_0", &api.GetTranslationKeyHistoryParams{Locale: "en_GB"})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+
+		var revisions []api.Revision
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&revisions))
+		return revisions
+	}
+
+	updateResult, err := client.PutTranslationKey(ctx, "test_lk_0", &api.PutTranslationKeyParams{Locale: "en_GB", XAuthor: ptr("alice")}, api.TranslationValue{Translation: "Overwritten"})
+	require.NoError(t, err)
+	updateResult.Body.Close()
+	require.Equal(t, 200, updateResult.StatusCode)
+
+	revisions := history(t)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, api.Updated, revisions[0].Type)
+	assert.Equal(t, "Overwritten", *revisions[0].Translation)
+	assert.Equal(t, "alice", *revisions[0].ChangedBy)
+	assert.Equal(t, api.Created, revisions[1].Type)
+	assert.Nil(t, revisions[1].ChangedBy)
+
+	original := revisions[1]
+
+	otherKey, err := client.GetTranslationKeyHistory(ctx, "test_lk_1", &api.GetTranslationKeyHistoryParams{Locale: "en_GB"})
+	require.NoError(t, err)
+	var otherRevisions []api.Revision
+	require.NoError(t, json.NewDecoder(otherKey.Body).Decode(&otherRevisions))
+	otherKey.Body.Close()
+	require.Len(t, otherRevisions, 1)
+
+	restore := map[string]struct {
+		revision            int64
+		expectedStatus      int
+		expectedTranslation string
+	}{
+		"revision of another key": {
+			revision:       otherRevisions[0].Id,
+			expectedStatus: 400,
+		},
+		"unknown revision": {
+			revision:       999,
+			expectedStatus: 404,
+		},
+		"original revision": {
+			revision:            original.Id,
+			expectedStatus:      200,
+			expectedTranslation: "Translation Service",
+		},
+	}
+
+	for name, tc := range restore {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.PostTranslationKeyRestore(ctx, "test_lk_0", &api.PostTranslationKeyRestoreParams{XAuthor: ptr("bob")}, api.RestoreInput{Revision: tc.revision})
+			require.NoError(t, err)
+			defer result.Body.Close()
+
+			require.Equal(t, tc.expectedStatus, result.StatusCode)
+			if result.StatusCode != 200 {
+				return
+			}
+
+			var restored api.Translation
+			require.NoError(t, json.NewDecoder(result.Body).Decode(&restored))
+			assert.Equal(t, tc.expectedTranslation, *restored.Translation)
+		})
+	}
+
+	t.Run("restore is recorded", func(t *testing.T) {
+		revisions := history(t)
+		require.Len(t, revisions, 3)
+		assert.Equal(t, "Translation Service", *revisions[0].Translation)
+		assert.Equal(t, "bob", *revisions[0].ChangedBy)
+	})
+}
+
+func TestDraftWorkflowREST(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	server, client, teardownServer := setupTestRESTServer()
+
+	defer teardownServer(server)
+
+	ctx := context.Background()
+
+	saveResult, err := client.PutTranslationKeyDraft(ctx, "test_lk_0", &api.PutTranslationKeyDraftParams{Locale: "en_GB", XAuthor: ptr("alice")}, api.TranslationValue{Translation: "Draft text"})
+	require.NoError(t, err)
+	saveResult.Body.Close()
+	require.Equal(t, 200, saveResult.StatusCode)
+
+	newResult, err := client.PutTranslationKeyDraft(ctx, "test_lk_new", &api.PutTranslationKeyDraftParams{Locale: "en_GB"}, api.TranslationValue{Translation: "Unpublished"})
+	require.NoError(t, err)
+	newResult.Body.Close()
+	require.Equal(t, 200, newResult.StatusCode)
+
+	reads := map[string]struct {
+		languageKey         string
+		preview             *bool
+		release             *string
+		expectedStatus      int
+		expectedTranslation string
+		expectedState       api.TranslationStatus
+	}{
+		"published value by default": {
+			languageKey:         "test_lk_0",
+			expectedStatus:      200,
+			expectedTranslation: "Translation Service",
+			expectedState:       api.TranslationStatusPublished,
+		},
+		"draft in preview": {
+			languageKey:         "test_lk_0",
+			preview:             ptr(true),
+			expectedStatus:      200,
+			expectedTranslation: "Draft text",
+			expectedState:       api.TranslationStatusDraft,
+		},
+		"unpublished key by default": {
+			languageKey:    "test_lk_new",
+			expectedStatus: 404,
+		},
+		"unpublished key in preview": {
+			languageKey:         "test_lk_new",
+			preview:             ptr(true),
+			expectedStatus:      200,
+			expectedTranslation: "Unpublished",
+			expectedState:       api.TranslationStatusDraft,
+		},
+		"published key in preview": {
+			languageKey:         "test_lk_1",
+			preview:             ptr(true),
+			expectedStatus:      200,
+			expectedTranslation: "Another one",
+			expectedState:       api.TranslationStatusPublished,
+		},
+		"preview of a release": {
+			languageKey:    "test_lk_0",
+			preview:        ptr(true),
+			release:        ptr("latest"),
+			expectedStatus: 400,
+		},
+	}
+
+	for name, tc := range reads {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.GetTranslationKey(ctx, tc.languageKey, &api.GetTranslationKeyParams{Locale: ptr("en_GB"), Preview: tc.preview, Release: tc.release})
+			require.NoError(t, err)
+			defer result.Body.Close()
+
+			require.Equal(t, tc.expectedStatus, result.StatusCode)
+			if result.StatusCode != 200 {
+				return
+			}
+
+			var body api.Translation
+			require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+			assert.Equal(t, tc.expectedTranslation, *body.Translation)
+			assert.Equal(t, tc.expectedState, *body.Status)
+		})
+	}
+
+	t.Run("preview bundle", func(t *testing.T) {
+		result, err := client.GetTranslations(ctx, &api.GetTranslationsParams{Locale: ptr("en_GB"), Preview: ptr(true)})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+		assert.Equal(t, "private, no-cache", result.Header.Get("Cache-Control"))
+
+		var body []api.Translation
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+		assert.Len(t, body, 3)
+	})
+
+	submitParams := &api.PostTranslationKeyDraftSubmitParams{Locale: "en_GB"}
+	approveParams := &api.PostTranslationKeyDraftApproveParams{Locale: "en_GB", XAuthor: ptr("bob")}
+	rejectParams := &api.PostTranslationKeyDraftRejectParams{Locale: "en_GB", XAuthor: ptr("bob")}
+	publishParams := &api.PostTranslationKeyDraftPublishParams{Locale: "en_GB", XAuthor: ptr("carol")}
+
+	decodeDraft := func(t *testing.T, result *http.Response, expectedStatus int) api.Draft {
+		defer result.Body.Close()
+
+		require.Equal(t, expectedStatus, result.StatusCode)
+
+		var draft api.Draft
+		if expectedStatus == 200 {
+			require.NoError(t, json.NewDecoder(result.Body).Decode(&draft))
+		}
+		return draft
+	}
+
+	t.Run("publish before approval", func(t *testing.T) {
+		result, err := client.PostTranslationKeyDraftPublish(ctx, "test_lk_0", publishParams)
+		require.NoError(t, err)
+		decodeDraft(t, result, 409)
+	})
+
+	t.Run("approve before submission", func(t *testing.T) {
+		result, err := client.PostTranslationKeyDraftApprove(ctx, "test_lk_0", approveParams, api.ReviewInput{})
+		require.NoError(t, err)
+		decodeDraft(t, result, 409)
+	})
+
+	t.Run("submit", func(t *testing.T) {
+		result, err := client.PostTranslationKeyDraftSubmit(ctx, "test_lk_0", submitParams)
+		require.NoError(t, err)
+		draft := decodeDraft(t, result, 200)
+		assert.Equal(t, api.TranslationStatusInReview, draft.Status)
+	})
+
+	t.Run("edit in review", func(t *testing.T) {
+		result, err := client.PutTranslationKeyDraft(ctx, "test_lk_0", &api.PutTranslationKeyDraftParams{Locale: "en_GB"}, api.TranslationValue{Translation: "Sneaky edit"})
+		require.NoError(t, err)
+		decodeDraft(t, result, 409)
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		result, err := client.PostTranslationKeyDraftReject(ctx, "test_lk_0", rejectParams, api.ReviewInput{Comment: ptr("Too long")})
+		require.NoError(t, err)
+		draft := decodeDraft(t, result, 200)
+		assert.Equal(t, api.TranslationStatusDraft, draft.Status)
+		assert.Equal(t, "Too long", *draft.Comment)
+		assert.Equal(t, "alice", *draft.ChangedBy)
+	})
+
+	t.Run("list drafts", func(t *testing.T) {
+		result, err := client.GetDrafts(ctx, &api.GetDraftsParams{Status: ptr(api.TranslationStatusDraft)})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		var drafts []api.Draft
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&drafts))
+		assert.Len(t, drafts, 2)
+	})
+
+	t.Run("approve", func(t *testing.T) {
+		result, err := client.PostTranslationKeyDraftSubmit(ctx, "test_lk_0", submitParams)
+		require.NoError(t, err)
+		decodeDraft(t, result, 200)
+
+		result, err = client.PostTranslationKeyDraftApprove(ctx, "test_lk_0", approveParams, api.ReviewInput{Comment: ptr("LGTM")})
+		require.NoError(t, err)
+		draft := decodeDraft(t, result, 200)
+		assert.Equal(t, api.TranslationStatusApproved, draft.Status)
+		assert.Equal(t, "bob", *draft.ReviewedBy)
+	})
+
+	t.Run("publish", func(t *testing.T) {
+		result, err := client.PostTranslationKeyDraftPublish(ctx, "test_lk_0", publishParams)
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+
+		var published api.Translation
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&published))
+		assert.Equal(t, "Draft text", *published.Translation)
+		assert.Equal(t, api.TranslationStatusPublished, *published.Status)
+
+		draftResult, err := client.GetTranslationKeyDraft(ctx, "test_lk_0", &api.GetTranslationKeyDraftParams{Locale: "en_GB"})
+		require.NoError(t, err)
+		decodeDraft(t, draftResult, 404)
+
+		history, err := client.GetTranslationKeyHistory(ctx, "test_lk_0", &api.GetTranslationKeyHistoryParams{Locale: "en_GB"})
+		require.NoError(t, err)
+		defer history.Body.Close()
+
+		var revisions []api.Revision
+		require.NoError(t, json.NewDecoder(history.Body).Decode(&revisions))
+		require.NotEmpty(t, revisions)
+		assert.Equal(t, "carol", *revisions[0].ChangedBy)
+	})
+}
+
+func TestRequireReviewREST(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	server, client, teardownServer := setupReviewedRESTServer()
+
+	defer teardownServer(server)
+
+	ctx := context.Background()
+
+	t.Run("direct writes are rejected", func(t *testing.T) {
+		created, err := client.PostTranslation(ctx, &api.PostTranslationParams{}, api.TranslationInput{LanguageKey: "test_lk_new", Locale: "en_GB", Translation: "New one"})
+		require.NoError(t, err)
+		defer created.Body.Close()
+		assert.Equal(t, 403, created.StatusCode)
+
+		replaced, err := client.PutTranslationKey(ctx, "test_lk_1", &api.PutTranslationKeyParams{Locale: "en_GB"}, api.TranslationValue{Translation: "Replaced"})
+		require.NoError(t, err)
+		defer replaced.Body.Close()
+		assert.Equal(t, 403, replaced.StatusCode)
+
+		value := "Patched"
+		patched, err := client.PatchTranslationKey(ctx, "test_lk_1", &api.PatchTranslationKeyParams{Locale: "en_GB"}, api.TranslationPatch{Translation: &value})
+		require.NoError(t, err)
+		defer patched.Body.Close()
+		assert.Equal(t, 403, patched.StatusCode)
+
+		deleted, err := client.DeleteTranslationKey(ctx, "test_lk_1", &api.DeleteTranslationKeyParams{Locale: "en_GB"})
+		require.NoError(t, err)
+		defer deleted.Body.Close()
+		assert.Equal(t, 403, deleted.StatusCode)
+	})
+
+	t.Run("imports are rejected", func(t *testing.T) {
+		gettext, err := client.PostGettextLocaleWithBody(ctx, "en_GB", &api.PostGettextLocaleParams{}, "text/x-gettext-translation", strings.NewReader("msgid \"test_lk_1\"\nmsgstr \"Imported\"\n"))
+		require.NoError(t, err)
+		defer gettext.Body.Close()
+		assert.Equal(t, 403, gettext.StatusCode)
+
+		var document strings.Builder
+		require.NoError(t, translation.WriteXLIFF(&document, &translation.XLIFFDocument{
+			Version:      translation.XLIFF20,
+			SourceLocale: "en-GB",
+			TargetLocale: "en-GB",
+			Units:        []translation.XLIFFUnit{{Key: "test_lk_1", Source: "Imported", Target: ptr("Imported")}},
+		}))
+
+		xliff, err := client.PostXliffLocaleWithBody(ctx, "en_GB", &api.PostXliffLocaleParams{}, "application/xliff+xml", strings.NewReader(document.String()))
+		require.NoError(t, err)
+		defer xliff.Body.Close()
+		assert.Equal(t, 403, xliff.StatusCode)
+
+		table := "language_key,en_GB\ntest_lk_1,Imported\n"
+
+		dryRun, err := client.PostCsvWithBody(ctx, &api.PostCsvParams{DryRun: ptr(true)}, "text/csv", strings.NewReader(table))
+		require.NoError(t, err)
+		defer dryRun.Body.Close()
+		assert.Equal(t, 200, dryRun.StatusCode)
+
+		csv, err := client.PostCsvWithBody(ctx, &api.PostCsvParams{}, "text/csv", strings.NewReader(table))
+		require.NoError(t, err)
+		defer csv.Body.Close()
+		assert.Equal(t, 403, csv.StatusCode)
+	})
+
+	t.Run("reviewed drafts are published", func(t *testing.T) {
+		saved, err := client.PutTranslationKeyDraft(ctx, "test_lk_1", &api.PutTranslationKeyDraftParams{Locale: "en_GB"}, api.TranslationValue{Translation: "Reviewed"})
+		require.NoError(t, err)
+		saved.Body.Close()
+		require.Equal(t, 200, saved.StatusCode)
+
+		submitted, err := client.PostTranslationKeyDraftSubmit(ctx, "test_lk_1", &api.PostTranslationKeyDraftSubmitParams{Locale: "en_GB"})
+		require.NoError(t, err)
+		submitted.Body.Close()
+		require.Equal(t, 200, submitted.StatusCode)
+
+		approved, err := client.PostTranslationKeyDraftApprove(ctx, "test_lk_1", &api.PostTranslationKeyDraftApproveParams{Locale: "en_GB"}, api.ReviewInput{})
+		require.NoError(t, err)
+		approved.Body.Close()
+		require.Equal(t, 200, approved.StatusCode)
+
+		published, err := client.PostTranslationKeyDraftPublish(ctx, "test_lk_1", &api.PostTranslationKeyDraftPublishParams{Locale: "en_GB"})
+		require.NoError(t, err)
+		published.Body.Close()
+		require.Equal(t, 200, published.StatusCode)
+
+		result, err := client.GetTranslationKey(ctx, "test_lk_1", &api.GetTranslationKeyParams{Locale: ptr("en_GB")})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		var body api.Translation
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+		assert.Equal(t, "Reviewed", *body.Translation)
+	})
+}
+
+func TestNamespaceREST(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	server, client, teardownServer := setupTestRESTServer()
+
+	defer teardownServer(server)
+
+	ctx := context.Background()
+
+	creations := map[string]struct {
+		body           api.NamespaceInput
+		expectedStatus int
+	}{
+		"unknown locale": {
+			body:           api.NamespaceInput{Name: "mobile", Locales: &[]string{"fr_FR"}},
+			expectedStatus: 400,
+		},
+		"invalid name": {
+			body:           api.NamespaceInput{Name: "Web App"},
+			expectedStatus: 400,
+		},
+		"default locale not enabled": {
+			body:           api.NamespaceInput{Name: "mobile", DefaultLocale: ptr("en_GB"), Locales: &[]string{"de_DE"}},
+			expectedStatus: 400,
+		},
+		"existing namespace": {
+			body:           api.NamespaceInput{Name: "default"},
+			expectedStatus: 409,
+		},
+	}
+
+	for name, tc := range creations {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.PostNamespace(ctx, tc.body)
+			require.NoError(t, err)
+			defer result.Body.Close()
+
+			assert.Equal(t, tc.expectedStatus, result.StatusCode)
+		})
+	}
+
+	t.Run("create namespace", func(t *testing.T) {
+		result, err := client.PostNamespace(ctx, api.NamespaceInput{Name: "web", Description: ptr("Web app"), DefaultLocale: ptr("de_DE"), Locales: &[]string{"de_DE"}})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 201, result.StatusCode)
+
+		var body api.Namespace
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+		assert.Equal(t, "web", body.Name)
+		assert.Equal(t, "de_DE", *body.DefaultLocale)
+		assert.Equal(t, []string{"de_DE"}, body.Locales)
+	})
+
+	writes := map[string]struct {
+		namespace      string
+		locale         string
+		expectedStatus int
+	}{
+		"same key as in the default namespace": {
+			namespace:      "web",
+			locale:         "de_DE",
+			expectedStatus: 201,
+		},
+		"locale not enabled in the namespace": {
+			namespace:      "web",
+			locale:         "en_GB",
+			expectedStatus: 400,
+		},
+		"unknown namespace": {
+			namespace:      "unknown",
+			locale:         "de_DE",
+			expectedStatus: 404,
+		},
+	}
+
+	for name, tc := range writes {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.PutNamespacesNamespaceTranslationKey(ctx, tc.namespace, "test_lk_0", &api.PutNamespacesNamespaceTranslationKeyParams{Locale: tc.locale}, api.TranslationValue{Translation: "Web-Dienst"})
+			require.NoError(t, err)
+			defer result.Body.Close()
+
+			assert.Equal(t, tc.expectedStatus, result.StatusCode)
+		})
+	}
+
+	reads := map[string]struct {
+		namespace           string
+		locale              *string
+		expectedStatus      int
+		expectedTranslation string
+	}{
+		"default locale of the namespace": {
+			namespace:           "web",
+			expectedStatus:      200,
+			expectedTranslation: "Web-Dienst",
+		},
+		"default namespace is unchanged": {
+			namespace:           "default",
+			locale:              ptr("de_DE"),
+			expectedStatus:      200,
+			expectedTranslation: "Übersetzungs-Dienst",
+		},
+		"locale not enabled in the namespace": {
+			namespace:      "web",
+			locale:         ptr("en_GB"),
+			expectedStatus: 400,
+		},
+		"unknown namespace": {
+			namespace:      "unknown",
+			expectedStatus: 404,
+		},
+	}
+
+	for name, tc := range reads {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.GetNamespacesNamespaceTranslationKey(ctx, tc.namespace, "test_lk_0", &api.GetNamespacesNamespaceTranslationKeyParams{Locale: tc.locale})
+			require.NoError(t, err)
+			defer result.Body.Close()
+
+			require.Equal(t, tc.expectedStatus, result.StatusCode)
+			if result.StatusCode != 200 {
+				return
+			}
+
+			var body api.Translation
+			require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+			assert.Equal(t, tc.expectedTranslation, *body.Translation)
+		})
+	}
+
+	t.Run("list translations of the namespace", func(t *testing.T) {
+		result, err := client.GetNamespacesNamespaceTranslations(ctx, "web", &api.GetNamespacesNamespaceTranslationsParams{})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+		assert.Equal(t, "de-DE", result.Header.Get("Content-Language"))
+
+		var body []api.Translation
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+		require.Len(t, body, 1)
+		assert.Equal(t, "Web-Dienst", *body[0].Translation)
+	})
+
+	t.Run("list namespaces", func(t *testing.T) {
+		result, err := client.GetNamespaces(ctx)
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		var body []api.Namespace
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+		require.Len(t, body, 2)
+		assert.Equal(t, "default", body[0].Name)
+		assert.Equal(t, "web", body[1].Name)
+	})
+
+	t.Run("enable all locales", func(t *testing.T) {
+		result, err := client.PatchNamespaceNamespace(ctx, "web", api.NamespacePatch{Locales: &[]string{}, DefaultLocale: ptr("")})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+
+		var body api.Namespace
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+		assert.Nil(t, body.DefaultLocale)
+		assert.Empty(t, body.Locales)
+
+		write, err := client.PutNamespacesNamespaceTranslationKey(ctx, "web", "test_lk_0", &api.PutNamespacesNamespaceTranslationKeyParams{Locale: "en_GB"}, api.TranslationValue{Translation: "Web service"})
+		require.NoError(t, err)
+		write.Body.Close()
+		assert.Equal(t, 201, write.StatusCode)
+	})
+
+	t.Run("restore into a locale no longer enabled", func(t *testing.T) {
+		history, err := client.GetNamespacesNamespaceTranslationKeyHistory(ctx, "web", "test_lk_0", &api.GetNamespacesNamespaceTranslationKeyHistoryParams{Locale: "en_GB"})
+		require.NoError(t, err)
+		defer history.Body.Close()
+
+		var revisions []api.Revision
+		require.NoError(t, json.NewDecoder(history.Body).Decode(&revisions))
+		require.Len(t, revisions, 1)
+
+		disable, err := client.PatchNamespaceNamespace(ctx, "web", api.NamespacePatch{Locales: &[]string{"de_DE"}})
+		require.NoError(t, err)
+		disable.Body.Close()
+		require.Equal(t, 200, disable.StatusCode)
+
+		restore, err := client.PostNamespacesNamespaceTranslationKeyRestore(ctx, "web", "test_lk_0", &api.PostNamespacesNamespaceTranslationKeyRestoreParams{}, api.RestoreInput{Revision: revisions[0].Id})
+		require.NoError(t, err)
+		restore.Body.Close()
+		assert.Equal(t, 400, restore.StatusCode)
+
+		enable, err := client.PatchNamespaceNamespace(ctx, "web", api.NamespacePatch{Locales: &[]string{}})
+		require.NoError(t, err)
+		enable.Body.Close()
+		require.Equal(t, 200, enable.StatusCode)
+	})
+
+	deletions := map[string]struct {
+		namespace      string
+		expectedStatus int
+	}{
+		"default namespace": {
+			namespace:      "default",
+			expectedStatus: 400,
+		},
+		"namespace with translations": {
+			namespace:      "web",
+			expectedStatus: 409,
+		},
+		"unknown namespace": {
+			namespace:      "unknown",
+			expectedStatus: 404,
+		},
+	}
+
+	for name, tc := range deletions {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.DeleteNamespaceNamespace(ctx, tc.namespace)
+			require.NoError(t, err)
+			defer result.Body.Close()
+
+			assert.Equal(t, tc.expectedStatus, result.StatusCode)
+		})
+	}
+
+	t.Run("delete empty namespace", func(t *testing.T) {
+		for _, locale := range []string{"de_DE", "en_GB"} {
+			result, err := client.DeleteNamespacesNamespaceTranslationKey(ctx, "web", "test_lk_0", &api.DeleteNamespacesNamespaceTranslationKeyParams{Locale: locale})
+			require.NoError(t, err)
+			result.Body.Close()
+			require.Equal(t, 204, result.StatusCode)
+		}
+
+		result, err := client.DeleteNamespaceNamespace(ctx, "web")
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		assert.Equal(t, 204, result.StatusCode)
+	})
+}
+
+func TestNamespacedWorkflowREST(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	server, client, teardownServer := setupTestRESTServer()
+
+	defer teardownServer(server)
+
+	ctx := context.Background()
+
+	createResult, err := client.PostNamespace(ctx, api.NamespaceInput{Name: "web", Locales: &[]string{"de_DE"}})
+	require.NoError(t, err)
+	createResult.Body.Close()
+	require.Equal(t, 201, createResult.StatusCode)
+
+	changesStatus, initial := func() (int, api.TranslationDelta) {
+		result, err := client.GetNamespacesNamespaceTranslationsChanges(ctx, "web", &api.GetNamespacesNamespaceTranslationsChangesParams{Locale: ptr("de_DE")})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		var body api.TranslationDelta
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+		return result.StatusCode, body
+	}()
+	require.Equal(t, 200, changesStatus)
+	assert.Empty(t, initial.Translations)
+
+	saveResult, err := client.PutNamespacesNamespaceTranslationKeyDraft(ctx, "web", "test_lk_0", &api.PutNamespacesNamespaceTranslationKeyDraftParams{Locale: "de_DE", XAuthor: ptr("alice")}, api.TranslationValue{Translation: "Web-Dienst"})
+	require.NoError(t, err)
+	saveResult.Body.Close()
+	require.Equal(t, 200, saveResult.StatusCode)
+
+	drafts := map[string]struct {
+		namespace      string
+		expectedStatus int
+		expectedDrafts int
+	}{
+		"drafts of the namespace": {
+			namespace:      "web",
+			expectedStatus: 200,
+			expectedDrafts: 1,
+		},
+		"drafts of the default namespace": {
+			namespace:      "default",
+			expectedStatus: 200,
+			expectedDrafts: 0,
+		},
+		"unknown namespace": {
+			namespace:      "unknown",
+			expectedStatus: 404,
+		},
+	}
+
+	for name, tc := range drafts {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.GetNamespacesNamespaceDrafts(ctx, tc.namespace, &api.GetNamespacesNamespaceDraftsParams{})
+			require.NoError(t, err)
+			defer result.Body.Close()
+
+			require.Equal(t, tc.expectedStatus, result.StatusCode)
+			if result.StatusCode != 200 {
+				return
+			}
+
+			var body []api.Draft
+			require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+			assert.Len(t, body, tc.expectedDrafts)
+		})
+	}
+
+	t.Run("publish draft of the namespace", func(t *testing.T) {
+		submitResult, err := client.PostNamespacesNamespaceTranslationKeyDraftSubmit(ctx, "web", "test_lk_0", &api.PostNamespacesNamespaceTranslationKeyDraftSubmitParams{Locale: "de_DE"})
+		require.NoError(t, err)
+		submitResult.Body.Close()
+		require.Equal(t, 200, submitResult.StatusCode)
+
+		approveResult, err := client.PostNamespacesNamespaceTranslationKeyDraftApprove(ctx, "web", "test_lk_0", &api.PostNamespacesNamespaceTranslationKeyDraftApproveParams{Locale: "de_DE", XAuthor: ptr("bob")}, api.ReviewInput{})
+		require.NoError(t, err)
+		approveResult.Body.Close()
+		require.Equal(t, 200, approveResult.StatusCode)
+
+		publishResult, err := client.PostNamespacesNamespaceTranslationKeyDraftPublish(ctx, "web", "test_lk_0", &api.PostNamespacesNamespaceTranslationKeyDraftPublishParams{Locale: "de_DE"})
+		require.NoError(t, err)
+		defer publishResult.Body.Close()
+		require.Equal(t, 200, publishResult.StatusCode)
+
+		var body api.Translation
+		require.NoError(t, json.NewDecoder(publishResult.Body).Decode(&body))
+		assert.Equal(t, "Web-Dienst", *body.Translation)
+	})
+
+	reads := map[string]struct {
+		namespace           string
+		expectedTranslation string
+	}{
+		"published in the namespace": {
+			namespace:           "web",
+			expectedTranslation: "Web-Dienst",
+		},
+		"default namespace is unchanged": {
+			namespace:           "default",
+			expectedTranslation: "Übersetzungs-Dienst",
+		},
+	}
+
+	for name, tc := range reads {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.GetNamespacesNamespaceTranslationKey(ctx, tc.namespace, "test_lk_0", &api.GetNamespacesNamespaceTranslationKeyParams{Locale: ptr("de_DE")})
+			require.NoError(t, err)
+			defer result.Body.Close()
+
+			require.Equal(t, 200, result.StatusCode)
+
+			var body api.Translation
+			require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+			assert.Equal(t, tc.expectedTranslation, *body.Translation)
+		})
+	}
+
+	t.Run("history of the namespace", func(t *testing.T) {
+		result, err := client.GetNamespacesNamespaceTranslationKeyHistory(ctx, "web", "test_lk_0", &api.GetNamespacesNamespaceTranslationKeyHistoryParams{Locale: "de_DE"})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+
+		var revisions []api.Revision
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&revisions))
+		require.Len(t, revisions, 1)
+		assert.Equal(t, "Web-Dienst", *revisions[0].Translation)
+
+		// Revisions of other namespaces are not found.
+		restoreResult, err := client.PostNamespacesNamespaceTranslationKeyRestore(ctx, "default", "test_lk_0", &api.PostNamespacesNamespaceTranslationKeyRestoreParams{}, api.RestoreInput{Revision: revisions[0].Id})
+		require.NoError(t, err)
+		restoreResult.Body.Close()
+		assert.Equal(t, 404, restoreResult.StatusCode)
+	})
+
+	t.Run("changes of the namespace", func(t *testing.T) {
+		result, err := client.GetNamespacesNamespaceTranslationsChanges(ctx, "web", &api.GetNamespacesNamespaceTranslationsChangesParams{Locale: ptr("de_DE"), Since: &initial.Version})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+
+		var body api.TranslationDelta
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+		require.Len(t, body.Translations, 1)
+		assert.Equal(t, "Web-Dienst", *body.Translations[0].Translation)
+	})
+}
+
+func TestRenderREST(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	server, client, teardownServer := setupTestRESTServer()
+
+	defer teardownServer(server)
+
+	ctx := context.Background()
+
+	messages := map[string]string{
+		"en_GB": "{gender, select, female {She has} male {He has} other {They have}} {count, plural, =0 {no items} one {# item} other {# items}} in the cart",
+		"de_DE": "{count, plural, one {# Artikel} other {# Artikel}} für {total, number, ::currency/EUR}",
+	}
+
+	for locale, message := range messages {
+		result, err := client.PutTranslationKey(ctx, "cart_summary", &api.PutTranslationKeyParams{Locale: locale}, api.TranslationValue{Translation: message})
+		require.NoError(t, err)
+		result.Body.Close()
+		require.Equal(t, 201, result.StatusCode)
+	}
+
+	result, err := client.PutTranslationKey(ctx, "broken_message", &api.PutTranslationKeyParams{Locale: "en_GB"}, api.TranslationValue{Translation: "{count, plural, one {# item}"})
+	require.NoError(t, err)
+	result.Body.Close()
+	require.Equal(t, 201, result.StatusCode)
+
+	result, err = client.PostLocale(ctx, api.LocaleInput{Code: "ja_JP"})
+	require.NoError(t, err)
+	result.Body.Close()
+	require.Equal(t, 201, result.StatusCode)
+
+	departures := map[string]string{
+		"en_GB": "Departs {departure, date, ::MMMMd} at {departure, time, short}",
+		"ja_JP": "{departure, date, long}に出発",
+	}
+
+	for locale, message := range departures {
+		result, err := client.PutTranslationKey(ctx, "departure", &api.PutTranslationKeyParams{Locale: locale}, api.TranslationValue{Translation: message})
+		require.NoError(t, err)
+		result.Body.Close()
+		require.Equal(t, 201, result.StatusCode)
+	}
+
+	testCases := map[string]struct {
+		key             string
+		locale          string
+		args            map[string]any
+		timeZone        *string
+		expectedStatus  int
+		expectedText    string
+		expectedMessage string
+	}{
+		"plural one": {
+			key:            "cart_summary",
+			locale:         "en_GB",
+			args:           map[string]any{"gender": "female", "count": 1},
+			expectedStatus: 200,
+			expectedText:   "She has 1 item in the cart",
+		},
+		"plural exact match": {
+			key:            "cart_summary",
+			locale:         "en_GB",
+			args:           map[string]any{"gender": "other", "count": 0},
+			expectedStatus: 200,
+			expectedText:   "They have no items in the cart",
+		},
+		"localized numbers": {
+			key:            "cart_summary",
+			locale:         "de_DE",
+			args:           map[string]any{"count": 1200, "total": 1234.5},
+			expectedStatus: 200,
+			expectedText:   "1.200 Artikel für € 1.234,50",
+		},
+		"missing argument": {
+			key:             "cart_summary",
+			locale:          "en_GB",
+			args:            map[string]any{"count": 2},
+			expectedStatus:  400,
+			expectedMessage: `missing argument "gender"`,
+		},
+		"mistyped argument": {
+			key:             "cart_summary",
+			locale:          "en_GB",
+			args:            map[string]any{"gender": "male", "count": "two"},
+			expectedStatus:  400,
+			expectedMessage: `plural argument "count" must be a number, got string`,
+		},
+		"invalid message": {
+			key:             "broken_message",
+			locale:          "en_GB",
+			args:            map[string]any{"count": 2},
+			expectedStatus:  422,
+			expectedMessage: "unclosed {",
+		},
+		"unknown key": {
+			key:            "unknown",
+			locale:         "en_GB",
+			expectedStatus: 404,
+		},
+		"date in UTC": {
+			key:            "departure",
+			locale:         "en_GB",
+			args:           map[string]any{"departure": "2025-03-05T23:30:00+01:00"},
+			expectedStatus: 200,
+			expectedText:   "Departs 5 March at 22:30",
+		},
+		"date in time zone": {
+			key:            "departure",
+			locale:         "en_GB",
+			args:           map[string]any{"departure": "2025-03-05T23:30:00Z"},
+			timeZone:       ptr("Asia/Tokyo"),
+			expectedStatus: 200,
+			expectedText:   "Departs 6 March at 08:30",
+		},
+		"unknown time zone": {
+			key:             "departure",
+			locale:          "en_GB",
+			args:            map[string]any{"departure": "2025-03-05T23:30:00Z"},
+			timeZone:        ptr("Mars/Olympus_Mons"),
+			expectedStatus:  400,
+			expectedMessage: `unknown time zone "Mars/Olympus_Mons"`,
+		},
+		"date in a locale without date symbols": {
+			key:            "departure",
+			locale:         "ja_JP",
+			args:           map[string]any{"departure": "2025-03-05T23:30:00Z"},
+			expectedStatus: 200,
+			expectedText:   "2025-03-05に出発",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.PostTranslationKeyRender(ctx, tc.key, &api.PostTranslationKeyRenderParams{Locale: &tc.locale}, api.RenderInput{Args: &tc.args, TimeZone: tc.timeZone})
+			require.NoError(t, err)
+			defer result.Body.Close()
+
+			require.Equal(t, tc.expectedStatus, result.StatusCode)
+
+			switch {
+			case tc.expectedText != "":
+				var body api.RenderResult
+				require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+				assert.Equal(t, tc.expectedText, body.Text)
+				assert.Equal(t, tc.locale, body.ResolvedLocale)
+			case tc.expectedMessage != "":
+				var body api.Error
+				require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+				assert.Contains(t, body.Message, tc.expectedMessage)
+			}
+		})
+	}
+}
+
+func TestPluralFormsREST(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	server, client, teardownServer := setupTestRESTServer()
+
+	defer teardownServer(server)
+
+	ctx := context.Background()
+
+	writes := map[string]struct {
+		forms          api.PluralForms
+		expectedStatus int
+	}{
+		"missing category": {
+			forms:          api.PluralForms{Other: ptr("items")},
+			expectedStatus: 400,
+		},
+		"category not used by the locale": {
+			forms:          api.PluralForms{One: ptr("item"), Few: ptr("items"), Other: ptr("items")},
+			expectedStatus: 400,
+		},
+		"empty form": {
+			forms:          api.PluralForms{One: ptr(""), Other: ptr("items")},
+			expectedStatus: 400,
+		},
+		"form with unquoted brace": {
+			forms:          api.PluralForms{One: ptr("One item"), Other: ptr("# items {")},
+			expectedStatus: 400,
+		},
+	}
+
+	for name, tc := range writes {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.PutTranslationKey(ctx, "cart_items", &api.PutTranslationKeyParams{Locale: "en_GB"}, api.TranslationValue{PluralForms: &tc.forms})
+			require.NoError(t, err)
+			defer result.Body.Close()
+
+			assert.Equal(t, tc.expectedStatus, result.StatusCode)
+		})
+	}
+
+	t.Run("create plural forms", func(t *testing.T) {
+		result, err := client.PutTranslationKey(ctx, "cart_items", &api.PutTranslationKeyParams{Locale: "en_GB"}, api.TranslationValue{PluralForms: &api.PluralForms{One: ptr("One item"), Other: ptr("Several items")}})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 201, result.StatusCode)
+
+		var body api.Translation
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+		assert.Equal(t, "Several items", *body.Translation)
+		assert.Equal(t, "One item", *body.PluralForms.One)
+	})
+
+	reads := map[string]struct {
+		count               *string
+		expectedStatus      int
+		expectedTranslation string
+	}{
+		"without count": {
+			expectedStatus:      200,
+			expectedTranslation: "Several items",
+		},
+		"one": {
+			count:               ptr("1"),
+			expectedStatus:      200,
+			expectedTranslation: "One item",
+		},
+		"other": {
+			count:               ptr("3"),
+			expectedStatus:      200,
+			expectedTranslation: "Several items",
+		},
+		"visible fraction digits": {
+			count:               ptr("1.0"),
+			expectedStatus:      200,
+			expectedTranslation: "Several items",
+		},
+		"invalid count": {
+			count:          ptr("-1"),
+			expectedStatus: 400,
+		},
+	}
+
+	for name, tc := range reads {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.GetTranslationKey(ctx, "cart_items", &api.GetTranslationKeyParams{Locale: ptr("en_GB"), Count: tc.count})
+			require.NoError(t, err)
+			defer result.Body.Close()
+
+			require.Equal(t, tc.expectedStatus, result.StatusCode)
+			if tc.expectedStatus != 200 {
+				return
+			}
+
+			var body api.Translation
+			require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+			assert.Equal(t, tc.expectedTranslation, *body.Translation)
+			assert.NotNil(t, body.PluralForms)
+		})
+	}
+
+	t.Run("count on a plain translation", func(t *testing.T) {
+		result, err := client.GetTranslationKey(ctx, "test_lk_0", &api.GetTranslationKeyParams{Locale: ptr("en_GB"), Count: ptr("1")})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+
+		var body api.Translation
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+		assert.Equal(t, "Translation Service", *body.Translation)
+		assert.Nil(t, body.PluralForms)
+	})
+
+	patch := func(t *testing.T, body api.TranslationPatch) api.Translation {
+		result, err := client.PatchTranslationKey(ctx, "cart_items", &api.PatchTranslationKeyParams{Locale: "en_GB"}, body)
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+
+		var patched api.Translation
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&patched))
+		require.NotNil(t, patched.PluralForms)
+		return patched
+	}
+
+	t.Run("translation replaces the other form", func(t *testing.T) {
+		patched := patch(t, api.TranslationPatch{Translation: ptr("Items")})
+		assert.Equal(t, "Items", *patched.Translation)
+		assert.Equal(t, "One item", *patched.PluralForms.One)
+		assert.Equal(t, "Items", *patched.PluralForms.Other)
+	})
+
+	t.Run("plural forms are merged", func(t *testing.T) {
+		patched := patch(t, api.TranslationPatch{PluralForms: &api.PluralForms{One: ptr("A single item")}})
+		assert.Equal(t, "Items", *patched.Translation)
+		assert.Equal(t, "A single item", *patched.PluralForms.One)
+		assert.Equal(t, "Items", *patched.PluralForms.Other)
+	})
+
+	t.Run("plain translation removes the plural forms", func(t *testing.T) {
+		result, err := client.PutTranslationKey(ctx, "cart_items", &api.PutTranslationKeyParams{Locale: "en_GB"}, api.TranslationValue{Translation: "Items"})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+
+		var body api.Translation
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+		assert.Equal(t, "Items", *body.Translation)
+		assert.Nil(t, body.PluralForms)
+	})
+}
+
+const gettextCatalog = `msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "test_lk_0"
+msgstr "Übersetzungs-Dienst"
+
+# Needs a native speaker
+#, fuzzy
+msgid "test_lk_1"
+msgstr "Noch ein anderer"
+
+msgid "test_lk_2"
+msgstr "Noch einer!"
+
+msgctxt "menu"
+msgid "file"
+msgstr "Datei"
+
+msgid "cart_items"
+msgid_plural "cart_items"
+msgstr[0] "Ein Artikel"
+msgstr[1] "# Artikel"
+
+msgid "untranslated"
+msgstr ""
+
+#~ msgid "obsolete"
+#~ msgstr "Veraltet"
+`
+
+func TestGettextREST(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	server, client, teardownServer := setupTestRESTServer()
+
+	defer teardownServer(server)
+
+	ctx := context.Background()
+
+	invalidImports := map[string]struct {
+		locale         string
+		catalog        string
+		expectedStatus int
+	}{
+		"unreadable catalog": {
+			locale:         "de_DE",
+			catalog:        "msgid \"a\"\nmsgstr \"b",
+			expectedStatus: 400,
+		},
+		"language mismatch": {
+			locale:         "en_GB",
+			catalog:        gettextCatalog,
+			expectedStatus: 400,
+		},
+		"unsupported locale": {
+			locale:         "xx_XX",
+			catalog:        gettextCatalog,
+			expectedStatus: 400,
+		},
+	}
+
+	for name, tc := range invalidImports {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.PostGettextLocaleWithBody(ctx, tc.locale, &api.PostGettextLocaleParams{}, "text/x-gettext-translation", strings.NewReader(tc.catalog))
+			require.NoError(t, err)
+			defer result.Body.Close()
+
+			assert.Equal(t, tc.expectedStatus, result.StatusCode)
+		})
+	}
+
+	t.Run("import", func(t *testing.T) {
+		result, err := client.PostGettextLocaleWithBody(ctx, "de_DE", &api.PostGettextLocaleParams{XAuthor: ptr("translator")}, "text/x-gettext-translation", strings.NewReader(gettextCatalog))
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+
+		var report api.ImportReport
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&report))
+		assert.Equal(t, []string{"test_lk_1", "menu\x04file", "cart_items"}, report.Created)
+		assert.Equal(t, []string{"test_lk_2"}, report.Updated)
+		assert.Equal(t, []api.SkippedEntry{
+			{Key: "test_lk_0", Reason: translation.SkipUnchanged},
+			{Key: "untranslated", Reason: translation.SkipUntranslated},
+			{Key: "obsolete", Reason: translation.SkipObsolete},
+		}, report.Skipped)
+	})
+
+	reads := map[string]struct {
+		key             string
+		expectedText    string
+		expectedComment string
+		expectedFuzzy   bool
+	}{
+		"updated translation": {
+			key:          "test_lk_2",
+			expectedText: "Noch einer!",
+		},
+		"fuzzy translation with comment": {
+			key:             "test_lk_1",
+			expectedText:    "Noch ein anderer",
+			expectedComment: "Needs a native speaker",
+			expectedFuzzy:   true,
+		},
+		"translation with context": {
+			key:          "menu\x04file",
+			expectedText: "Datei",
+		},
+		"plural translation": {
+			key:          "cart_items",
+			expectedText: "# Artikel",
+		},
+	}
+
+	for name, tc := range reads {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.GetTranslationKey(ctx, tc.key, &api.GetTranslationKeyParams{Locale: ptr("de_DE")})
+			require.NoError(t, err)
+			defer result.Body.Close()
+
+			require.Equal(t, 200, result.StatusCode)
+
+			var body api.Translation
+			require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+			assert.Equal(t, tc.expectedText, *body.Translation)
+			assert.Equal(t, tc.expectedComment, *body.Comment)
+			assert.Equal(t, tc.expectedFuzzy, *body.Fuzzy)
+		})
+	}
+
+	t.Run("export po", func(t *testing.T) {
+		result, err := client.GetGettextLocale(ctx, "de_DE", &api.GetGettextLocaleParams{})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+		assert.Equal(t, "text/x-gettext-translation", result.Header.Get("Content-Type"))
+
+		catalog, err := translation.ParsePO(result.Body)
+		require.NoError(t, err)
+
+		language, _ := catalog.Header("Language")
+		assert.Equal(t, "de_DE", language)
+
+		entries := map[string]translation.CatalogEntry{}
+		for _, entry := range catalog.Entries {
+			entries[entry.Key()] = entry
+		}
+		assert.Len(t, entries, 5)
+		assert.Equal(t, []string{"Ein Artikel", "# Artikel"}, entries["cart_items"].Strings)
+		assert.NotNil(t, entries["cart_items"].IDPlural)
+		assert.Equal(t, []string{"Needs a native speaker"}, entries["test_lk_1"].TranslatorComments)
+		assert.True(t, entries["test_lk_1"].Fuzzy())
+		assert.Equal(t, "menu", *entries["menu\x04file"].Context)
+	})
+
+	t.Run("export mo leaves out fuzzy translations", func(t *testing.T) {
+		result, err := client.GetGettextLocale(ctx, "de_DE", &api.GetGettextLocaleParams{Format: ptr(api.GetGettextLocaleParamsFormatMo)})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+		assert.Equal(t, "application/x-gettext-translation", result.Header.Get("Content-Type"))
+
+		data, err := io.ReadAll(result.Body)
+		require.NoError(t, err)
+		catalog, err := translation.ParseMO(data)
+		require.NoError(t, err)
+
+		var keys []string
+		for _, entry := range catalog.Entries {
+			keys = append(keys, entry.Key())
+		}
+		assert.Equal(t, []string{"cart_items", "menu\x04file", "test_lk_0", "test_lk_2"}, keys)
+	})
+
+	t.Run("export pot", func(t *testing.T) {
+		result, err := client.GetGettext(ctx)
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+
+		catalog, err := translation.ParsePO(result.Body)
+		require.NoError(t, err)
+
+		var keys []string
+		for _, entry := range catalog.Entries {
+			keys = append(keys, entry.Key())
+			assert.False(t, entry.Translated())
+		}
+		assert.Equal(t, []string{"cart_items", "menu\x04file", "test_lk_0", "test_lk_1", "test_lk_2"}, keys)
+	})
+
+	t.Run("editing clears the fuzzy flag", func(t *testing.T) {
+		result, err := client.PatchTranslationKey(ctx, "test_lk_1", &api.PatchTranslationKeyParams{Locale: "de_DE"}, api.TranslationPatch{Translation: ptr("Noch ein weiterer")})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+
+		var body api.Translation
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+		assert.False(t, *body.Fuzzy)
+		assert.Equal(t, "Needs a native speaker", *body.Comment)
+	})
+}
+
+func TestXLIFFREST(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	server, client, teardownServer := setupTestRESTServer()
+
+	defer teardownServer(server)
+
+	ctx := context.Background()
+
+	exports := map[string]struct {
+		params          api.GetXliffLocaleParams
+		expectedVersion string
+	}{
+		"default version": {
+			params:          api.GetXliffLocaleParams{Source: ptr("en_GB")},
+			expectedVersion: translation.XLIFF20,
+		},
+		"version 1.2": {
+			params:          api.GetXliffLocaleParams{Source: ptr("en_GB"), Version: ptr(api.GetXliffLocaleParamsVersionN12)},
+			expectedVersion: translation.XLIFF12,
+		},
+	}
+
+	for name, tc := range exports {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.GetXliffLocale(ctx, "de_DE", &tc.params)
+			require.NoError(t, err)
+			defer result.Body.Close()
+
+			require.Equal(t, 200, result.StatusCode)
+			assert.Equal(t, "application/xliff+xml", result.Header.Get("Content-Type"))
+
+			document, err := translation.ParseXLIFF(result.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedVersion, document.Version)
+			assert.Equal(t, "en-GB", document.SourceLocale)
+			assert.Equal(t, "de-DE", document.TargetLocale)
+
+			require.Len(t, document.Units, 2)
+			assert.Equal(t, "test_lk_0", document.Units[0].Key)
+			assert.Equal(t, "Translation Service", document.Units[0].Source)
+			assert.Equal(t, "Übersetzungs-Dienst", *document.Units[0].Target)
+			assert.Equal(t, "test_lk_1", document.Units[1].Key)
+			assert.Nil(t, document.Units[1].Target)
+		})
+	}
+
+	invalidExports := map[string]struct {
+		locale         string
+		params         api.GetXliffLocaleParams
+		expectedStatus int
+	}{
+		"same source and target": {
+			locale:         "en_GB",
+			params:         api.GetXliffLocaleParams{Source: ptr("en_GB")},
+			expectedStatus: 400,
+		},
+		"unsupported version": {
+			locale:         "de_DE",
+			params:         api.GetXliffLocaleParams{Version: ptr(api.GetXliffLocaleParamsVersion("3.0"))},
+			expectedStatus: 400,
+		},
+		"unsupported source": {
+			locale:         "de_DE",
+			params:         api.GetXliffLocaleParams{Source: ptr("xx_XX")},
+			expectedStatus: 400,
+		},
+	}
+
+	for name, tc := range invalidExports {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.GetXliffLocale(ctx, tc.locale, &tc.params)
+			require.NoError(t, err)
+			defer result.Body.Close()
+
+			assert.Equal(t, tc.expectedStatus, result.StatusCode)
+		})
+	}
+
+	export := func(t *testing.T) *translation.XLIFFDocument {
+		result, err := client.GetXliffLocale(ctx, "de_DE", &api.GetXliffLocaleParams{Source: ptr("en_GB")})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+
+		document, err := translation.ParseXLIFF(result.Body)
+		require.NoError(t, err)
+		return document
+	}
+
+	importDocument := func(t *testing.T, locale string, document *translation.XLIFFDocument) *http.Response {
+		var body strings.Builder
+		require.NoError(t, translation.WriteXLIFF(&body, document))
+
+		result, err := client.PostXliffLocaleWithBody(ctx, locale, &api.PostXliffLocaleParams{XAuthor: ptr("translator")}, "application/xliff+xml", strings.NewReader(body.String()))
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("invalid document", func(t *testing.T) {
+		result, err := client.PostXliffLocaleWithBody(ctx, "de_DE", &api.PostXliffLocaleParams{}, "application/xliff+xml", strings.NewReader("<html/>"))
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		assert.Equal(t, 400, result.StatusCode)
+	})
+
+	t.Run("document too large", func(t *testing.T) {
+		body := "<xliff>" + strings.Repeat(" ", 32<<20) + "</xliff>"
+		result, err := client.PostXliffLocaleWithBody(ctx, "de_DE", &api.PostXliffLocaleParams{}, "application/xliff+xml", strings.NewReader(body))
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		assert.Equal(t, 413, result.StatusCode)
+	})
+
+	t.Run("language mismatch", func(t *testing.T) {
+		result := importDocument(t, "en_GB", export(t))
+		defer result.Body.Close()
+
+		assert.Equal(t, 400, result.StatusCode)
+	})
+
+	t.Run("import", func(t *testing.T) {
+		document := export(t)
+		document.Units[0].Target = ptr("Übersetzungsdienst")
+		document.Units[1].Target = ptr("Noch ein anderer")
+		document.Units[1].Notes = []string{"Reviewed"}
+		document.Units = append(document.Units, translation.XLIFFUnit{Key: "unknown", Source: "Unknown", Target: ptr("Unbekannt")})
+
+		// The translation changes after the export, its unit conflicts.
+		patch, err := client.PatchTranslationKey(ctx, "test_lk_0", &api.PatchTranslationKeyParams{Locale: "de_DE"}, api.TranslationPatch{Translation: ptr("Dienst")})
+		require.NoError(t, err)
+		patch.Body.Close()
+		require.Equal(t, 200, patch.StatusCode)
+
+		result := importDocument(t, "de_DE", document)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+
+		var report api.ImportReport
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&report))
+		assert.Equal(t, []string{"test_lk_1"}, report.Created)
+		assert.Empty(t, report.Updated)
+		assert.Equal(t, []api.SkippedEntry{{Key: "unknown", Reason: translation.SkipUnknownKey}}, report.Skipped)
+		assert.Equal(t, []api.ImportConflict{{Key: "test_lk_0", Current: "Dienst", Imported: "Übersetzungsdienst"}}, report.Conflicts)
+	})
+
+	reads := map[string]struct {
+		key             string
+		expectedText    string
+		expectedComment string
+	}{
+		"conflicting translation is kept": {
+			key:          "test_lk_0",
+			expectedText: "Dienst",
+		},
+		"imported translation": {
+			key:             "test_lk_1",
+			expectedText:    "Noch ein anderer",
+			expectedComment: "Reviewed",
+		},
+	}
+
+	for name, tc := range reads {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.GetTranslationKey(ctx, tc.key, &api.GetTranslationKeyParams{Locale: ptr("de_DE")})
+			require.NoError(t, err)
+			defer result.Body.Close()
+
+			require.Equal(t, 200, result.StatusCode)
+
+			var body api.Translation
+			require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+			assert.Equal(t, tc.expectedText, *body.Translation)
+			assert.Equal(t, tc.expectedComment, *body.Comment)
+		})
+	}
+
+	t.Run("reimport of a fresh export updates", func(t *testing.T) {
+		document := export(t)
+		document.Units[0].Target = ptr("Übersetzungsdienst")
+
+		result := importDocument(t, "de_DE", document)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+
+		var report api.ImportReport
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&report))
+		assert.Equal(t, []string{"test_lk_0"}, report.Updated)
+		assert.Equal(t, []api.SkippedEntry{{Key: "test_lk_1", Reason: translation.SkipUnchanged}}, report.Skipped)
+		assert.Empty(t, report.Conflicts)
+	})
+}
+
+func TestBundleFormatsREST(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	server, client, teardownServer := setupTestRESTServer()
+
+	defer teardownServer(server)
+
+	ctx := context.Background()
+
+	created, err := client.PutTranslationKey(ctx, "cart_items", &api.PutTranslationKeyParams{Locale: "en_GB"}, api.TranslationValue{PluralForms: &api.PluralForms{One: ptr("One item"), Other: ptr("# items")}})
+	require.NoError(t, err)
+	created.Body.Close()
+	require.Equal(t, 201, created.StatusCode)
+
+	created, err = client.PutTranslationKey(ctx, "ticket_number", &api.PutTranslationKeyParams{Locale: "en_GB"}, api.TranslationValue{PluralForms: &api.PluralForms{One: ptr("Ticket '#'{number}"), Other: ptr("'{'So far'}' # tickets of {total}")}})
+	require.NoError(t, err)
+	created.Body.Close()
+	require.Equal(t, 201, created.StatusCode)
+
+	created, err = client.PutTranslationKey(ctx, "checkout.title", &api.PutTranslationKeyParams{Locale: "en_GB"}, api.TranslationValue{Translation: "Checkout"})
+	require.NoError(t, err)
+	created.Body.Close()
+	require.Equal(t, 201, created.StatusCode)
+
+	created, err = client.PutTranslationKey(ctx, "discount", &api.PutTranslationKeyParams{Locale: "en_GB"}, api.TranslationValue{Translation: "Save 20% with {code}"})
+	require.NoError(t, err)
+	created.Body.Close()
+	require.Equal(t, 201, created.StatusCode)
+
+	created, err = client.PutTranslationKey(ctx, "progress", &api.PutTranslationKeyParams{Locale: "en_GB"}, api.TranslationValue{Translation: "100% done"})
+	require.NoError(t, err)
+	created.Body.Close()
+	require.Equal(t, 201, created.StatusCode)
+
+	tests := map[string]struct {
+		format              *api.GetTranslationsParamsFormat
+		accept              string
+		expectedStatus      int
+		expectedContentType string
+		expectedContent     []string
+	}{
+		"android": {
+			format:              ptr(api.GetTranslationsParamsFormatAndroid),
+			expectedStatus:      200,
+			expectedContentType: "application/x-android-strings+xml",
+			expectedContent: []string{
+				`<string name="test_lk_0">Translation Service</string>`,
+				`<plurals name="cart_items">`,
+				`<item quantity="one">One item</item>`,
+				`<item quantity="other">%d items</item>`,
+				`<item quantity="one">Ticket #%3$s</item>`,
+				`<item quantity="other">{So far} %1$d tickets of %2$s</item>`,
+				`<string name="discount">Save 20%% with %1$s</string>`,
+				`<string name="progress" formatted="false">100% done</string>`,
+			},
+		},
+		"ios": {
+			format:              ptr(api.GetTranslationsParamsFormatIos),
+			expectedStatus:      200,
+			expectedContentType: "text/x-ios-strings",
+			expectedContent: []string{
+				`"test_lk_0" = "Translation Service";`,
+				`"cart_items" = "%d items";`,
+				`"ticket_number" = "{So far} %1$d tickets of %2$@";`,
+				`"discount" = "Save 20%% with %1$@";`,
+				`"progress" = "100% done";`,
+			},
+		},
+		"stringsdict": {
+			format:              ptr(api.GetTranslationsParamsFormatStringsdict),
+			expectedStatus:      200,
+			expectedContentType: "application/x-ios-stringsdict+xml",
+			expectedContent: []string{
+				"<key>cart_items</key>",
+				"<string>NSStringPluralRuleType</string>",
+				"<key>one</key>\n            <string>One item</string>",
+				"<key>other</key>\n            <string>%d items</string>",
+				"<key>one</key>\n            <string>Ticket #%3$@</string>",
+			},
+		},
+		"flat json": {
+			format:              ptr(api.GetTranslationsParamsFormatFlat),
+			expectedStatus:      200,
+			expectedContentType: "application/x-flat+json",
+			expectedContent: []string{
+				`"test_lk_0": "Translation Service"`,
+				`"checkout.title": "Checkout"`,
+				`"cart_items": "{count, plural, one {One item} other {# items}}"`,
+				`"ticket_number": "{count, plural, one {Ticket '#'{number}} other {'{'So far'}' # tickets of {total}}}"`,
+			},
+		},
+		"i18next": {
+			format:              ptr(api.GetTranslationsParamsFormatI18next),
+			expectedStatus:      200,
+			expectedContentType: "application/x-i18next+json",
+			expectedContent: []string{
+				`"cart_items_one": "One item"`,
+				`"cart_items_other": "{{count}} items"`,
+				`"ticket_number_one": "Ticket #{{number}}"`,
+				`"ticket_number_other": "{So far} {{count}} tickets of {{total}}"`,
+				"\"checkout\": {\n    \"title\": \"Checkout\"\n  }",
+			},
+		},
+		"arb": {
+			format:              ptr(api.GetTranslationsParamsFormatArb),
+			expectedStatus:      200,
+			expectedContentType: "application/x-arb+json",
+			expectedContent: []string{
+				`"@@locale": "en_GB"`,
+				`"test_lk_0": "Translation Service"`,
+				`"cart_items": "{count, plural, one {One item} other {# items}}"`,
+				`"type": "num"`,
+			},
+		},
+		"properties": {
+			format:              ptr(api.GetTranslationsParamsFormatProperties),
+			expectedStatus:      200,
+			expectedContentType: "text/x-java-properties",
+			expectedContent: []string{
+				"test_lk_0=Translation Service\n",
+				"cart_items={count, plural, one {One item} other {# items}}\n",
+			},
+		},
+		"negotiated i18next": {
+			accept:              "application/x-i18next+json, application/json;q=0.9",
+			expectedStatus:      200,
+			expectedContentType: "application/x-i18next+json",
+			expectedContent:     []string{`"test_lk_1": "Another one"`},
+		},
+		"negotiated android": {
+			accept:              "application/json;q=0.5, application/x-android-strings+xml",
+			expectedStatus:      200,
+			expectedContentType: "application/x-android-strings+xml",
+			expectedContent:     []string{`<string name="test_lk_1">Another one</string>`},
+		},
+		"negotiated json": {
+			accept:              "*/*",
+			expectedStatus:      200,
+			expectedContentType: "application/json",
+			expectedContent:     []string{`"languageKey":"test_lk_0"`},
+		},
+		"format wins over accept": {
+			format:              ptr(api.GetTranslationsParamsFormatIos),
+			accept:              "application/json",
+			expectedStatus:      200,
+			expectedContentType: "text/x-ios-strings",
+		},
+		"unknown format": {
+			format:         ptr(api.GetTranslationsParamsFormat("yaml")),
+			expectedStatus: 400,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			setAccept := func(_ context.Context, req *http.Request) error {
+				if tc.accept != "" {
+					req.Header.Set("Accept", tc.accept)
+				}
+				return nil
+			}
+
+			result, err := client.GetTranslations(ctx, &api.GetTranslationsParams{Locale: ptr("en_GB"), Format: tc.format}, setAccept)
+			require.NoError(t, err)
+			defer result.Body.Close()
+
+			require.Equal(t, tc.expectedStatus, result.StatusCode)
+			if tc.expectedStatus != 200 {
+				return
+			}
+
+			assert.Equal(t, tc.expectedContentType, result.Header.Get("Content-Type"))
+			assert.Contains(t, result.Header.Values("Vary"), "Accept")
+			assert.NotEmpty(t, result.Header.Get("ETag"))
+
+			body, err := io.ReadAll(result.Body)
+			require.NoError(t, err)
+			for _, content := range tc.expectedContent {
+				assert.Contains(t, string(body), content)
+			}
+		})
+	}
+}
+
+func TestCSVREST(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	server, client, teardownServer := setupTestRESTServer()
+
+	defer teardownServer(server)
+
+	ctx := context.Background()
+
+	exports := map[string]struct {
+		locales        *[]string
+		expectedStatus int
+		expectedBody   string
+	}{
+		"default locales": {
+			expectedStatus: 200,
+			expectedBody: "language_key,en_GB,de_DE\r\n" +
+				"test_lk_0,Translation Service,Übersetzungs-Dienst\r\n" +
+				"test_lk_1,Another one,\r\n" +
+				"test_lk_2,,Noch einer\r\n",
+		},
+		"selected locale": {
+			locales:        &[]string{"de_DE"},
+			expectedStatus: 200,
+			expectedBody: "language_key,de_DE\r\n" +
+				"test_lk_0,Übersetzungs-Dienst\r\n" +
+				"test_lk_1,\r\n" +
+				"test_lk_2,Noch einer\r\n",
+		},
+		"unsupported locale": {
+			locales:        &[]string{"xx_XX"},
+			expectedStatus: 400,
+		},
+	}
+
+	for name, tc := range exports {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.GetCsv(ctx, &api.GetCsvParams{Locale: tc.locales})
+			require.NoError(t, err)
+			defer result.Body.Close()
+
+			require.Equal(t, tc.expectedStatus, result.StatusCode)
+			if tc.expectedStatus != 200 {
+				return
+			}
+
+			assert.Equal(t, "text/csv; charset=utf-8", result.Header.Get("Content-Type"))
+			body, err := io.ReadAll(result.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedBody, string(body))
+		})
+	}
+
+	invalidImports := map[string]struct {
+		table string
+	}{
+		"missing key column": {
+			table: "key,en_GB\ntest_lk_0,Translation Service\n",
+		},
+		"duplicate key": {
+			table: "language_key,en_GB\ntest_lk_0,a\ntest_lk_0,b\n",
+		},
+		"unsupported locale": {
+			table: "language_key,xx_XX\ntest_lk_0,a\n",
+		},
+		"plural category not used by the locale": {
+			table: "language_key,en_GB\ncart_items,\"{count, plural, few {A few items} other {# items}}\"\n",
+		},
+	}
+
+	for name, tc := range invalidImports {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.PostCsvWithBody(ctx, &api.PostCsvParams{}, "text/csv", strings.NewReader(tc.table))
+			require.NoError(t, err)
+			defer result.Body.Close()
+
+			assert.Equal(t, 400, result.StatusCode)
+		})
+	}
+
+	table := "\xef\xbb\xbflanguage_key,en_GB,de_DE\r\n" +
+		"test_lk_0,Translation Service,Übersetzungsdienst\r\n" +
+		"test_lk_1,,Noch ein anderer\r\n" +
+		"cart_items,\"{count, plural, one {One item} other {# items}}\",\r\n"
+
+	expectedDiff := api.TableDiff{
+		Added: []api.TableChange{
+			{Key: "test_lk_1", Locale: "de_DE", Imported: ptr("Noch ein anderer")},
+			{Key: "cart_items", Locale: "en_GB", Imported: ptr("{count, plural, one {One item} other {# items}}")},
+		},
+		Changed: []api.TableChange{
+			{Key: "test_lk_0", Locale: "de_DE", Current: ptr("Übersetzungs-Dienst"), Imported: ptr("Übersetzungsdienst")},
+		},
+		Removed: []api.TableChange{
+			{Key: "test_lk_1", Locale: "en_GB", Current: ptr("Another one")},
+			{Key: "test_lk_2", Locale: "de_DE", Current: ptr("Noch einer")},
+		},
+		Unchanged: []api.TableChange{
+			{Key: "test_lk_0", Locale: "en_GB", Current: ptr("Translation Service"), Imported: ptr("Translation Service")},
+		},
+	}
+
+	importTable := func(t *testing.T, dryRun bool, fingerprint *string) (int, api.TableDiff) {
+		result, err := client.PostCsvWithBody(ctx, &api.PostCsvParams{DryRun: &dryRun, Fingerprint: fingerprint, XAuthor: ptr("product")}, "text/csv", strings.NewReader(table))
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		var diff api.TableDiff
+		if result.StatusCode == 200 {
+			require.NoError(t, json.NewDecoder(result.Body).Decode(&diff))
+		}
+		return result.StatusCode, diff
+	}
+
+	setTranslation := func(t *testing.T, key, locale, text string) {
+		result, err := client.PutTranslationKey(ctx, key, &api.PutTranslationKeyParams{Locale: locale}, api.TranslationValue{Translation: text})
+		require.NoError(t, err)
+		result.Body.Close()
+		require.Contains(t, []int{200, 201}, result.StatusCode)
+	}
+
+	readTranslation := func(t *testing.T, key, locale string) int {
+		result, err := client.GetTranslationKey(ctx, key, &api.GetTranslationKeyParams{Locale: &locale})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		return result.StatusCode
+	}
+
+	var fingerprint string
+
+	t.Run("dry run writes nothing", func(t *testing.T) {
+		status, diff := importTable(t, true, nil)
+		require.Equal(t, 200, status)
+
+		fingerprint = diff.Fingerprint
+		assert.NotEmpty(t, fingerprint)
+		diff.Fingerprint = ""
+		assert.Equal(t, expectedDiff, diff)
+
+		assert.Equal(t, 200, readTranslation(t, "test_lk_2", "de_DE"))
+		assert.Equal(t, 404, readTranslation(t, "cart_items", "en_GB"))
+	})
+
+	t.Run("import rejects a diff changed since the dry run", func(t *testing.T) {
+		setTranslation(t, "test_lk_2", "de_DE", "Noch einer!")
+
+		status, _ := importTable(t, false, &fingerprint)
+		assert.Equal(t, 409, status)
+		assert.Equal(t, 404, readTranslation(t, "cart_items", "en_GB"))
+
+		setTranslation(t, "test_lk_2", "de_DE", "Noch einer")
+	})
+
+	t.Run("import applies the diff", func(t *testing.T) {
+		status, diff := importTable(t, false, &fingerprint)
+		require.Equal(t, 200, status)
+		assert.Equal(t, fingerprint, diff.Fingerprint)
+
+		assert.Equal(t, 404, readTranslation(t, "test_lk_2", "de_DE"))
+		assert.Equal(t, 200, readTranslation(t, "cart_items", "en_GB"))
+	})
+
+	t.Run("plural cells become plural forms", func(t *testing.T) {
+		result, err := client.GetTranslationKey(ctx, "cart_items", &api.GetTranslationKeyParams{Locale: ptr("en_GB")})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+
+		var body api.Translation
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+		assert.Equal(t, "# items", *body.Translation)
+		assert.Equal(t, "One item", *body.PluralForms.One)
+	})
+
+	t.Run("reimport changes nothing", func(t *testing.T) {
+		result, err := client.PostCsvWithBody(ctx, &api.PostCsvParams{}, "text/csv", strings.NewReader(table))
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+
+		var diff api.TableDiff
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&diff))
+		assert.Empty(t, diff.Added)
+		assert.Empty(t, diff.Changed)
+		assert.Empty(t, diff.Removed)
+		assert.Len(t, diff.Unchanged, 4)
+	})
+
+	t.Run("formulas are exported as text", func(t *testing.T) {
+		setTranslation(t, "formula", "en_GB", `=HYPERLINK("https://example.com")`)
+		setTranslation(t, "-discount", "en_GB", "-20%")
+
+		result, err := client.GetCsv(ctx, &api.GetCsvParams{Locale: &[]string{"en_GB"}})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+		body, err := io.ReadAll(result.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), "'-discount,'-20%\r\n")
+		assert.Contains(t, string(body), `formula,"'=HYPERLINK(""https://example.com"")"`+"\r\n")
+
+		imported, err := client.PostCsvWithBody(ctx, &api.PostCsvParams{DryRun: ptr(true)}, "text/csv", strings.NewReader(string(body)))
+		require.NoError(t, err)
+		defer imported.Body.Close()
+
+		require.Equal(t, 200, imported.StatusCode)
+		var diff api.TableDiff
+		require.NoError(t, json.NewDecoder(imported.Body).Decode(&diff))
+		assert.Empty(t, diff.Added)
+		assert.Empty(t, diff.Changed)
+		assert.Empty(t, diff.Removed)
+	})
+}
+
+func TestSearchREST(t *testing.T) {
+	dbConn, teardownDatabase := setupTestDatabase(t)
+	defer teardownDatabase()
+
+	db, err := sql.Open("postgres", dbConn)
+	if err != nil {
+		t.Fatalf("Failed to open database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	runGooseUp(t, db)
+
+	executeSQLFile(t, db, "./test_data/get_translations.sql")
+
+	server, client, teardownServer := setupTestRESTServer()
+
+	defer teardownServer(server)
+
+	ctx := context.Background()
+
+	testCases := map[string]struct {
+		query           string
+		locale          *string
+		expectedStatus  int
+		expectedKeys    []string
+		expectedSnippet string
+	}{
+		"english words are stemmed": {
+			query:           "services",
+			locale:          ptr("en_GB"),
+			expectedStatus:  200,
+			expectedKeys:    []string{"test_lk_0 en_GB"},
+			expectedSnippet: "<mark>Service</mark>",
+		},
+		"german words are stemmed": {
+			query:          "Dienste",
+			locale:         ptr("de_DE"),
+			expectedStatus: 200,
+			expectedKeys:   []string{"test_lk_0 de_DE"},
+		},
+		"keys match in every locale": {
+			query:          "test_lk",
+			expectedStatus: 200,
+			expectedKeys:   []string{"test_lk_0 de_DE", "test_lk_0 en_GB", "test_lk_1 en_GB", "test_lk_2 de_DE"},
+		},
+		"no match": {
+			query:          "nothing",
+			expectedStatus: 200,
+			expectedKeys:   []string{},
+		},
+		"missing query": {
+			query:          " ",
+			expectedStatus: 400,
+		},
+		"unsupported locale": {
+			query:          "services",
+			locale:         ptr("xx_XX"),
+			expectedStatus: 400,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			result, err := client.GetTranslationsSearch(ctx, &api.GetTranslationsSearchParams{Q: tc.query, Locale: tc.locale})
+			require.NoError(t, err)
+			defer result.Body.Close()
+
+			require.Equal(t, tc.expectedStatus, result.StatusCode)
+			if tc.expectedStatus != 200 {
+				return
+			}
+
+			var body []api.SearchResult
+			require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+
+			keys := []string{}
+			for _, entry := range body {
+				keys = append(keys, entry.LanguageKey+" "+entry.Locale)
+			}
+			assert.Equal(t, tc.expectedKeys, keys)
+			if tc.expectedSnippet != "" {
+				assert.Contains(t, body[0].Snippet, tc.expectedSnippet)
+			}
+		})
+	}
+
+	t.Run("similar keys rank first", func(t *testing.T) {
+		result, err := client.GetTranslationsSearch(ctx, &api.GetTranslationsSearchParams{Q: "test_lk_1"})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		require.Equal(t, 200, result.StatusCode)
+
+		var body []api.SearchResult
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+		require.NotEmpty(t, body)
+		assert.Equal(t, "test_lk_1", body[0].LanguageKey)
+	})
+
+	t.Run("paginate results", func(t *testing.T) {
+		keys := []string{}
+		var pageToken *string
+
+		for {
+			result, err := client.GetTranslationsSearch(ctx, &api.GetTranslationsSearchParams{Q: "test_lk", PageSize: ptr(1), PageToken: pageToken})
+			require.NoError(t, err)
+			require.Equal(t, 200, result.StatusCode)
+
+			var body []api.SearchResult
+			require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+			result.Body.Close()
+			require.LessOrEqual(t, len(body), 1)
+
+			for _, entry := range body {
+				keys = append(keys, entry.LanguageKey+" "+entry.Locale)
+			}
+
+			next := result.Header.Get("X-Next-Page-Token")
+			if next == "" {
+				break
+			}
+			pageToken = &next
+		}
+
+		assert.Equal(t, []string{"test_lk_0 de_DE", "test_lk_0 en_GB", "test_lk_1 en_GB", "test_lk_2 de_DE"}, keys)
+	})
+
+	t.Run("page token of another search", func(t *testing.T) {
+		result, err := client.GetTranslationsSearch(ctx, &api.GetTranslationsSearchParams{Q: "test_lk", PageSize: ptr(1)})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		pageToken := result.Header.Get("X-Next-Page-Token")
+		require.NotEmpty(t, pageToken)
+
+		other, err := client.GetTranslationsSearch(ctx, &api.GetTranslationsSearchParams{Q: "services", PageToken: &pageToken})
+		require.NoError(t, err)
+		defer other.Body.Close()
+
+		assert.Equal(t, 400, other.StatusCode)
+	})
+
+	t.Run("unknown namespace", func(t *testing.T) {
+		result, err := client.GetNamespacesNamespaceTranslationsSearch(ctx, "unknown", &api.GetNamespacesNamespaceTranslationsSearchParams{Q: "services"})
+		require.NoError(t, err)
+		defer result.Body.Close()
+
+		assert.Equal(t, 404, result.StatusCode)
+	})
+}
@@ -0,0 +1,134 @@
+package translation
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// WriteAndroidStrings writes translations as Android strings.xml resource.
+// Pluralised translations become plurals, translator comments XML comments.
+// Placeholders are converted by printfMessages, strings holding a literal "%"
+// but no placeholder are marked as not formatted.
+// Keys are turned into resource names by replacing every character Java
+// identifiers do not allow with an underscore, keys whose name is already
+// taken are left out with a comment.
+func WriteAndroidStrings(w io.Writer, translations []Translation) error {
+	out := bufio.NewWriter(w)
+
+	out.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + "\n")
+	out.WriteString("<resources>\n")
+
+	names := make(map[string]string, len(translations))
+	for _, entity := range translations {
+		name := androidResourceName(entity.LanguageKey)
+		if key, taken := names[name]; taken {
+			fmt.Fprintf(out, "    <!-- %s left out, its name %s is taken by %s -->\n", androidComment(strconv.Quote(entity.LanguageKey)), name, androidComment(strconv.Quote(key)))
+			continue
+		}
+		names[name] = entity.LanguageKey
+
+		if entity.Comment != "" {
+			fmt.Fprintf(out, "    <!-- %s -->\n", androidComment(entity.Comment))
+		}
+
+		converted := printfMessages(entity, "s")
+		if len(converted.forms) == 0 {
+			attributes := ""
+			if !converted.format && strings.Contains(converted.text, "%") {
+				attributes = ` formatted="false"`
+			}
+			fmt.Fprintf(out, "    <string name=\"%s\"%s>%s</string>\n", name, attributes, androidEscape(converted.text))
+			continue
+		}
+
+		fmt.Fprintf(out, "    <plurals name=\"%s\">\n", name)
+		for _, category := range converted.forms.Categories() {
+			fmt.Fprintf(out, "        <item quantity=\"%s\">%s</item>\n", category, androidEscape(converted.forms[category]))
+		}
+		out.WriteString("    </plurals>\n")
+	}
+
+	out.WriteString("</resources>\n")
+
+	return out.Flush()
+}
+
+// androidResourceName maps a key onto a valid resource name, e.g.
+// "checkout.title" onto "checkout_title".
+func androidResourceName(key string) string {
+	var name strings.Builder
+	for i, r := range key {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+			name.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				name.WriteByte('_')
+			}
+			name.WriteRune(r)
+		default:
+			name.WriteByte('_')
+		}
+	}
+	return name.String()
+}
+
+// androidEscape escapes text for aapt, which removes unescaped quotes,
+// collapses whitespace and treats a leading @ or ? as reference.
+func androidEscape(text string) string {
+	var result strings.Builder
+	previous := rune(0)
+	for i, r := range text {
+		switch {
+		case r == '\\':
+			result.WriteString(`\\`)
+		case r == '\'':
+			result.WriteString(`\'`)
+		case r == '"':
+			result.WriteString(`\"`)
+		case r == '\n':
+			result.WriteString(`\n`)
+		case r == '\t':
+			result.WriteString(`\t`)
+		case r == '&':
+			result.WriteString("&amp;")
+		case r == '<':
+			result.WriteString("&lt;")
+		case r == '>':
+			result.WriteString("&gt;")
+		case (r == '@' || r == '?') && i == 0:
+			result.WriteByte('\\')
+			result.WriteRune(r)
+		case r == ' ' && (i == 0 || i == len(text)-1 || previous == ' '):
+			result.WriteString(`\u0020`)
+		case r < 0x20 || r == 0x7f || r == utf8.RuneError:
+			fmt.Fprintf(&result, `\u%04x`, r)
+		default:
+			result.WriteRune(r)
+		}
+		previous = r
+	}
+	return result.String()
+}
+
+// androidComment makes text safe to use within an XML comment, which must
+// not contain "--" nor end with "-".
+func androidComment(text string) string {
+	text = strings.Map(func(r rune) rune {
+		if (r < 0x20 && r != '\n' && r != '\t') || r == 0x7f {
+			return -1
+		}
+		return r
+	}, text)
+	for strings.Contains(text, "--") {
+		text = strings.ReplaceAll(text, "--", "- -")
+	}
+	if strings.HasSuffix(text, "-") {
+		text += " "
+	}
+	return text
+}
@@ -0,0 +1,83 @@
+package translation
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+type arbMetadata struct {
+	Description  string                    `json:"description,omitempty"`
+	Placeholders map[string]arbPlaceholder `json:"placeholders,omitempty"`
+}
+
+type arbPlaceholder struct {
+	Type string `json:"type"`
+}
+
+// WriteARB writes translations as Flutter Application Resource Bundle of
+// locale. Translator comments become descriptions, pluralised translations a
+// plural message of a numeric count placeholder.
+func WriteARB(w io.Writer, translations []Translation, locale Locale) error {
+	out := bufio.NewWriter(w)
+
+	// ARB tools expect every key to be followed by its metadata, which a
+	// map would sort apart, so the object is written field by field.
+	out.WriteString("{\n")
+	if err := writeARBField(out, "@@locale", locale.String()); err != nil {
+		return err
+	}
+
+	for _, entity := range translations {
+		out.WriteString(",\n")
+		if err := writeARBField(out, entity.LanguageKey, bundleMessage(entity)); err != nil {
+			return err
+		}
+
+		metadata := arbMetadata{Description: entity.Comment}
+		if len(entity.PluralForms) > 0 {
+			metadata.Placeholders = map[string]arbPlaceholder{countArgument: {Type: "num"}}
+		}
+		if metadata.Description != "" || metadata.Placeholders != nil {
+			out.WriteString(",\n")
+			if err := writeARBField(out, "@"+entity.LanguageKey, metadata); err != nil {
+				return err
+			}
+		}
+	}
+
+	out.WriteString("\n}\n")
+
+	return out.Flush()
+}
+
+func writeARBField(out *bufio.Writer, name string, value any) error {
+	encodedName, err := marshalJSON(name, "")
+	if err != nil {
+		return err
+	}
+	encodedValue, err := marshalJSON(value, "  ")
+	if err != nil {
+		return err
+	}
+
+	out.WriteString("  ")
+	out.Write(encodedName)
+	out.WriteString(": ")
+	out.Write(encodedValue)
+	return nil
+}
+
+// marshalJSON encodes value like writeJSONBundle, with nested lines indented
+// by prefix and without the trailing newline.
+func marshalJSON(value any, prefix string) ([]byte, error) {
+	var result bytes.Buffer
+	encoder := json.NewEncoder(&result)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent(prefix, "  ")
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(result.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,270 @@
+package translation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// countArgument names the argument selecting the plural form in bundles that
+// express pluralised translations as a single message.
+const countArgument = "count"
+
+// pluralMessage writes plural forms as ICU MessageFormat plural argument,
+// e.g. "{count, plural, one {One item} other {# items}}". The forms are
+// message patterns themselves, so "#" stands for the count.
+func pluralMessage(forms PluralForms) string {
+	var result strings.Builder
+	result.WriteString("{" + countArgument + ", plural,")
+	for _, category := range forms.Categories() {
+		result.WriteString(" " + string(category) + " {" + pluralFormPattern(forms[category]) + "}")
+	}
+	result.WriteString("}")
+	return result.String()
+}
+
+// pluralFormPattern returns form as plural branch pattern. Forms stored
+// before they were validated as patterns are quoted as literal text.
+func pluralFormPattern(form string) string {
+	if _, err := parsePluralForm(form); err != nil {
+		return quotePluralForm(form)
+	}
+	return form
+}
+
+// parsePluralMessage reverses pluralMessage, it reports false for any other
+// message.
+func parsePluralMessage(message string) (PluralForms, bool) {
+	nodes, err := parseMessage(message)
+	if err != nil || len(nodes) != 1 || nodes[0].argument == nil {
+		return nil, false
+	}
+
+	argument := nodes[0].argument
+	if argument.name != countArgument || argument.kind != "plural" || argument.offset != 0 {
+		return nil, false
+	}
+
+	forms := PluralForms{}
+	for _, option := range argument.options {
+		category := PluralCategory(option.selector)
+		if !slices.Contains(pluralCategoryOrder, category) {
+			return nil, false
+		}
+		forms[category] = option.source
+	}
+	return forms, true
+}
+
+// bundleMessage returns the text of a translation as single message.
+func bundleMessage(entity Translation) string {
+	if len(entity.PluralForms) == 0 {
+		return entity.Translation
+	}
+	return pluralMessage(entity.PluralForms)
+}
+
+// printfTranslation is a translation converted into printf style format
+// strings, as read by Android and iOS.
+type printfTranslation struct {
+	text  string
+	forms PluralForms
+	// format is set if the texts are format strings, in which a literal "%"
+	// is written as "%%".
+	format bool
+}
+
+// printfMessages converts the messages of entity into printf style: "#"
+// becomes "%d" and every other argument a positional argument of the given
+// verb, e.g. "{name}" "%1$s". The count of a pluralised translation is the
+// first argument, then "#" becomes "%1$d" if the forms hold other arguments.
+// Pluralised translations are always format strings, as the count is passed
+// to select the form, plain translations only if they hold arguments.
+// Messages using plural or select arguments, which printf cannot express, and
+// invalid messages are kept as they are.
+func printfMessages(entity Translation, verb string) printfTranslation {
+	kept := printfTranslation{text: entity.Translation, forms: entity.PluralForms}
+
+	pluralised := len(entity.PluralForms) > 0
+	categories := []PluralCategory{""}
+	if pluralised {
+		// The other form comes first, so plain Localizable.strings entries
+		// number the arguments as the stringsdict.
+		categories = append([]PluralCategory{PluralOther}, slices.DeleteFunc(entity.PluralForms.Categories(), func(category PluralCategory) bool {
+			return category == PluralOther
+		})...)
+	}
+
+	messages := make(map[PluralCategory][]messageNode, len(categories))
+	var names []string
+	for _, category := range categories {
+		var nodes []messageNode
+		var err error
+		if pluralised {
+			nodes, err = parsePluralForm(entity.PluralForms[category])
+		} else {
+			nodes, err = parseMessage(entity.Translation)
+		}
+		if err != nil {
+			return kept
+		}
+		for _, node := range nodes {
+			if node.argument == nil {
+				continue
+			}
+			switch node.argument.kind {
+			case "", "number", "date", "time":
+			default:
+				return kept
+			}
+			if !slices.Contains(names, node.argument.name) {
+				names = append(names, node.argument.name)
+			}
+		}
+		messages[category] = nodes
+	}
+
+	first := 1
+	if pluralised {
+		first = 2
+	}
+	result := printfTranslation{format: pluralised || len(names) > 0}
+	render := func(nodes []messageNode) string {
+		var text strings.Builder
+		for _, node := range nodes {
+			switch {
+			case node.pound && len(names) == 0:
+				text.WriteString("%d")
+			case node.pound:
+				text.WriteString("%1$d")
+			case node.argument != nil:
+				fmt.Fprintf(&text, "%%%d$%s", first+slices.Index(names, node.argument.name), verb)
+			case result.format:
+				text.WriteString(strings.ReplaceAll(node.text, "%", "%%"))
+			default:
+				text.WriteString(node.text)
+			}
+		}
+		return text.String()
+	}
+
+	if !pluralised {
+		result.text = render(messages[""])
+		return result
+	}
+	result.forms = make(PluralForms, len(categories))
+	for _, category := range categories {
+		result.forms[category] = render(messages[category])
+	}
+	result.text = result.forms[PluralOther]
+	return result
+}
+
+// WriteFlatJSON writes translations as JSON object of keys and messages, as
+// read by ICU MessageFormat libraries such as FormatJS. Pluralised
+// translations become a plural argument named count.
+func WriteFlatJSON(w io.Writer, translations []Translation) error {
+	bundle := make(map[string]string, len(translations))
+	for _, entity := range translations {
+		bundle[entity.LanguageKey] = bundleMessage(entity)
+	}
+	return writeJSONBundle(w, bundle)
+}
+
+// WriteI18next writes translations as nested i18next JSON. Keys are split
+// at dots, "checkout.title" becomes the title of the checkout object, and
+// plural forms become keys with the suffix of their category, such as
+// "items_one" and "items_other". Placeholders are converted by
+// i18nextMessage. A key addressing an object of another key,
+// or a value within the text of another key, is left out in favour of the
+// first of both in the order of translations.
+func WriteI18next(w io.Writer, translations []Translation) error {
+	bundle := map[string]any{}
+
+	for _, entity := range translations {
+		path := strings.Split(entity.LanguageKey, ".")
+
+		object, ok := bundle, true
+		for _, name := range path[:len(path)-1] {
+			switch child := object[name].(type) {
+			case nil:
+				next := map[string]any{}
+				object[name] = next
+				object = next
+			case map[string]any:
+				object = child
+			default:
+				ok = false
+			}
+			if !ok {
+				break
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		name := path[len(path)-1]
+		if len(entity.PluralForms) == 0 {
+			if _, taken := object[name]; !taken {
+				object[name] = i18nextMessage(entity.Translation, false)
+			}
+			continue
+		}
+		for category, text := range entity.PluralForms {
+			if _, taken := object[name+"_"+string(category)]; !taken {
+				object[name+"_"+string(category)] = i18nextMessage(text, true)
+			}
+		}
+	}
+
+	return writeJSONBundle(w, bundle)
+}
+
+// i18nextMessage converts a message, or with form set a plural form, into
+// i18next interpolation: "#" becomes "{{count}}", "{name}" "{{name}}" and
+// number, date and time arguments use the formats of the same name, e.g.
+// "{{price, number}}". Messages using plural or select arguments, which
+// i18next cannot express, and invalid messages are kept as they are.
+func i18nextMessage(message string, form bool) string {
+	var nodes []messageNode
+	var err error
+	if form {
+		nodes, err = parsePluralForm(message)
+	} else {
+		nodes, err = parseMessage(message)
+	}
+	if err != nil {
+		return message
+	}
+
+	var result strings.Builder
+	for _, node := range nodes {
+		switch {
+		case node.pound:
+			result.WriteString("{{" + countArgument + "}}")
+		case node.argument == nil:
+			result.WriteString(node.text)
+		case node.argument.kind == "":
+			result.WriteString("{{" + node.argument.name + "}}")
+		case node.argument.kind == "number":
+			result.WriteString("{{" + node.argument.name + ", number}}")
+		case node.argument.kind == "date", node.argument.kind == "time":
+			result.WriteString("{{" + node.argument.name + ", datetime}}")
+		default:
+			return message
+		}
+	}
+	return result.String()
+}
+
+// writeJSONBundle writes an indented JSON document with sorted keys, leaving
+// markup of the messages unescaped.
+func writeJSONBundle(w io.Writer, bundle any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(bundle)
+}
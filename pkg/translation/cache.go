@@ -0,0 +1,262 @@
+package translation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+)
+
+// CachedRepository is a Repository keeping a snapshot of every requested locale
+// in memory. Reads are served from the snapshots, writes go to the wrapped
+// repository and drop the snapshot of the written locale. Changes made by other
+// replicas reach the cache through a ChangeListener, see AddHandler. The
+// repositories returned by InNamespace share the cache.
+type CachedRepository struct {
+	repo      Repository
+	namespace string
+	cache     *snapshotCache
+}
+
+type snapshotCache struct {
+	mu        sync.RWMutex
+	snapshots map[snapshotKey]*snapshot
+	// versions count the invalidations per locale and epoch counts the flushes,
+	// so a snapshot loaded while its locale changed is not stored.
+	versions map[snapshotKey]uint64
+	epoch    uint64
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+type snapshotKey struct {
+	namespace string
+	locale    Locale
+}
+
+type CacheStats struct {
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+	Locales int    `json:"locales"`
+}
+
+// snapshot holds all translations of one locale.
+type snapshot struct {
+	byKey map[string]Translation
+}
+
+func NewCachedRepository(repo Repository) *CachedRepository {
+	return &CachedRepository{
+		repo:      repo,
+		namespace: DefaultNamespace,
+		cache: &snapshotCache{
+			snapshots: map[snapshotKey]*snapshot{},
+			versions:  map[snapshotKey]uint64{},
+		},
+	}
+}
+
+func (c *CachedRepository) InNamespace(namespace string) Repository {
+	return &CachedRepository{
+		repo:      c.repo.InNamespace(namespace),
+		namespace: namespace,
+		cache:     c.cache,
+	}
+}
+
+func (c *CachedRepository) GetTranslationByKey(key string, locales ...Locale) (*Translation, error) {
+	for _, locale := range locales {
+		s, err := c.snapshot(locale)
+		if err != nil {
+			return nil, err
+		}
+		if entity, ok := s.byKey[key]; ok {
+			return &entity, nil
+		}
+	}
+
+	return &Translation{}, gorm.ErrRecordNotFound
+}
+
+func (c *CachedRepository) GetTranslations(locales ...Locale) ([]Translation, error) {
+	return c.resolve(locales)
+}
+
+func (c *CachedRepository) ListTranslations(options ListOptions, locales ...Locale) (*TranslationPage, error) {
+	query, err := parseListOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
+	translations, err := c.resolve(locales)
+	if err != nil {
+		return nil, err
+	}
+
+	return query.apply(translations), nil
+}
+
+func (c *CachedRepository) GetTranslationsByKeys(keys []string, locales ...Locale) ([]Translation, error) {
+	if err := validateBatchKeys(keys); err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]*snapshot, 0, len(locales))
+	for _, locale := range locales {
+		s, err := c.snapshot(locale)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, s)
+	}
+
+	var result []Translation
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		for _, s := range snapshots {
+			if entity, ok := s.byKey[key]; ok {
+				result = append(result, entity)
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (c *CachedRepository) CreateTranslation(translation *Translation, author string) error {
+	err := c.repo.CreateTranslation(translation, author)
+	if err == nil {
+		c.invalidate(translation.Locale)
+	}
+
+	return err
+}
+
+func (c *CachedRepository) UpdateTranslation(key string, locale Locale, text string, forms PluralForms, author string) (*Translation, error) {
+	result, err := c.repo.UpdateTranslation(key, locale, text, forms, author)
+	if err == nil {
+		c.invalidate(locale)
+	}
+
+	return result, err
+}
+
+func (c *CachedRepository) DeleteTranslation(key string, locale Locale, author string) error {
+	err := c.repo.DeleteTranslation(key, locale, author)
+	if err == nil {
+		c.invalidate(locale)
+	}
+
+	return err
+}
+
+// TranslationChanged implements ChangeHandler.
+func (c *CachedRepository) TranslationChanged(change Change) {
+	if change.Operation == OperationNamespace {
+		return
+	}
+	c.cache.invalidate(snapshotKey{namespace: change.Namespace, locale: change.Locale})
+}
+
+// ChangesLost implements ChangeHandler.
+func (c *CachedRepository) ChangesLost() {
+	c.cache.mu.Lock()
+	defer c.cache.mu.Unlock()
+
+	c.cache.snapshots = map[snapshotKey]*snapshot{}
+	c.cache.epoch++
+}
+
+// Stats counts the cached locales of all namespaces.
+func (c *CachedRepository) Stats() CacheStats {
+	c.cache.mu.RLock()
+	defer c.cache.mu.RUnlock()
+
+	return CacheStats{
+		Hits:    c.cache.hits.Load(),
+		Misses:  c.cache.misses.Load(),
+		Locales: len(c.cache.snapshots),
+	}
+}
+
+func (c *CachedRepository) invalidate(locale Locale) {
+	c.cache.invalidate(snapshotKey{namespace: c.namespace, locale: locale})
+}
+
+func (s *snapshotCache) invalidate(key snapshotKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.snapshots, key)
+	s.versions[key]++
+}
+
+func (c *CachedRepository) snapshot(locale Locale) (*snapshot, error) {
+	key := snapshotKey{namespace: c.namespace, locale: locale}
+
+	c.cache.mu.RLock()
+	s, ok := c.cache.snapshots[key]
+	version, epoch := c.cache.versions[key], c.cache.epoch
+	c.cache.mu.RUnlock()
+
+	if ok {
+		c.cache.hits.Add(1)
+		return s, nil
+	}
+	c.cache.misses.Add(1)
+
+	translations, err := c.repo.GetTranslations(locale)
+	if err != nil {
+		return nil, fmt.Errorf("loading translations of locale %s: %w", locale, err)
+	}
+
+	s = &snapshot{byKey: make(map[string]Translation, len(translations))}
+	for _, entity := range translations {
+		s.byKey[entity.LanguageKey] = entity
+	}
+
+	c.cache.mu.Lock()
+	defer c.cache.mu.Unlock()
+
+	if c.cache.versions[key] == version && c.cache.epoch == epoch {
+		c.cache.snapshots[key] = s
+	}
+
+	return s, nil
+}
+
+// resolve merges the snapshots of locales the way resolvedTranslations does,
+// ordered by key.
+func (c *CachedRepository) resolve(locales []Locale) ([]Translation, error) {
+	var result []Translation
+	seen := map[string]bool{}
+
+	for _, locale := range locales {
+		s, err := c.snapshot(locale)
+		if err != nil {
+			return nil, err
+		}
+		for key, entity := range s.byKey {
+			if !seen[key] {
+				seen[key] = true
+				result = append(result, entity)
+			}
+		}
+	}
+
+	slices.SortFunc(result, func(a, b Translation) int {
+		return strings.Compare(a.LanguageKey, b.LanguageKey)
+	})
+
+	return result, nil
+}
@@ -0,0 +1,796 @@
+package translation
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Reasons an import skips an entry, besides validation errors.
+const (
+	SkipObsolete     = "obsolete"
+	SkipUntranslated = "untranslated"
+	SkipUnchanged    = "unchanged"
+	SkipDuplicate    = "duplicate"
+	SkipUnknownKey   = "unknown key"
+)
+
+// CatalogStore maps gettext catalogs and XLIFF documents onto the translation
+// table. The key of a translation is the msgid of its entry, prefixed with the
+// msgctxt and GettextContextSeparator if the entry has a context. The
+// translator comments and the fuzzy flag of an entry are kept alongside the
+// translation, other comments and flags are dropped. Plural entries are
+// mapped onto the CLDR plural categories of the locale through the
+// Plural-Forms header.
+type CatalogStore interface {
+	// ExportCatalog returns the translations of locale in namespace, without
+	// fallbacks, ordered by key.
+	ExportCatalog(namespace string, locale Locale) (*Catalog, error)
+	// ExportTemplate returns the keys of namespace with empty translations.
+	ExportTemplate(namespace string) (*Catalog, error)
+	// ImportCatalog creates and updates the translations of locale in
+	// namespace from the catalog in a single transaction. Entries that cannot
+	// be imported are skipped and reported, translations missing from the
+	// catalog are kept.
+	ImportCatalog(namespace string, locale Locale, catalog *Catalog, author string) (*ImportReport, error)
+	// ExportXLIFF returns a unit per key of namespace translated into source,
+	// holding its translation into target if there is one. The translator
+	// comment is written as note.
+	ExportXLIFF(namespace string, source, target Locale, version string) (*XLIFFDocument, error)
+	// ImportXLIFF merges the targets of a document into the translations of
+	// locale in namespace in a single transaction, notes replace the
+	// translator comment. Units of keys the namespace does not hold are
+	// skipped. Units whose translation changed since the export are reported
+	// as conflicts and left alone.
+	ImportXLIFF(namespace string, locale Locale, document *XLIFFDocument, author string) (*ImportReport, error)
+	// ExportCSV returns a row per key of namespace with a column per locale,
+	// by default the default locale of the namespace followed by the other
+	// locales it enables.
+	ExportCSV(namespace string, locales []Locale) (*TranslationTable, error)
+	// ImportCSV compares the table with the translations of its locales in
+	// namespace and applies the differences in a single transaction, unless
+	// dryRun is set. The table is taken as the complete state of its locales,
+	// empty cells and keys missing from the table remove translations.
+	// Updates clear the fuzzy flag and keep the translator comment. A
+	// non-empty fingerprint must match the fingerprint of the diff, so a diff
+	// reviewed in a dry run is applied as it was or not at all.
+	ImportCSV(namespace string, table *TranslationTable, author string, dryRun bool, fingerprint string) (*TableDiff, error)
+}
+
+// ImportReport lists the keys an import created, updated and skipped and the
+// conflicts it found, in catalog order.
+type ImportReport struct {
+	Created   []string
+	Updated   []string
+	Skipped   []SkippedEntry
+	Conflicts []ImportConflict
+}
+
+type SkippedEntry struct {
+	Key    string
+	Reason string
+}
+
+// ImportConflict is a translation changed since it was exported. Current and
+// Imported hold the texts, the PluralOther forms of plural translations.
+type ImportConflict struct {
+	Key      string
+	Current  string
+	Imported string
+}
+
+// TableDiff lists the cells of a CSV import by outcome, in table order.
+// Translations of keys missing from the table are removed after the others.
+type TableDiff struct {
+	Added     []TableChange
+	Changed   []TableChange
+	Removed   []TableChange
+	Unchanged []TableChange
+	// Fingerprint identifies the cells and outcomes, see tableDiffFingerprint.
+	Fingerprint string
+}
+
+// TableChange is a cell of an import. Current holds the message stored before
+// the import and Imported the message of the table, each empty if missing.
+type TableChange struct {
+	Key      string
+	Locale   Locale
+	Current  string
+	Imported string
+}
+
+type catalogStore struct {
+	db *gorm.DB
+}
+
+func NewCatalogStore(db *gorm.DB) CatalogStore {
+	return &catalogStore{
+		db: db,
+	}
+}
+
+func (c catalogStore) ExportCatalog(namespace string, locale Locale) (*Catalog, error) {
+	plurals, err := newGettextPlurals(GettextPluralForms(locale), locale)
+	if err != nil {
+		return nil, err
+	}
+
+	var translations []Translation
+	err = c.db.Where("namespace = ? AND locale = ?", namespace, locale).Order(`language_key COLLATE "C"`).Find(&translations).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Catalog{Headers: catalogHeaders(locale)}
+	for _, entity := range translations {
+		entry := catalogEntryForKey(entity.LanguageKey)
+		if entity.Comment != "" {
+			entry.TranslatorComments = strings.Split(entity.Comment, "\n")
+		}
+		if entity.Fuzzy {
+			entry.Flags = []string{FlagFuzzy}
+		}
+
+		// Keys are source texts at best, so the msgid doubles as msgid_plural.
+		if len(entity.PluralForms) > 0 {
+			entry.IDPlural = &entry.ID
+			entry.Strings = plurals.msgstrs(entity.PluralForms)
+		} else {
+			entry.Strings = []string{entity.Translation}
+		}
+
+		result.Entries = append(result.Entries, entry)
+	}
+
+	return result, nil
+}
+
+func (c catalogStore) ExportTemplate(namespace string) (*Catalog, error) {
+	var keys []struct {
+		LanguageKey string
+		Plural      bool
+	}
+
+	err := c.db.Model(&Translation{}).
+		Select("language_key, bool_or(plural_forms IS NOT NULL) AS plural").
+		Where("namespace = ?", namespace).
+		Group("language_key").
+		Order(`language_key COLLATE "C"`).
+		Find(&keys).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Catalog{Headers: catalogHeaders("")}
+	for _, key := range keys {
+		entry := catalogEntryForKey(key.LanguageKey)
+		if key.Plural {
+			entry.IDPlural = &entry.ID
+			entry.Strings = []string{"", ""}
+		}
+		result.Entries = append(result.Entries, entry)
+	}
+
+	return result, nil
+}
+
+func (c catalogStore) ImportCatalog(namespace string, locale Locale, catalog *Catalog, author string) (*ImportReport, error) {
+	if language, ok := catalog.Header("Language"); ok && !catalogLanguageMatches(language, locale) {
+		return nil, fmt.Errorf("%w: catalog language %q does not match %s", ErrInvalidCatalog, language, locale)
+	}
+
+	header, ok := catalog.Header("Plural-Forms")
+	if !ok {
+		header = GettextPluralForms(locale)
+	}
+
+	// Only needed by plural entries, which report a broken header.
+	plurals, pluralsErr := newGettextPlurals(header, locale)
+
+	return c.runImport(namespace, locale, author, func(i *catalogImport) error {
+		for _, entry := range catalog.Entries {
+			entity, reason := catalogTranslation(entry, locale, plurals, pluralsErr)
+			if reason != "" {
+				i.skip(entry.Key(), reason)
+				continue
+			}
+			if err := i.write(entity); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (c catalogStore) ExportXLIFF(namespace string, source, target Locale, version string) (*XLIFFDocument, error) {
+	categories, err := PluralCategories(target)
+	if err != nil {
+		return nil, err
+	}
+
+	var sources, targets []Translation
+	err = c.db.Where("namespace = ? AND locale = ?", namespace, source).Order(`language_key COLLATE "C"`).Find(&sources).Error
+	if err != nil {
+		return nil, err
+	}
+	if err := c.db.Where("namespace = ? AND locale = ?", namespace, target).Find(&targets).Error; err != nil {
+		return nil, err
+	}
+
+	translated := make(map[string]*Translation, len(targets))
+	for i := range targets {
+		translated[targets[i].LanguageKey] = &targets[i]
+	}
+
+	result := &XLIFFDocument{
+		Version:      version,
+		Namespace:    namespace,
+		SourceLocale: source.LanguageTag(),
+		TargetLocale: target.LanguageTag(),
+	}
+
+	for _, sourceEntity := range sources {
+		targetEntity := translated[sourceEntity.LanguageKey]
+		unit := XLIFFUnit{
+			Key:      sourceEntity.LanguageKey,
+			Baseline: translationFingerprint(targetEntity),
+		}
+		if targetEntity != nil {
+			if targetEntity.Comment != "" {
+				unit.Notes = []string{targetEntity.Comment}
+			}
+			unit.Fuzzy = targetEntity.Fuzzy
+		}
+
+		if len(sourceEntity.PluralForms) == 0 && (targetEntity == nil || len(targetEntity.PluralForms) == 0) {
+			unit.Source = sourceEntity.Translation
+			if targetEntity != nil {
+				unit.Target = &targetEntity.Translation
+			}
+			result.Units = append(result.Units, unit)
+			continue
+		}
+
+		// A unit per category of the target, sources lacking the category
+		// offer their text, which is the PluralOther form of plural sources.
+		unit.SourceForms = PluralForms{}
+		unit.TargetForms = PluralForms{}
+		for _, category := range categories {
+			text, ok := sourceEntity.PluralForms[category]
+			if !ok {
+				text = sourceEntity.Translation
+			}
+			unit.SourceForms[category] = text
+			unit.TargetForms[category] = ""
+		}
+		if targetEntity != nil {
+			maps.Copy(unit.TargetForms, targetEntity.PluralForms)
+			if len(targetEntity.PluralForms) == 0 {
+				unit.TargetForms[PluralOther] = targetEntity.Translation
+			}
+		}
+		result.Units = append(result.Units, unit)
+	}
+
+	return result, nil
+}
+
+func (c catalogStore) ImportXLIFF(namespace string, locale Locale, document *XLIFFDocument, author string) (*ImportReport, error) {
+	if !catalogLanguageMatches(document.TargetLocale, locale) {
+		return nil, fmt.Errorf("%w: target language %q does not match %s", ErrInvalidXLIFF, document.TargetLocale, locale)
+	}
+
+	return c.runImport(namespace, locale, author, func(i *catalogImport) error {
+		var keys []string
+		if err := i.tx.Model(&Translation{}).Where("namespace = ?", namespace).Distinct().Pluck("language_key", &keys).Error; err != nil {
+			return err
+		}
+
+		known := make(map[string]bool, len(keys))
+		for _, key := range keys {
+			known[key] = true
+		}
+
+		for _, unit := range document.Units {
+			if !known[unit.Key] {
+				i.skip(unit.Key, SkipUnknownKey)
+				continue
+			}
+
+			entity, reason := xliffTranslation(unit, locale)
+			if reason != "" {
+				i.skip(unit.Key, reason)
+				continue
+			}
+			if len(unit.Notes) == 0 {
+				entity.Comment = i.current[unit.Key].Comment
+			}
+
+			stored, exists := i.current[unit.Key]
+			var current *Translation
+			if exists {
+				current = &stored
+			}
+			if unit.Baseline != "" && unit.Baseline != translationFingerprint(current) && !(exists && sameTranslation(current, entity)) {
+				i.report.Conflicts = append(i.report.Conflicts, ImportConflict{Key: unit.Key, Current: stored.Translation, Imported: entity.Translation})
+				continue
+			}
+
+			if err := i.write(entity); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (c catalogStore) ExportCSV(namespace string, locales []Locale) (*TranslationTable, error) {
+	settings, err := getNamespace(c.db, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if locales, err = tableLocales(c.db, settings, locales); err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	err = c.db.Model(&Translation{}).
+		Where("namespace = ?", namespace).
+		Group("language_key").
+		Order(`language_key COLLATE "C"`).
+		Pluck("language_key", &keys).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var translations []Translation
+	if err := c.db.Where("namespace = ? AND locale IN ?", namespace, locales).Find(&translations).Error; err != nil {
+		return nil, err
+	}
+
+	columns := make(map[Locale]int, len(locales))
+	for i, locale := range locales {
+		columns[locale] = i
+	}
+
+	result := &TranslationTable{Locales: locales}
+	rows := make(map[string]int, len(keys))
+	for _, key := range keys {
+		rows[key] = len(result.Rows)
+		result.Rows = append(result.Rows, TableRow{Key: key, Cells: make([]string, len(locales))})
+	}
+	for _, entity := range translations {
+		result.Rows[rows[entity.LanguageKey]].Cells[columns[entity.Locale]] = bundleMessage(entity)
+	}
+
+	return result, nil
+}
+
+func (c catalogStore) ImportCSV(namespace string, table *TranslationTable, author string, dryRun bool, fingerprint string) (*TableDiff, error) {
+	diff := &TableDiff{}
+
+	err := c.db.Transaction(func(tx *gorm.DB) error {
+		settings, err := getNamespace(tx, namespace)
+		if err != nil {
+			return err
+		}
+		if len(table.Locales) == 0 {
+			return fmt.Errorf("%w: no locale columns", ErrInvalidCSV)
+		}
+		if _, err := tableLocales(tx, settings, table.Locales); err != nil {
+			return err
+		}
+		if err := setAuthor(tx, author); err != nil {
+			return err
+		}
+
+		var existing []Translation
+		err = tx.Where("namespace = ? AND locale IN ?", namespace, table.Locales).Order(`language_key COLLATE "C", locale`).Find(&existing).Error
+		if err != nil {
+			return err
+		}
+
+		current := make(map[tableCell]Translation, len(existing))
+		for _, entity := range existing {
+			current[tableCell{entity.LanguageKey, entity.Locale}] = entity
+		}
+
+		var creates, updates []*Translation
+		var removals []TableChange
+		seen := make(map[tableCell]bool, len(existing))
+
+		for _, row := range table.Rows {
+			if len(row.Cells) != len(table.Locales) {
+				return fmt.Errorf("%w: %d cells instead of %d in row %q", ErrInvalidCSV, len(row.Cells), len(table.Locales), row.Key)
+			}
+
+			for i, locale := range table.Locales {
+				cell := tableCell{row.Key, locale}
+				seen[cell] = true
+
+				change := TableChange{Key: row.Key, Locale: locale, Imported: row.Cells[i]}
+				stored, exists := current[cell]
+				if exists {
+					change.Current = bundleMessage(stored)
+				}
+
+				switch {
+				case change.Imported == "" && !exists:
+					continue
+				case change.Imported == "":
+					removals = append(removals, change)
+					continue
+				case change.Imported == change.Current:
+					diff.Unchanged = append(diff.Unchanged, change)
+					continue
+				}
+
+				entity, err := tableTranslation(row.Key, locale, change.Imported)
+				if err != nil {
+					return fmt.Errorf("%w: %q in %s: %v", ErrInvalidCSV, row.Key, locale, err)
+				}
+				if exists {
+					updates = append(updates, entity)
+					diff.Changed = append(diff.Changed, change)
+				} else {
+					creates = append(creates, entity)
+					diff.Added = append(diff.Added, change)
+				}
+			}
+		}
+
+		for _, entity := range existing {
+			if !seen[tableCell{entity.LanguageKey, entity.Locale}] {
+				removals = append(removals, TableChange{Key: entity.LanguageKey, Locale: entity.Locale, Current: bundleMessage(entity)})
+			}
+		}
+		diff.Removed = removals
+		diff.Fingerprint = tableDiffFingerprint(diff)
+
+		if fingerprint != "" && fingerprint != diff.Fingerprint {
+			return fmt.Errorf("%w: the diff has fingerprint %q instead of %q", ErrStaleDiff, diff.Fingerprint, fingerprint)
+		}
+		if dryRun {
+			return nil
+		}
+
+		for _, entity := range creates {
+			entity.Namespace = namespace
+			if err := tx.Create(entity).Error; err != nil {
+				return err
+			}
+		}
+		for _, entity := range updates {
+			err := tx.Model(&Translation{}).
+				Where("namespace = ? AND language_key = ? AND locale = ?", namespace, entity.LanguageKey, entity.Locale).
+				Updates(map[string]any{
+					"translation":  entity.Translation,
+					"plural_forms": entity.PluralForms,
+					"fuzzy":        false,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		for _, removal := range removals {
+			err := tx.Where("namespace = ? AND language_key = ? AND locale = ?", namespace, removal.Key, removal.Locale).Delete(&Translation{}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return diff, nil
+}
+
+type tableCell struct {
+	key    string
+	locale Locale
+}
+
+// tableLocales validates the locales of a table, which must be enabled and
+// enabled in the namespace. Without locales it returns the default locale of
+// the namespace followed by the other locales it enables.
+func tableLocales(db *gorm.DB, namespace *Namespace, locales []Locale) ([]Locale, error) {
+	enabled, err := namespaceLocales(db, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(locales) > 0 {
+		for i, locale := range locales {
+			if !slices.Contains(enabled, locale) {
+				return nil, fmt.Errorf("%w: %q", ErrUnsupportedLocale, locale)
+			}
+			if slices.Index(locales, locale) != i {
+				return nil, fmt.Errorf("%w: duplicate locale %s", ErrInvalidCSV, locale)
+			}
+		}
+		return locales, nil
+	}
+
+	defaultLocale := namespace.EffectiveDefaultLocale()
+	if !slices.Contains(enabled, defaultLocale) {
+		return enabled, nil
+	}
+	result := []Locale{defaultLocale}
+	for _, locale := range enabled {
+		if locale != defaultLocale {
+			result = append(result, locale)
+		}
+	}
+	return result, nil
+}
+
+// namespaceLocales returns the enabled locales the namespace enables, ordered
+// by code.
+func namespaceLocales(db *gorm.DB, namespace *Namespace) ([]Locale, error) {
+	var definitions []LocaleDefinition
+	if err := db.Where("enabled").Order("code").Find(&definitions).Error; err != nil {
+		return nil, err
+	}
+
+	enabled := make([]Locale, 0, len(definitions))
+	for _, definition := range definitions {
+		enabled = append(enabled, definition.Code)
+	}
+	return namespace.FilterLocales(enabled), nil
+}
+
+// tableTranslation maps a cell onto a translation, plural messages of a count
+// argument onto plural forms.
+func tableTranslation(key string, locale Locale, message string) (*Translation, error) {
+	entity := &Translation{LanguageKey: key, Locale: locale, Translation: message}
+	if forms, ok := parsePluralMessage(message); ok {
+		entity.PluralForms = forms
+		entity.Translation = forms[PluralOther]
+	}
+	return entity, entity.Validate()
+}
+
+// xliffTranslation maps a unit onto a translation of locale, or returns why
+// the unit is skipped.
+func xliffTranslation(unit XLIFFUnit, locale Locale) (*Translation, string) {
+	entity := &Translation{
+		LanguageKey: unit.Key,
+		Locale:      locale,
+		Comment:     strings.Join(unit.Notes, "\n"),
+		Fuzzy:       unit.Fuzzy,
+	}
+
+	if unit.Plural() {
+		forms := PluralForms{}
+		for category, text := range unit.TargetForms {
+			if text != "" {
+				forms[category] = text
+			}
+		}
+		if len(forms) == 0 {
+			return nil, SkipUntranslated
+		}
+		entity.PluralForms = forms
+		entity.Translation = forms[PluralOther]
+	} else {
+		if unit.Target == nil || *unit.Target == "" {
+			return nil, SkipUntranslated
+		}
+		entity.Translation = *unit.Target
+	}
+
+	if err := entity.Validate(); err != nil {
+		return nil, err.Error()
+	}
+
+	return entity, ""
+}
+
+// translationFingerprint identifies the text and plural forms of a translation,
+// nil stands for a missing translation.
+func translationFingerprint(entity *Translation) string {
+	hash := sha256.New()
+	if entity != nil {
+		forms, _ := json.Marshal(entity.PluralForms)
+		hash.Write([]byte(entity.Translation))
+		hash.Write([]byte{0})
+		hash.Write(forms)
+	}
+	return base64.RawURLEncoding.EncodeToString(hash.Sum(nil)[:12])
+}
+
+// tableDiffFingerprint identifies the changes of a diff including the messages
+// stored before, so it changes with every write to the cells of the table in
+// between a dry run and the import.
+func tableDiffFingerprint(diff *TableDiff) string {
+	hash := sha256.New()
+	for _, changes := range [][]TableChange{diff.Added, diff.Changed, diff.Removed, diff.Unchanged} {
+		for _, change := range changes {
+			for _, field := range []string{change.Key, change.Locale.String(), change.Current, change.Imported} {
+				hash.Write([]byte(field))
+				hash.Write([]byte{0})
+			}
+		}
+		hash.Write([]byte{1})
+	}
+	return base64.RawURLEncoding.EncodeToString(hash.Sum(nil)[:12])
+}
+
+// catalogImport writes the translations of an import in its transaction.
+type catalogImport struct {
+	tx        *gorm.DB
+	namespace string
+	locale    Locale
+	// current holds the translations of the locale before the import.
+	current map[string]Translation
+	seen    map[string]bool
+	report  *ImportReport
+}
+
+// runImport runs an import in a transaction recording author in the revision
+// history.
+func (c catalogStore) runImport(namespace string, locale Locale, author string, run func(i *catalogImport) error) (*ImportReport, error) {
+	report := &ImportReport{}
+
+	err := c.db.Transaction(func(tx *gorm.DB) error {
+		if err := setAuthor(tx, author); err != nil {
+			return err
+		}
+
+		var existing []Translation
+		if err := tx.Where("namespace = ? AND locale = ?", namespace, locale).Find(&existing).Error; err != nil {
+			return err
+		}
+
+		current := make(map[string]Translation, len(existing))
+		for _, entity := range existing {
+			current[entity.LanguageKey] = entity
+		}
+
+		return run(&catalogImport{
+			tx:        tx,
+			namespace: namespace,
+			locale:    locale,
+			current:   current,
+			seen:      map[string]bool{},
+			report:    report,
+		})
+	})
+	if isForeignKeyViolation(err, translationLocaleConstraint) {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedLocale, locale)
+	}
+	if isForeignKeyViolation(err, translationNamespaceConstraint) {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownNamespace, namespace)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (i *catalogImport) skip(key, reason string) {
+	i.report.Skipped = append(i.report.Skipped, SkippedEntry{Key: key, Reason: reason})
+}
+
+// write creates or updates a validated translation, keys already imported and
+// unchanged translations are skipped.
+func (i *catalogImport) write(entity *Translation) error {
+	key := entity.LanguageKey
+	if i.seen[key] {
+		i.skip(key, SkipDuplicate)
+		return nil
+	}
+	i.seen[key] = true
+
+	stored, exists := i.current[key]
+	if exists && sameTranslation(&stored, entity) && stored.Comment == entity.Comment && stored.Fuzzy == entity.Fuzzy {
+		i.skip(key, SkipUnchanged)
+		return nil
+	}
+
+	if exists {
+		err := i.tx.Model(&Translation{}).
+			Where("namespace = ? AND language_key = ? AND locale = ?", i.namespace, key, i.locale).
+			Updates(map[string]any{
+				"translation":  entity.Translation,
+				"plural_forms": entity.PluralForms,
+				"comment":      entity.Comment,
+				"fuzzy":        entity.Fuzzy,
+			}).Error
+		if err != nil {
+			return err
+		}
+		i.report.Updated = append(i.report.Updated, key)
+		return nil
+	}
+
+	entity.Namespace = i.namespace
+	entity.Locale = i.locale
+	if err := i.tx.Create(entity).Error; err != nil {
+		return err
+	}
+	i.report.Created = append(i.report.Created, key)
+	return nil
+}
+
+// sameTranslation reports whether two translations hold the same text and
+// plural forms.
+func sameTranslation(a, b *Translation) bool {
+	return a.Translation == b.Translation && maps.Equal(a.PluralForms, b.PluralForms)
+}
+
+// catalogTranslation maps a catalog entry onto a translation of locale, or
+// returns why the entry is skipped.
+func catalogTranslation(entry CatalogEntry, locale Locale, plurals *gettextPlurals, pluralsErr error) (*Translation, string) {
+	if entry.Obsolete {
+		return nil, SkipObsolete
+	}
+	if !entry.Translated() {
+		return nil, SkipUntranslated
+	}
+
+	entity := &Translation{
+		LanguageKey: entry.Key(),
+		Locale:      locale,
+		Translation: entry.Strings[0],
+		Comment:     strings.Join(entry.TranslatorComments, "\n"),
+		Fuzzy:       entry.Fuzzy(),
+	}
+
+	if entry.IDPlural != nil {
+		if pluralsErr != nil {
+			return nil, pluralsErr.Error()
+		}
+		forms, err := plurals.pluralForms(entry.Strings)
+		if err != nil {
+			return nil, fmt.Sprintf("%v: %v", ErrInvalidCatalog, err)
+		}
+		entity.PluralForms = forms
+		entity.Translation = forms[PluralOther]
+	}
+
+	if err := entity.Validate(); err != nil {
+		return nil, err.Error()
+	}
+
+	return entity, ""
+}
+
+// catalogHeaders returns the header of an exported catalog, templates pass an
+// empty locale.
+func catalogHeaders(locale Locale) []CatalogHeader {
+	headers := []CatalogHeader{
+		{Name: "MIME-Version", Value: "1.0"},
+		{Name: "Content-Type", Value: "text/plain; charset=UTF-8"},
+		{Name: "Content-Transfer-Encoding", Value: "8bit"},
+	}
+	if locale != "" {
+		headers = append(headers,
+			CatalogHeader{Name: "Language", Value: locale.String()},
+			CatalogHeader{Name: "Plural-Forms", Value: GettextPluralForms(locale)},
+		)
+	}
+	return headers
+}
+
+// catalogLanguageMatches reports whether the Language header of a catalog
+// names locale or its language, e.g. "de" or "de-DE" for de_DE.
+func catalogLanguageMatches(language string, locale Locale) bool {
+	language = strings.ReplaceAll(strings.TrimSpace(language), "-", "_")
+	if language == "" || strings.EqualFold(language, locale.String()) {
+		return true
+	}
+
+	base, _, _ := strings.Cut(locale.String(), "_")
+	return strings.EqualFold(language, base)
+}
@@ -0,0 +1,257 @@
+package translation
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// ChangeFeed reads the change log of the translations of one namespace,
+// DefaultNamespace unless InNamespace is used. Subscriptions are woken up
+// whenever a ChangeListener reports a change, see AddHandler.
+type ChangeFeed struct {
+	db        *gorm.DB
+	namespace string
+	// hub is shared by the feeds of all namespaces, so a single ChangeHandler
+	// wakes up the subscriptions of every namespace.
+	hub *subscriptionHub
+}
+
+type subscriptionHub struct {
+	mu            sync.Mutex
+	subscriptions map[*Subscription]struct{}
+	closed        chan struct{}
+}
+
+// Subscription signals that changes of its locales may have been logged. The
+// signals are coalesced, so subscribers have to read all changes since the
+// version they have seen.
+type Subscription struct {
+	hub       *subscriptionHub
+	namespace string
+	locales   map[Locale]bool
+	changed   chan struct{}
+}
+
+func NewChangeFeed(db *gorm.DB) *ChangeFeed {
+	return &ChangeFeed{
+		db:        db,
+		namespace: DefaultNamespace,
+		hub: &subscriptionHub{
+			subscriptions: map[*Subscription]struct{}{},
+			closed:        make(chan struct{}),
+		},
+	}
+}
+
+// InNamespace returns a feed of the changes of namespace, sharing the
+// subscriptions of f.
+func (f *ChangeFeed) InNamespace(namespace string) *ChangeFeed {
+	return &ChangeFeed{
+		db:        f.db,
+		namespace: namespace,
+		hub:       f.hub,
+	}
+}
+
+// Subscribe to changes of locales, or of all locales if none are given.
+func (f *ChangeFeed) Subscribe(locales ...Locale) *Subscription {
+	subscription := &Subscription{
+		hub:       f.hub,
+		namespace: f.namespace,
+		locales:   map[Locale]bool{},
+		changed:   make(chan struct{}, 1),
+	}
+	for _, locale := range locales {
+		subscription.locales[locale] = true
+	}
+
+	f.hub.mu.Lock()
+	defer f.hub.mu.Unlock()
+
+	f.hub.subscriptions[subscription] = struct{}{}
+
+	return subscription
+}
+
+// Close ends all subscriptions of all namespaces, see Subscription.Done.
+func (f *ChangeFeed) Close() {
+	f.hub.mu.Lock()
+	defer f.hub.mu.Unlock()
+
+	select {
+	case <-f.hub.closed:
+	default:
+		close(f.hub.closed)
+	}
+}
+
+// Snapshot returns the translations stored for locale and the version of the
+// change log they reflect.
+func (f *ChangeFeed) Snapshot(locale Locale) ([]Translation, int64, error) {
+	var result []Translation
+	var version int64
+
+	err := f.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("namespace = ? AND locale = ?", f.namespace, locale).Order("language_key").Find(&result).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&TranslationChange{}).Select("COALESCE(MAX(version), 0)").Scan(&version).Error
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+
+	return result, version, err
+}
+
+// ChangesSince returns up to limit changes after version in ascending order,
+// restricted to locales if any are given.
+func (f *ChangeFeed) ChangesSince(version int64, limit int, locales ...Locale) ([]TranslationChange, error) {
+	var result []TranslationChange
+
+	db := f.db.Where("namespace = ? AND version > ?", f.namespace, version)
+	if len(locales) > 0 {
+		db = db.Where("locale IN ?", locales)
+	}
+
+	err := db.Order("version").Limit(limit).Find(&result).Error
+
+	return result, err
+}
+
+// Delta holds the changes of a fallback chain after a version: the current
+// translation of every key that changed and the keys that no longer resolve.
+type Delta struct {
+	Translations []Translation
+	DeletedKeys  []string
+	// Version to request the next delta from.
+	Version int64
+	// HasMore is set if the delta was cut at the limit.
+	HasMore bool
+}
+
+// Delta resolves the keys of up to limit changes of locales after version, see
+// Repository for the order of locales. A limit of zero means MaxPageSize.
+func (f *ChangeFeed) Delta(version int64, limit int, locales ...Locale) (*Delta, error) {
+	delta := &Delta{Version: version}
+
+	if limit <= 0 || limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	if len(locales) == 0 {
+		return delta, nil
+	}
+
+	err := f.db.Transaction(func(tx *gorm.DB) error {
+		var changes []TranslationChange
+		err := tx.Select("version", "language_key").
+			Where("namespace = ? AND version > ? AND locale IN ?", f.namespace, version, locales).
+			Order("version").
+			Limit(limit + 1).
+			Find(&changes).Error
+		if err != nil {
+			return err
+		}
+
+		if len(changes) > limit {
+			changes = changes[:limit]
+			delta.HasMore = true
+			delta.Version = changes[limit-1].Version
+		} else if err := tx.Model(&TranslationChange{}).Select("COALESCE(MAX(version), ?)", version).Scan(&delta.Version).Error; err != nil {
+			return err
+		}
+
+		if len(changes) == 0 {
+			return nil
+		}
+
+		keys := make([]string, 0, len(changes))
+		for _, change := range changes {
+			keys = append(keys, change.LanguageKey)
+		}
+
+		if err := resolvedTranslations(tx.Where("namespace = ?", f.namespace), locales).Where("language_key IN ?", keys).Find(&delta.Translations).Error; err != nil {
+			return err
+		}
+		delta.DeletedKeys = MissingKeys(keys, delta.Translations)
+
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return delta, nil
+}
+
+// ValidateVersion rejects versions the change log has not reached yet, which
+// a client can only hold if the log was reset.
+func (f *ChangeFeed) ValidateVersion(version int64) error {
+	if version < 0 {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	var latest int64
+	if err := f.db.Model(&TranslationChange{}).Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+		return err
+	}
+	if version > latest {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	return nil
+}
+
+// TranslationChanged implements ChangeHandler.
+func (f *ChangeFeed) TranslationChanged(change Change) {
+	if change.Operation == OperationNamespace {
+		return
+	}
+
+	f.hub.mu.Lock()
+	defer f.hub.mu.Unlock()
+
+	for subscription := range f.hub.subscriptions {
+		if subscription.namespace != change.Namespace {
+			continue
+		}
+		if len(subscription.locales) == 0 || subscription.locales[change.Locale] {
+			subscription.notify()
+		}
+	}
+}
+
+// ChangesLost implements ChangeHandler.
+func (f *ChangeFeed) ChangesLost() {
+	f.hub.mu.Lock()
+	defer f.hub.mu.Unlock()
+
+	for subscription := range f.hub.subscriptions {
+		subscription.notify()
+	}
+}
+
+func (s *Subscription) Changed() <-chan struct{} {
+	return s.changed
+}
+
+// Done is closed when the feed is closed, subscribers should stop then.
+func (s *Subscription) Done() <-chan struct{} {
+	return s.hub.closed
+}
+
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	delete(s.hub.subscriptions, s)
+}
+
+func (s *Subscription) notify() {
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
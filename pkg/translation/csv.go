@@ -0,0 +1,159 @@
+package translation
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CSVKeyColumn heads the key column of CSV tables, the other columns are
+// headed by locale codes.
+const CSVKeyColumn = "language_key"
+
+// maxCSVRows limits the number of rows of a parsed table.
+const maxCSVRows = 100000
+
+// formulaPrefixes are the characters that make spreadsheet applications
+// evaluate a cell as formula.
+const formulaPrefixes = "=+-@\t\r"
+
+// TranslationTable is a spreadsheet of translations with a row per key and a
+// column per locale. Pluralised translations are written as ICU plural
+// message of a count argument, such as
+// "{count, plural, one {One item} other {# items}}".
+type TranslationTable struct {
+	Locales []Locale
+	Rows    []TableRow
+}
+
+type TableRow struct {
+	Key string
+	// Cells holds the message of every locale of the table in column order,
+	// an empty cell stands for a missing translation.
+	Cells []string
+}
+
+// ParseCSV reads a table whose first column is headed by CSVKeyColumn. The
+// byte order mark spreadsheet applications put in front is skipped, as is the
+// apostrophe WriteCSV puts in front of cells looking like formulas. Keys must
+// be unique and not empty.
+func ParseCSV(r io.Reader) (*TranslationTable, error) {
+	input := bufio.NewReader(r)
+	if bom, err := input.Peek(3); err == nil && string(bom) == "\xef\xbb\xbf" {
+		_, _ = input.Discard(3)
+	}
+
+	reader := csv.NewReader(input)
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: missing header", ErrInvalidCSV)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCSV, err)
+	}
+	if strings.TrimSpace(header[0]) != CSVKeyColumn {
+		return nil, fmt.Errorf("%w: first column is %q instead of %q", ErrInvalidCSV, header[0], CSVKeyColumn)
+	}
+
+	table := &TranslationTable{}
+	seenLocales := map[Locale]bool{}
+	for _, column := range header[1:] {
+		locale := Locale(strings.TrimSpace(column))
+		if locale == "" {
+			return nil, fmt.Errorf("%w: column without locale", ErrInvalidCSV)
+		}
+		if seenLocales[locale] {
+			return nil, fmt.Errorf("%w: duplicate column %s", ErrInvalidCSV, locale)
+		}
+		seenLocales[locale] = true
+		table.Locales = append(table.Locales, locale)
+	}
+
+	seenKeys := map[string]bool{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCSV, err)
+		}
+
+		for i, cell := range record {
+			record[i] = unescapeFormula(cell)
+		}
+
+		line, _ := reader.FieldPos(0)
+		if len(table.Rows) == maxCSVRows {
+			return nil, fmt.Errorf("%w: more than %d rows", ErrInvalidCSV, maxCSVRows)
+		}
+		if record[0] == "" {
+			return nil, fmt.Errorf("%w: line %d: empty key", ErrInvalidCSV, line)
+		}
+		if seenKeys[record[0]] {
+			return nil, fmt.Errorf("%w: line %d: duplicate key %q", ErrInvalidCSV, line, record[0])
+		}
+		seenKeys[record[0]] = true
+
+		table.Rows = append(table.Rows, TableRow{Key: record[0], Cells: record[1:]})
+	}
+
+	return table, nil
+}
+
+// WriteCSV writes the table with CRLF line endings, as spreadsheet
+// applications expect. Cells starting with a formula character such as "="
+// are prefixed with an apostrophe, so spreadsheets show them as text rather
+// than evaluate them.
+func WriteCSV(w io.Writer, table *TranslationTable) error {
+	writer := csv.NewWriter(w)
+	writer.UseCRLF = true
+
+	header := []string{CSVKeyColumn}
+	for _, locale := range table.Locales {
+		header = append(header, locale.String())
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range table.Rows {
+		record := []string{escapeFormula(row.Key)}
+		for _, cell := range row.Cells {
+			record = append(record, escapeFormula(cell))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func escapeFormula(cell string) string {
+	if looksLikeFormula(cell) {
+		return "'" + cell
+	}
+	return cell
+}
+
+// unescapeFormula reverses escapeFormula.
+func unescapeFormula(cell string) string {
+	if strings.HasPrefix(cell, "'") && looksLikeFormula(cell[1:]) {
+		return cell[1:]
+	}
+	return cell
+}
+
+// looksLikeFormula reports whether cell starts with a formula character. Cells
+// starting with apostrophes in front of one are escaped as well, so the
+// apostrophe removed by unescapeFormula is always the added one.
+func looksLikeFormula(cell string) bool {
+	cell = strings.TrimLeft(cell, "'")
+	return cell != "" && strings.ContainsRune(formulaPrefixes, rune(cell[0]))
+}
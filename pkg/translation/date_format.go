@@ -0,0 +1,429 @@
+package translation
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// dateSymbols holds the CLDR patterns and names used to format dates and
+// times in a locale. Patterns are ordered full, long, medium, short.
+type dateSymbols struct {
+	dateFormats    [4]string
+	timeFormats    [4]string
+	dateTimeFormat string
+	months         [12]string
+	shortMonths    [12]string
+	weekdays       [7]string
+	shortWeekdays  [7]string
+	dayPeriods     [2]string
+}
+
+var englishNames = dateSymbols{
+	months:        [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+	shortMonths:   [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+	weekdays:      [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+	shortWeekdays: [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+	dayPeriods:    [2]string{"AM", "PM"},
+}
+
+// isoDateSymbols formats locales without symbols of their own. Without CLDR
+// names for them, dates are shown in ISO 8601 order with numeric months and
+// weekdays rather than in the words of another language.
+var isoDateSymbols = dateSymbols{
+	dateFormats:    [4]string{"y-MM-dd", "y-MM-dd", "y-MM-dd", "y-MM-dd"},
+	timeFormats:    [4]string{"HH:mm:ss z", "HH:mm:ss z", "HH:mm:ss", "HH:mm"},
+	dateTimeFormat: "{1} {0}",
+	months:         [12]string{"01", "02", "03", "04", "05", "06", "07", "08", "09", "10", "11", "12"},
+	shortMonths:    [12]string{"01", "02", "03", "04", "05", "06", "07", "08", "09", "10", "11", "12"},
+	weekdays:       [7]string{"7", "1", "2", "3", "4", "5", "6"},
+	shortWeekdays:  [7]string{"7", "1", "2", "3", "4", "5", "6"},
+	dayPeriods:     [2]string{"AM", "PM"},
+}
+
+// dateSymbolsByTag holds the CLDR symbols of the locales dates and times are
+// formatted for. It is looked up by language tag first, then by language.
+var dateSymbolsByTag = map[string]dateSymbols{
+	"en": withPatterns(englishNames,
+		[4]string{"EEEE, MMMM d, y", "MMMM d, y", "MMM d, y", "M/d/yy"},
+		[4]string{"h:mm:ss a z", "h:mm:ss a z", "h:mm:ss a", "h:mm a"},
+		"{1}, {0}"),
+	"en-GB": withPatterns(englishNames,
+		[4]string{"EEEE d MMMM y", "d MMMM y", "d MMM y", "dd/MM/y"},
+		[4]string{"HH:mm:ss z", "HH:mm:ss z", "HH:mm:ss", "HH:mm"},
+		"{1}, {0}"),
+	"de": {
+		dateFormats:    [4]string{"EEEE, d. MMMM y", "d. MMMM y", "dd.MM.y", "dd.MM.yy"},
+		timeFormats:    [4]string{"HH:mm:ss z", "HH:mm:ss z", "HH:mm:ss", "HH:mm"},
+		dateTimeFormat: "{1}, {0}",
+		months:         [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		shortMonths:    [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+		weekdays:       [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		shortWeekdays:  [7]string{"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."},
+		dayPeriods:     [2]string{"AM", "PM"},
+	},
+	"fr": {
+		dateFormats:    [4]string{"EEEE d MMMM y", "d MMMM y", "d MMM y", "dd/MM/y"},
+		timeFormats:    [4]string{"HH:mm:ss z", "HH:mm:ss z", "HH:mm:ss", "HH:mm"},
+		dateTimeFormat: "{1} {0}",
+		months:         [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		shortMonths:    [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		weekdays:       [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		shortWeekdays:  [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+		dayPeriods:     [2]string{"AM", "PM"},
+	},
+	"es": {
+		dateFormats:    [4]string{"EEEE, d 'de' MMMM 'de' y", "d 'de' MMMM 'de' y", "d MMM y", "d/M/yy"},
+		timeFormats:    [4]string{"H:mm:ss z", "H:mm:ss z", "H:mm:ss", "H:mm"},
+		dateTimeFormat: "{1}, {0}",
+		months:         [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		shortMonths:    [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		weekdays:       [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		shortWeekdays:  [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		dayPeriods:     [2]string{"a. m.", "p. m."},
+	},
+}
+
+var dateStyles = map[string]int{"full": 0, "long": 1, "medium": 2, "": 2, "short": 3}
+
+func withPatterns(names dateSymbols, dateFormats, timeFormats [4]string, dateTimeFormat string) dateSymbols {
+	names.dateFormats = dateFormats
+	names.timeFormats = timeFormats
+	names.dateTimeFormat = dateTimeFormat
+	return names
+}
+
+// lookupDateSymbols falls back to isoDateSymbols for locales without symbols
+// rather than formatting them in the names of another locale.
+func lookupDateSymbols(tag language.Tag) dateSymbols {
+	if symbols, ok := dateSymbolsByTag[tag.String()]; ok {
+		return symbols
+	}
+	base, _ := tag.Base()
+	if symbols, ok := dateSymbolsByTag[base.String()]; ok {
+		return symbols
+	}
+	return isoDateSymbols
+}
+
+// formatDateTime formats t for a date or time argument. style is one of
+// short, medium, long or full, or a skeleton such as "::yMMMd" that selects
+// the fields of the closest locale pattern.
+func formatDateTime(tag language.Tag, t time.Time, kind, style string) (string, error) {
+	symbols := lookupDateSymbols(tag)
+
+	if skeleton, ok := strings.CutPrefix(style, "::"); ok {
+		pattern, err := symbols.skeletonPattern(skeleton)
+		if err != nil {
+			return "", err
+		}
+		return symbols.format(t, pattern)
+	}
+
+	index, ok := dateStyles[style]
+	if !ok {
+		return "", fmt.Errorf("%w: unsupported %s style %q", ErrInvalidMessage, kind, style)
+	}
+
+	if kind == "time" {
+		return symbols.format(t, symbols.timeFormats[index])
+	}
+	return symbols.format(t, symbols.dateFormats[index])
+}
+
+// patternToken is a field such as "MMMM" or literal text of a pattern.
+type patternToken struct {
+	field   byte
+	width   int
+	literal string
+}
+
+func parsePattern(pattern string) ([]patternToken, error) {
+	var tokens []patternToken
+
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+
+		switch {
+		case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			j := i
+			for j < len(pattern) && pattern[j] == c {
+				j++
+			}
+			tokens = append(tokens, patternToken{field: c, width: j - i})
+			i = j
+		case c == '\'':
+			end := strings.IndexByte(pattern[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unclosed quote in date pattern %q", ErrInvalidMessage, pattern)
+			}
+			literal := pattern[i+1 : i+1+end]
+			if literal == "" {
+				literal = "'"
+			}
+			tokens = append(tokens, patternToken{literal: literal})
+			i += end + 2
+		default:
+			j := i
+			for j < len(pattern) && !(pattern[j] >= 'a' && pattern[j] <= 'z' || pattern[j] >= 'A' && pattern[j] <= 'Z' || pattern[j] == '\'') {
+				j++
+			}
+			tokens = append(tokens, patternToken{literal: pattern[i:j]})
+			i = j
+		}
+	}
+
+	return tokens, nil
+}
+
+// fieldClass groups the pattern letters standing for the same field.
+func fieldClass(field byte) byte {
+	switch field {
+	case 'L':
+		return 'M'
+	case 'c', 'e':
+		return 'E'
+	case 'H', 'k', 'K', 'j':
+		return 'h'
+	case 'Z', 'v', 'V', 'O', 'x', 'X':
+		return 'z'
+	default:
+		return field
+	}
+}
+
+// skeletonPattern derives a pattern from a skeleton: the fields of the
+// skeleton are picked from the locale pattern closest in width, keeping the
+// locale order and separators.
+func (s dateSymbols) skeletonPattern(skeleton string) (string, error) {
+	fields, err := parsePattern(skeleton)
+	if err != nil {
+		return "", err
+	}
+
+	requested := map[byte]patternToken{}
+	for _, token := range fields {
+		if token.field == 0 {
+			return "", fmt.Errorf("%w: invalid date skeleton %q", ErrInvalidMessage, skeleton)
+		}
+		requested[fieldClass(token.field)] = token
+	}
+
+	var dateFields, timeFields bool
+	for class := range requested {
+		switch class {
+		case 'y', 'M', 'd', 'E':
+			dateFields = true
+		case 'h', 'm', 's', 'a', 'z':
+			timeFields = true
+		default:
+			return "", fmt.Errorf("%w: unsupported date skeleton field %q", ErrInvalidMessage, string(class))
+		}
+	}
+
+	var datePattern, timePattern string
+
+	if dateFields {
+		index := 3
+		switch {
+		case requested['E'].field != 0:
+			index = 0
+		case requested['M'].width >= 4:
+			index = 1
+		case requested['M'].width == 3:
+			index = 2
+		}
+		if datePattern, err = selectFields(s.dateFormats[index], requested); err != nil {
+			return "", err
+		}
+	}
+
+	if timeFields {
+		index := 2
+		if requested['z'].field != 0 {
+			index = 1
+		}
+		if timePattern, err = selectFields(s.timeFormats[index], requested); err != nil {
+			return "", err
+		}
+	}
+
+	switch {
+	case datePattern == "":
+		return timePattern, nil
+	case timePattern == "":
+		return datePattern, nil
+	default:
+		return strings.NewReplacer("{1}", datePattern, "{0}", timePattern).Replace(s.dateTimeFormat), nil
+	}
+}
+
+// selectFields keeps the fields of pattern requested by the skeleton with the
+// width of the skeleton. A dropped date field takes the separator following
+// it along, a dropped time field the one preceding it, so that "h:mm:ss a"
+// becomes "h:mm a" and "EEEE, d. MMMM" becomes "EEEE, MMMM".
+func selectFields(pattern string, requested map[byte]patternToken) (string, error) {
+	tokens, err := parsePattern(pattern)
+	if err != nil {
+		return "", err
+	}
+
+	// A 12 hour clock needs the day period even if the skeleton omits it.
+	hasPeriod := false
+	twelveHour := requested['h'].field == 'h'
+	for _, token := range tokens {
+		hasPeriod = hasPeriod || token.field == 'a'
+		twelveHour = twelveHour || requested['h'].field == 'j' && token.field == 'h'
+	}
+	if _, ok := requested['a']; twelveHour && !ok {
+		requested = maps.Clone(requested)
+		requested['a'] = patternToken{field: 'a', width: 1}
+	}
+
+	result := strings.Builder{}
+	pendingLiteral := ""
+	kept, dropped := false, false
+
+	for _, token := range tokens {
+		if token.field == 0 {
+			if !dropped {
+				pendingLiteral += token.literal
+			}
+			continue
+		}
+
+		want, ok := requested[fieldClass(token.field)]
+		if !ok {
+			switch fieldClass(token.field) {
+			case 'h', 'm', 's':
+				pendingLiteral = ""
+			default:
+				dropped = true
+			}
+			continue
+		}
+
+		if kept {
+			result.WriteString(quoteLiteral(pendingLiteral))
+		}
+		pendingLiteral = ""
+		dropped = false
+
+		field := token.field
+		width := want.width
+		switch fieldClass(token.field) {
+		case 'm', 's', 'a', 'z':
+			width = token.width
+		case 'h':
+			// Keep the hour cycle of the locale unless the skeleton asks for one.
+			if want.field != 'j' {
+				field = want.field
+			}
+			width = min(width, 2)
+		case 'y':
+			if width != 2 {
+				width = 1
+			}
+		}
+
+		result.WriteString(strings.Repeat(string(field), width))
+		kept = true
+	}
+
+	if twelveHour && !hasPeriod {
+		result.WriteString(" a")
+	}
+
+	return result.String(), nil
+}
+
+func quoteLiteral(literal string) string {
+	if strings.ContainsFunc(literal, func(r rune) bool { return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '\'' }) {
+		return "'" + strings.ReplaceAll(literal, "'", "''") + "'"
+	}
+	return literal
+}
+
+func (s dateSymbols) format(t time.Time, pattern string) (string, error) {
+	tokens, err := parsePattern(pattern)
+	if err != nil {
+		return "", err
+	}
+
+	result := strings.Builder{}
+
+	for _, token := range tokens {
+		if token.field == 0 {
+			result.WriteString(token.literal)
+			continue
+		}
+
+		switch token.field {
+		case 'y':
+			if token.width == 2 {
+				result.WriteString(fmt.Sprintf("%02d", t.Year()%100))
+			} else {
+				result.WriteString(padNumber(t.Year(), token.width))
+			}
+		case 'M', 'L':
+			switch {
+			case token.width >= 4:
+				result.WriteString(s.months[t.Month()-1])
+			case token.width == 3:
+				result.WriteString(s.shortMonths[t.Month()-1])
+			default:
+				result.WriteString(padNumber(int(t.Month()), token.width))
+			}
+		case 'd':
+			result.WriteString(padNumber(t.Day(), token.width))
+		case 'E', 'c', 'e':
+			if token.width >= 4 {
+				result.WriteString(s.weekdays[t.Weekday()])
+			} else {
+				result.WriteString(s.shortWeekdays[t.Weekday()])
+			}
+		case 'a':
+			result.WriteString(s.dayPeriods[t.Hour()/12])
+		case 'h':
+			hour := t.Hour() % 12
+			if hour == 0 {
+				hour = 12
+			}
+			result.WriteString(padNumber(hour, token.width))
+		case 'H':
+			result.WriteString(padNumber(t.Hour(), token.width))
+		case 'K':
+			result.WriteString(padNumber(t.Hour()%12, token.width))
+		case 'k':
+			hour := t.Hour()
+			if hour == 0 {
+				hour = 24
+			}
+			result.WriteString(padNumber(hour, token.width))
+		case 'm':
+			result.WriteString(padNumber(t.Minute(), token.width))
+		case 's':
+			result.WriteString(padNumber(t.Second(), token.width))
+		case 'z', 'v', 'V', 'O':
+			result.WriteString(t.Format("MST"))
+		case 'Z', 'x', 'X':
+			result.WriteString(t.Format("-0700"))
+		default:
+			return "", fmt.Errorf("%w: unsupported date field %q", ErrInvalidMessage, strings.Repeat(string(token.field), token.width))
+		}
+	}
+
+	return result.String(), nil
+}
+
+func padNumber(n, width int) string {
+	text := strconv.Itoa(n)
+	if len(text) < width {
+		text = strings.Repeat("0", width-len(text)) + text
+	}
+	return text
+}
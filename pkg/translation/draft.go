@@ -0,0 +1,352 @@
+package translation
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Status is the workflow status of a translation value. A draft is submitted
+// for review, approved or rejected back to draft by a reviewer, and published
+// once approved.
+type Status string
+
+const (
+	StatusDraft     Status = "draft"
+	StatusInReview  Status = "in_review"
+	StatusApproved  Status = "approved"
+	StatusPublished Status = "published"
+)
+
+// transitions lists the statuses a draft may move to from each status.
+var transitions = map[Status][]Status{
+	StatusDraft:    {StatusInReview},
+	StatusInReview: {StatusApproved, StatusDraft},
+	StatusApproved: {StatusPublished, StatusDraft},
+}
+
+// CanTransition reports whether a draft in status s may move to next.
+func (s Status) CanTransition(next Status) bool {
+	return slices.Contains(transitions[s], next)
+}
+
+// predecessors returns the statuses a draft may move to next from.
+func predecessors(next Status) []Status {
+	var result []Status
+	for status, targets := range transitions {
+		if slices.Contains(targets, next) {
+			result = append(result, status)
+		}
+	}
+	return result
+}
+
+// DraftStore manages unpublished work on translations. A translation has at
+// most one draft per locale, the published value stays in Repository until
+// the draft is published. A DraftStore serves the drafts of one namespace,
+// DefaultNamespace unless InNamespace is used.
+type DraftStore interface {
+	InNamespace(namespace string) DraftStore
+	// ListDrafts returns the drafts in status, or all drafts if status is
+	// empty, least recently changed first.
+	ListDrafts(status Status) ([]Draft, error)
+	GetDraft(key string, locale Locale) (*Draft, error)
+	// SaveDraft creates or edits the draft of a translation, plural forms are
+	// validated as by Repository. Drafts under review cannot be edited until
+	// they are rejected.
+	SaveDraft(key string, locale Locale, text string, forms PluralForms, author string) (*Draft, error)
+	DiscardDraft(key string, locale Locale) error
+	Submit(key string, locale Locale) (*Draft, error)
+	Approve(key string, locale Locale, reviewer, comment string) (*Draft, error)
+	Reject(key string, locale Locale, reviewer, comment string) (*Draft, error)
+	// Publish writes the text of an approved draft to the translation and
+	// removes the draft in a single transaction.
+	Publish(key string, locale Locale, author string) (*Translation, error)
+	// PreviewRepository serves drafts in place of the published values,
+	// writes fail with ErrPreviewReadOnly.
+	PreviewRepository() Repository
+}
+
+type draftStore struct {
+	db        *gorm.DB
+	namespace string
+}
+
+func NewDraftStore(db *gorm.DB) DraftStore {
+	return &draftStore{
+		db:        db,
+		namespace: DefaultNamespace,
+	}
+}
+
+func (d draftStore) InNamespace(namespace string) DraftStore {
+	d.namespace = namespace
+	return d
+}
+
+func (d draftStore) ListDrafts(status Status) ([]Draft, error) {
+	var result []Draft
+
+	db := d.scoped().Order("updated_at, id")
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+
+	err := db.Find(&result).Error
+
+	return result, err
+}
+
+func (d draftStore) GetDraft(key string, locale Locale) (*Draft, error) {
+	result := Draft{}
+
+	err := d.scoped().Where("language_key = ? AND locale = ?", key, locale).Take(&result).Error
+
+	return &result, err
+}
+
+func (d draftStore) SaveDraft(key string, locale Locale, text string, forms PluralForms, author string) (*Draft, error) {
+	text = pluralText(text, forms)
+	if err := (Translation{LanguageKey: key, Locale: locale, Translation: text, PluralForms: forms}).Validate(); err != nil {
+		return nil, err
+	}
+
+	result := Draft{}
+
+	save := d.db.Raw(`INSERT INTO translation_draft (namespace, language_key, locale, translation, plural_forms, status, changed_by) VALUES (?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (namespace, language_key, locale) DO UPDATE SET translation = EXCLUDED.translation, plural_forms = EXCLUDED.plural_forms, changed_by = EXCLUDED.changed_by, updated_at = NOW()
+WHERE translation_draft.status = EXCLUDED.status
+RETURNING *`, d.namespace, key, locale, text, forms, StatusDraft, nullable(author)).Scan(&result)
+	if isForeignKeyViolation(save.Error, draftLocaleConstraint) {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedLocale, locale)
+	}
+	if isForeignKeyViolation(save.Error, draftNamespaceConstraint) {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownNamespace, d.namespace)
+	}
+	if save.Error != nil {
+		return nil, save.Error
+	}
+	if save.RowsAffected == 0 {
+		current, err := d.GetDraft(key, locale)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %s draft cannot be edited", ErrInvalidTransition, current.Status)
+	}
+
+	return &result, nil
+}
+
+func (d draftStore) DiscardDraft(key string, locale Locale) error {
+	deletion := d.scoped().Where("language_key = ? AND locale = ?", key, locale).Delete(&Draft{})
+	if deletion.Error != nil {
+		return deletion.Error
+	}
+	if deletion.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (d draftStore) Submit(key string, locale Locale) (*Draft, error) {
+	return d.transition(key, locale, StatusInReview, map[string]any{})
+}
+
+func (d draftStore) Approve(key string, locale Locale, reviewer, comment string) (*Draft, error) {
+	return d.transition(key, locale, StatusApproved, map[string]any{
+		"comment":     nullable(comment),
+		"reviewed_by": nullable(reviewer),
+	})
+}
+
+func (d draftStore) Reject(key string, locale Locale, reviewer, comment string) (*Draft, error) {
+	return d.transition(key, locale, StatusDraft, map[string]any{
+		"comment":     nullable(comment),
+		"reviewed_by": nullable(reviewer),
+	})
+}
+
+func (d draftStore) Publish(key string, locale Locale, author string) (*Translation, error) {
+	var result *Translation
+
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		// The lock keeps the draft from being rejected or edited before the
+		// published value and the removal of the draft are committed.
+		draft := Draft{}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("namespace = ? AND language_key = ? AND locale = ? AND status IN ?", d.namespace, key, locale, predecessors(StatusPublished)).
+			Take(&draft).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return d.transitionError(key, locale, StatusPublished)
+		}
+		if err != nil {
+			return err
+		}
+
+		result, err = writeTranslation(repository{db: tx, namespace: d.namespace}, key, locale, draft.Translation, draft.PluralForms, author)
+		if err != nil {
+			return err
+		}
+
+		return tx.Delete(&draft).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (d draftStore) PreviewRepository() Repository {
+	rows := d.db.Table("translation").
+		Select(`COALESCE(translation.id, 0) AS id,
+COALESCE(translation_draft.namespace, translation.namespace) AS namespace,
+COALESCE(translation_draft.language_key, translation.language_key) AS language_key,
+COALESCE(translation_draft.locale, translation.locale) AS locale,
+COALESCE(translation_draft.translation, translation.translation) AS translation,
+CASE WHEN translation_draft.id IS NULL THEN translation.plural_forms ELSE translation_draft.plural_forms END AS plural_forms,
+COALESCE(translation.comment, '') AS comment,
+COALESCE(translation.fuzzy, false) AS fuzzy,
+COALESCE(translation.created_at, translation_draft.created_at) AS created_at,
+COALESCE(translation_draft.updated_at, translation.updated_at) AS updated_at,
+COALESCE(translation_draft.status, ?) AS status`, StatusPublished).
+		Joins("FULL JOIN translation_draft ON translation_draft.namespace = translation.namespace AND translation_draft.language_key = translation.language_key AND translation_draft.locale = translation.locale")
+
+	return previewRepository{
+		repository: repository{db: d.db.Table("(?) AS translation", rows).Session(&gorm.Session{}), namespace: d.namespace},
+	}
+}
+
+// scoped returns a query on the drafts of the namespace.
+func (d draftStore) scoped() *gorm.DB {
+	return d.db.Where("namespace = ?", d.namespace)
+}
+
+// transition moves a draft to next with the column updates applied.
+func (d draftStore) transition(key string, locale Locale, next Status, updates map[string]any) (*Draft, error) {
+	result := Draft{}
+	updates["status"] = next
+
+	update := d.scoped().Model(&result).
+		Clauses(clause.Returning{}).
+		Where("language_key = ? AND locale = ? AND status IN ?", key, locale, predecessors(next)).
+		Updates(updates)
+	if update.Error != nil {
+		return nil, update.Error
+	}
+	if update.RowsAffected == 0 {
+		return nil, d.transitionError(key, locale, next)
+	}
+
+	return &result, nil
+}
+
+// transitionError explains why the draft of key could not move to next, the
+// draft is either missing or in a status that does not lead to next.
+func (d draftStore) transitionError(key string, locale Locale, next Status) error {
+	current, err := d.GetDraft(key, locale)
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %s draft cannot become %s", ErrInvalidTransition, current.Status, next)
+}
+
+// previewRepository runs the queries of repository against the translations
+// with their drafts applied.
+type previewRepository struct {
+	repository
+}
+
+func (p previewRepository) InNamespace(namespace string) Repository {
+	return previewRepository{repository: p.inNamespace(namespace)}
+}
+
+func (previewRepository) CreateTranslation(*Translation, string) error {
+	return ErrPreviewReadOnly
+}
+
+func (previewRepository) UpdateTranslation(string, Locale, string, PluralForms, string) (*Translation, error) {
+	return nil, ErrPreviewReadOnly
+}
+
+func (previewRepository) DeleteTranslation(string, Locale, string) error {
+	return ErrPreviewReadOnly
+}
+
+// RequireReview returns a Repository that rejects CreateTranslation,
+// UpdateTranslation and DeleteTranslation with ErrReviewRequired, so values
+// only go live through DraftStore.Publish once a reviewer approved them.
+// Reads are passed on to repo.
+func RequireReview(repo Repository) Repository {
+	return reviewedRepository{Repository: repo}
+}
+
+type reviewedRepository struct {
+	Repository
+}
+
+func (r reviewedRepository) InNamespace(namespace string) Repository {
+	return reviewedRepository{Repository: r.Repository.InNamespace(namespace)}
+}
+
+func (reviewedRepository) CreateTranslation(*Translation, string) error {
+	return ErrReviewRequired
+}
+
+func (reviewedRepository) UpdateTranslation(string, Locale, string, PluralForms, string) (*Translation, error) {
+	return nil, ErrReviewRequired
+}
+
+func (reviewedRepository) DeleteTranslation(string, Locale, string) error {
+	return ErrReviewRequired
+}
+
+// TranslationChanged and ChangesLost pass changes on to a caching repo.
+func (r reviewedRepository) TranslationChanged(change Change) {
+	if handler, ok := r.Repository.(ChangeHandler); ok {
+		handler.TranslationChanged(change)
+	}
+}
+
+func (r reviewedRepository) ChangesLost() {
+	if handler, ok := r.Repository.(ChangeHandler); ok {
+		handler.ChangesLost()
+	}
+}
+
+// RequireReviewedImports returns a CatalogStore that rejects imports with
+// ErrReviewRequired, as they would write the translations around the review
+// that RequireReview enforces. Exports and dry runs are passed on to store.
+func RequireReviewedImports(store CatalogStore) CatalogStore {
+	return reviewedCatalogStore{CatalogStore: store}
+}
+
+type reviewedCatalogStore struct {
+	CatalogStore
+}
+
+func (reviewedCatalogStore) ImportCatalog(string, Locale, *Catalog, string) (*ImportReport, error) {
+	return nil, ErrReviewRequired
+}
+
+func (reviewedCatalogStore) ImportXLIFF(string, Locale, *XLIFFDocument, string) (*ImportReport, error) {
+	return nil, ErrReviewRequired
+}
+
+func (r reviewedCatalogStore) ImportCSV(namespace string, table *TranslationTable, author string, dryRun bool, fingerprint string) (*TableDiff, error) {
+	if !dryRun {
+		return nil, ErrReviewRequired
+	}
+	return r.CatalogStore.ImportCSV(namespace, table, author, dryRun, fingerprint)
+}
+
+func nullable(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -1,6 +1,10 @@
 package translation
 
-import "time"
+import (
+	"fmt"
+	"slices"
+	"time"
+)
 
 type Locale string
 
@@ -9,15 +13,192 @@ func (l Locale) String() string { return string(l) }
 const (
 	LocaleDEDE Locale = "de_DE"
 	LocaleENGB Locale = "en_GB"
+
+	// DefaultLocale is served when a client expresses no preference.
+	DefaultLocale = LocaleENGB
+
+	// DefaultNamespace holds the translations of clients that do not name a
+	// namespace.
+	DefaultNamespace = "default"
 )
 
 type Translation struct {
 	ID          int       `gorm:"primaryKey"`
+	Namespace   string    `gorm:"type:text;not null;uniqueIndex:ux_translation_language_key_locale"`
 	LanguageKey string    `gorm:"type:text;not null;uniqueIndex:ux_translation_language_key_locale"`
-	Locale      Locale    `gorm:"type:locale;not null;uniqueIndex:ux_translation_language_key_locale"`
+	Locale      Locale    `gorm:"type:text;not null;uniqueIndex:ux_translation_language_key_locale"`
 	Translation string    `gorm:"type:text;not null"`
 	CreatedAt   time.Time `gorm:"autoCreateTime"`
 	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+	// PluralForms holds the variants of a pluralised translation, Translation
+	// then holds the PluralOther form.
+	PluralForms PluralForms `gorm:"type:jsonb"`
+	// Comment and Fuzzy hold the translator comment and the fuzzy flag of
+	// gettext catalogs, see CatalogStore.
+	Comment string `gorm:"type:text;not null;default:''"`
+	Fuzzy   bool   `gorm:"not null;default:false"`
+	// Status is only read by previews, other reads serve published values and
+	// leave it empty.
+	Status Status `gorm:"->;-:migration"`
 }
 
 func (Translation) TableName() string { return "translation" }
+
+// CurrentStatus returns the workflow status of the value.
+func (t Translation) CurrentStatus() Status {
+	if t.Status == "" {
+		return StatusPublished
+	}
+	return t.Status
+}
+
+func (t Translation) Validate() error {
+	if t.LanguageKey == "" {
+		return fmt.Errorf("%w: language key is required", ErrInvalidTranslation)
+	}
+	if t.Locale == "" {
+		return fmt.Errorf("%w: locale is required", ErrInvalidTranslation)
+	}
+	if t.Translation == "" {
+		return fmt.Errorf("%w: translation is required", ErrInvalidTranslation)
+	}
+	if len(t.PluralForms) == 0 {
+		return nil
+	}
+	if err := validatePluralForms(t.Locale, t.PluralForms); err != nil {
+		return err
+	}
+	if t.Translation != t.PluralForms[PluralOther] {
+		return fmt.Errorf("%w: translation must match the %q plural form", ErrInvalidTranslation, PluralOther)
+	}
+	return nil
+}
+
+type LocaleDefinition struct {
+	Code      Locale    `gorm:"primaryKey;type:text"`
+	Enabled   bool      `gorm:"not null;default:true"`
+	Fallback  *Locale   `gorm:"type:text"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+func (LocaleDefinition) TableName() string { return "locale" }
+
+// LocaleUpdate holds the changes applied by LocaleRegistry.UpdateLocale, nil
+// fields are left untouched. An empty Fallback removes the fallback.
+type LocaleUpdate struct {
+	Enabled  *bool
+	Fallback *string
+}
+
+// Namespace partitions the translation keys of a project. DefaultLocale and
+// Locales are optional, see EffectiveDefaultLocale and Enables.
+type Namespace struct {
+	Name          string  `gorm:"primaryKey;type:text"`
+	Description   string  `gorm:"not null;default:''"`
+	DefaultLocale *Locale `gorm:"type:text"`
+	// Locales lists the locales enabled in the namespace, empty if all
+	// enabled locales are.
+	Locales   []Locale  `gorm:"-"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+func (Namespace) TableName() string { return "namespace" }
+
+// EffectiveDefaultLocale returns the locale served when a client of the
+// namespace expresses no preference.
+func (n Namespace) EffectiveDefaultLocale() Locale {
+	if n.DefaultLocale == nil {
+		return DefaultLocale
+	}
+	return *n.DefaultLocale
+}
+
+// Enables reports whether locale may be used in the namespace.
+func (n Namespace) Enables(locale Locale) bool {
+	return len(n.Locales) == 0 || slices.Contains(n.Locales, locale)
+}
+
+// FilterLocales returns the locales of chain enabled in the namespace, keeping
+// their order.
+func (n Namespace) FilterLocales(chain []Locale) []Locale {
+	return slices.DeleteFunc(slices.Clone(chain), func(locale Locale) bool {
+		return !n.Enables(locale)
+	})
+}
+
+type namespaceLocale struct {
+	Namespace string `gorm:"primaryKey;type:text"`
+	Locale    Locale `gorm:"primaryKey;type:text"`
+}
+
+func (namespaceLocale) TableName() string { return "namespace_locale" }
+
+// NamespaceUpdate holds the settings applied by NamespaceRegistry, nil fields
+// are left untouched. An empty DefaultLocale removes the default locale and an
+// empty Locales enables all locales.
+type NamespaceUpdate struct {
+	Description   *string
+	DefaultLocale *string
+	Locales       *[]string
+}
+
+// TranslationChange is an entry of the change log the translation table
+// triggers write. Translation is nil for deletions.
+type TranslationChange struct {
+	Version     int64  `gorm:"primaryKey"`
+	Operation   string `gorm:"not null"`
+	Namespace   string `gorm:"type:text;not null"`
+	Locale      Locale `gorm:"type:text;not null"`
+	LanguageKey string `gorm:"not null"`
+	Translation *string
+	ChangedAt   time.Time
+}
+
+func (TranslationChange) TableName() string { return "translation_change" }
+
+type Release struct {
+	ID          int       `gorm:"primaryKey"`
+	Description string    `gorm:"not null;default:''"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	// Latest is set if the latest tag points to the release.
+	Latest bool `gorm:"->;-:migration"`
+}
+
+func (Release) TableName() string { return "release" }
+
+// Revision is a value a translation had, Translation is nil for deletions and
+// ChangedBy for anonymous changes.
+type Revision struct {
+	ID          int64  `gorm:"primaryKey"`
+	Namespace   string `gorm:"type:text;not null"`
+	LanguageKey string `gorm:"not null"`
+	Locale      Locale `gorm:"type:text;not null"`
+	Operation   string `gorm:"not null"`
+	Translation *string
+	PluralForms PluralForms
+	ChangedBy   *string
+	ChangedAt   time.Time
+}
+
+func (Revision) TableName() string { return "translation_revision" }
+
+// Draft is unpublished work on a translation. Comment and ReviewedBy hold the
+// last review, ChangedBy the author of the text.
+type Draft struct {
+	ID          int64  `gorm:"primaryKey"`
+	Namespace   string `gorm:"type:text;not null"`
+	LanguageKey string `gorm:"not null"`
+	Locale      Locale `gorm:"type:text;not null"`
+	Translation string `gorm:"not null"`
+	Status      Status `gorm:"type:text;not null"`
+	PluralForms PluralForms
+	Comment     *string
+	ChangedBy   *string
+	ReviewedBy  *string
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+}
+
+func (Draft) TableName() string { return "translation_draft" }
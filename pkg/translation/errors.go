@@ -0,0 +1,58 @@
+package translation
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	ErrTranslationExists  = errors.New("translation already exists")
+	ErrInvalidTranslation = errors.New("invalid translation")
+	ErrUnsupportedLocale  = errors.New("unsupported locale")
+	ErrLocaleExists       = errors.New("locale already exists")
+	ErrInvalidLocale      = errors.New("invalid locale code")
+	ErrInvalidListOptions = errors.New("invalid list options")
+	ErrUnknownVersion     = errors.New("unknown version")
+	ErrInvalidRelease     = errors.New("invalid release")
+	ErrReleaseImmutable   = errors.New("releases are immutable")
+	ErrInvalidRevision    = errors.New("invalid revision")
+	ErrInvalidTransition  = errors.New("invalid status transition")
+	ErrPreviewReadOnly    = errors.New("previews are read-only")
+	ErrReviewRequired     = errors.New("translations are published through reviewed drafts")
+	ErrUnknownNamespace   = errors.New("unknown namespace")
+	ErrInvalidNamespace   = errors.New("invalid namespace")
+	ErrNamespaceExists    = errors.New("namespace already exists")
+	ErrNamespaceNotEmpty  = errors.New("namespace is not empty")
+	ErrInvalidMessage     = errors.New("invalid message format")
+	ErrInvalidArgument    = errors.New("invalid message argument")
+	ErrInvalidCount       = errors.New("invalid plural count")
+	ErrInvalidCatalog     = errors.New("invalid gettext catalog")
+	ErrInvalidXLIFF       = errors.New("invalid XLIFF document")
+	ErrInvalidCSV         = errors.New("invalid CSV table")
+	ErrStaleDiff          = errors.New("table diff changed since the dry run")
+	ErrInvalidSearch      = errors.New("invalid search")
+)
+
+const (
+	uniqueTranslationConstraint      = "translation_unique_key"
+	translationLocaleConstraint      = "translation_locale_fkey"
+	localePrimaryKeyConstraint       = "locale_pkey"
+	localeFallbackConstraint         = "locale_fallback_fkey"
+	draftLocaleConstraint            = "translation_draft_locale_fkey"
+	translationNamespaceConstraint   = "translation_namespace_fkey"
+	draftNamespaceConstraint         = "translation_draft_namespace_fkey"
+	namespacePrimaryKeyConstraint    = "namespace_pkey"
+	namespaceDefaultLocaleConstraint = "namespace_default_locale_fkey"
+	namespaceLocaleConstraint        = "namespace_locale_locale_fkey"
+)
+
+func isUniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == constraint
+}
+
+func isForeignKeyViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503" && pgErr.ConstraintName == constraint
+}
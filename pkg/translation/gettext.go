@@ -0,0 +1,611 @@
+package translation
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// GettextContextSeparator joins msgctxt and msgid into a translation key, as
+// in compiled MO files. Entries without a context use the msgid as key.
+const GettextContextSeparator = "\x04"
+
+// FlagFuzzy marks a catalog entry whose translation needs review.
+const FlagFuzzy = "fuzzy"
+
+const (
+	moMagic         = 0x950412de
+	moHeaderSize    = 28
+	maxCatalogLines = 1_000_000
+)
+
+// Catalog is a gettext PO, POT or MO file. Headers hold the fields of the
+// header entry in file order.
+type Catalog struct {
+	Headers []CatalogHeader
+	Entries []CatalogEntry
+}
+
+type CatalogHeader struct {
+	Name  string
+	Value string
+}
+
+// CatalogEntry is a message of a catalog. Strings holds msgstr, or msgstr[0]
+// to msgstr[n] if IDPlural is set. MO files only carry Context, ID, IDPlural
+// and Strings.
+type CatalogEntry struct {
+	Context            *string
+	ID                 string
+	IDPlural           *string
+	Strings            []string
+	TranslatorComments []string
+	ExtractedComments  []string
+	References         []string
+	Flags              []string
+	Obsolete           bool
+}
+
+// Key returns the translation key of the entry.
+func (e CatalogEntry) Key() string {
+	if e.Context == nil {
+		return e.ID
+	}
+	return *e.Context + GettextContextSeparator + e.ID
+}
+
+func (e CatalogEntry) Fuzzy() bool {
+	return slices.Contains(e.Flags, FlagFuzzy)
+}
+
+// Translated reports whether every msgstr of the entry is filled in.
+func (e CatalogEntry) Translated() bool {
+	return len(e.Strings) > 0 && !slices.Contains(e.Strings, "")
+}
+
+// catalogEntryForKey returns an entry with the msgctxt and msgid encoded in
+// key.
+func catalogEntryForKey(key string) CatalogEntry {
+	context, id, ok := strings.Cut(key, GettextContextSeparator)
+	if !ok {
+		return CatalogEntry{ID: key}
+	}
+	return CatalogEntry{Context: &context, ID: id}
+}
+
+// Header returns the value of the header field name, matched case
+// insensitively.
+func (c *Catalog) Header(name string) (string, bool) {
+	for _, header := range c.Headers {
+		if strings.EqualFold(header.Name, name) {
+			return header.Value, true
+		}
+	}
+	return "", false
+}
+
+func (c *Catalog) headerText() string {
+	var text strings.Builder
+	for _, header := range c.Headers {
+		text.WriteString(header.Name + ": " + header.Value + "\n")
+	}
+	return text.String()
+}
+
+func parseHeaders(text string) []CatalogHeader {
+	var result []CatalogHeader
+	for line := range strings.SplitSeq(text, "\n") {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		result = append(result, CatalogHeader{Name: strings.TrimSpace(name), Value: strings.TrimSpace(value)})
+	}
+	return result
+}
+
+// ParseCatalog reads a PO or POT file, or an MO file recognised by its magic
+// number.
+func ParseCatalog(data []byte) (*Catalog, error) {
+	if len(data) >= 4 && (binary.LittleEndian.Uint32(data) == moMagic || binary.BigEndian.Uint32(data) == moMagic) {
+		return ParseMO(data)
+	}
+	return ParsePO(bytes.NewReader(data))
+}
+
+// poParser reads a PO file line by line, collecting the keywords of the
+// current entry until the next entry starts.
+type poParser struct {
+	catalog *Catalog
+	entry   CatalogEntry
+	// field points at the string continuation lines are appended to.
+	field   *string
+	started bool
+	hasID   bool
+	lineNo  int
+}
+
+// ParsePO reads a PO or POT file. The header entry is moved to the headers
+// of the catalog.
+func ParsePO(r io.Reader) (*Catalog, error) {
+	p := poParser{catalog: &Catalog{}}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		p.lineNo++
+		if p.lineNo > maxCatalogLines {
+			return nil, fmt.Errorf("%w: more than %d lines", ErrInvalidCatalog, maxCatalogLines)
+		}
+		if err := p.parseLine(strings.TrimSpace(scanner.Text())); err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCatalog, err)
+	}
+
+	if err := p.finishEntry(); err != nil {
+		return nil, err
+	}
+
+	return p.catalog, nil
+}
+
+func (p *poParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: line %d: %s", ErrInvalidCatalog, p.lineNo, fmt.Sprintf(format, args...))
+}
+
+func (p *poParser) parseLine(line string) error {
+	if line == "" {
+		return p.finishEntry()
+	}
+
+	obsolete := false
+	if rest, ok := strings.CutPrefix(line, "#~"); ok {
+		obsolete = true
+		line = strings.TrimSpace(rest)
+	}
+
+	if strings.HasPrefix(line, "#") {
+		return p.parseComment(line)
+	}
+
+	if strings.HasPrefix(line, `"`) {
+		if p.field == nil {
+			return p.errorf("string without keyword")
+		}
+		text, err := p.unquote(line)
+		if err != nil {
+			return err
+		}
+		*p.field += text
+		return nil
+	}
+
+	keyword, value, _ := strings.Cut(line, " ")
+	if !strings.HasPrefix(keyword, "msg") {
+		return p.errorf("unknown keyword %q", keyword)
+	}
+	text, err := p.unquote(strings.TrimSpace(value))
+	if err != nil {
+		return err
+	}
+
+	// A msgctxt or msgid following a msgstr starts the next entry.
+	if (keyword == "msgctxt" || keyword == "msgid") && len(p.entry.Strings) > 0 {
+		if err := p.finishEntry(); err != nil {
+			return err
+		}
+	}
+	p.started = true
+	p.entry.Obsolete = p.entry.Obsolete || obsolete
+
+	switch {
+	case keyword == "msgctxt":
+		p.entry.Context = &text
+		p.field = p.entry.Context
+	case keyword == "msgid":
+		if p.hasID {
+			return p.errorf("msgid %q without msgstr", p.entry.ID)
+		}
+		p.hasID = true
+		p.entry.ID = text
+		p.field = &p.entry.ID
+	case keyword == "msgid_plural":
+		p.entry.IDPlural = &text
+		p.field = p.entry.IDPlural
+	case !p.hasID && strings.HasPrefix(keyword, "msgstr"):
+		return p.errorf("%s without msgid", keyword)
+	case keyword == "msgstr":
+		if p.entry.IDPlural != nil {
+			return p.errorf("plural entry with msgstr instead of msgstr[0]")
+		}
+		p.entry.Strings = append(p.entry.Strings, text)
+		p.field = &p.entry.Strings[len(p.entry.Strings)-1]
+	case strings.HasPrefix(keyword, "msgstr[") && strings.HasSuffix(keyword, "]"):
+		index, err := strconv.Atoi(keyword[len("msgstr[") : len(keyword)-1])
+		if err != nil || index != len(p.entry.Strings) {
+			return p.errorf("unexpected %s", keyword)
+		}
+		if p.entry.IDPlural == nil {
+			return p.errorf("%s without msgid_plural", keyword)
+		}
+		p.entry.Strings = append(p.entry.Strings, text)
+		p.field = &p.entry.Strings[len(p.entry.Strings)-1]
+	default:
+		return p.errorf("unknown keyword %q", keyword)
+	}
+
+	return nil
+}
+
+func (p *poParser) parseComment(line string) error {
+	// Comments before a msgctxt or msgid belong to the next entry.
+	if len(p.entry.Strings) > 0 {
+		if err := p.finishEntry(); err != nil {
+			return err
+		}
+	}
+	p.field = nil
+
+	switch {
+	case strings.HasPrefix(line, "#."):
+		p.entry.ExtractedComments = append(p.entry.ExtractedComments, strings.TrimSpace(line[2:]))
+	case strings.HasPrefix(line, "#:"):
+		p.entry.References = append(p.entry.References, strings.Fields(line[2:])...)
+	case strings.HasPrefix(line, "#,"):
+		for flag := range strings.SplitSeq(line[2:], ",") {
+			if flag = strings.TrimSpace(flag); flag != "" {
+				p.entry.Flags = append(p.entry.Flags, flag)
+			}
+		}
+	case strings.HasPrefix(line, "#|"):
+		// Previous msgid of a fuzzy entry, not kept.
+	default:
+		comment := strings.TrimPrefix(line[1:], " ")
+		p.entry.TranslatorComments = append(p.entry.TranslatorComments, comment)
+	}
+
+	return nil
+}
+
+func (p *poParser) finishEntry() error {
+	defer func() {
+		p.entry = CatalogEntry{}
+		p.field = nil
+		p.started = false
+		p.hasID = false
+	}()
+
+	if !p.started {
+		return nil
+	}
+	if len(p.entry.Strings) == 0 {
+		return p.errorf("entry %q without msgstr", p.entry.ID)
+	}
+
+	if p.entry.ID == "" && p.entry.Context == nil && !p.entry.Obsolete {
+		if len(p.catalog.Headers) > 0 || len(p.catalog.Entries) > 0 {
+			return p.errorf("header entry after the first entry")
+		}
+		p.catalog.Headers = parseHeaders(p.entry.Strings[0])
+		return nil
+	}
+
+	p.catalog.Entries = append(p.catalog.Entries, p.entry)
+	return nil
+}
+
+// unquote decodes a C string literal as used by PO files.
+func (p *poParser) unquote(value string) (string, error) {
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return "", p.errorf("expected a quoted string, got %q", value)
+	}
+	value = value[1 : len(value)-1]
+
+	var result strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c == '"' {
+			return "", p.errorf("unescaped quote")
+		}
+		if c != '\\' {
+			result.WriteByte(c)
+			continue
+		}
+
+		i++
+		if i == len(value) {
+			return "", p.errorf("string ends with a backslash")
+		}
+
+		switch value[i] {
+		case 'n':
+			result.WriteByte('\n')
+		case 't':
+			result.WriteByte('\t')
+		case 'r':
+			result.WriteByte('\r')
+		case 'a':
+			result.WriteByte('\a')
+		case 'b':
+			result.WriteByte('\b')
+		case 'f':
+			result.WriteByte('\f')
+		case 'v':
+			result.WriteByte('\v')
+		case '\\', '"', '\'', '?':
+			result.WriteByte(value[i])
+		case 'x':
+			end := i + 1
+			for end < len(value) && end < i+3 && strings.ContainsRune("0123456789abcdefABCDEF", rune(value[end])) {
+				end++
+			}
+			code, err := strconv.ParseUint(value[i+1:end], 16, 8)
+			if err != nil {
+				return "", p.errorf("invalid hex escape")
+			}
+			result.WriteByte(byte(code))
+			i = end - 1
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			end := i
+			for end < len(value) && end < i+3 && value[end] >= '0' && value[end] <= '7' {
+				end++
+			}
+			code, err := strconv.ParseUint(value[i:end], 8, 8)
+			if err != nil {
+				return "", p.errorf("invalid octal escape")
+			}
+			result.WriteByte(byte(code))
+			i = end - 1
+		default:
+			return "", p.errorf("unknown escape \\%c", value[i])
+		}
+	}
+
+	return result.String(), nil
+}
+
+// WritePO writes the catalog as a PO file, or as a POT file if no entry is
+// translated.
+func WritePO(w io.Writer, catalog *Catalog) error {
+	out := bufio.NewWriter(w)
+
+	if len(catalog.Headers) > 0 {
+		writePOString(out, "", "msgid", "")
+		writePOString(out, "", "msgstr", catalog.headerText())
+	}
+
+	for i, entry := range catalog.Entries {
+		// Entries are separated by blank lines.
+		if i > 0 || len(catalog.Headers) > 0 {
+			out.WriteString("\n")
+		}
+
+		prefix := ""
+		if entry.Obsolete {
+			prefix = "#~ "
+		}
+
+		for _, comment := range entry.TranslatorComments {
+			out.WriteString(strings.TrimRight("# "+comment, " ") + "\n")
+		}
+		for _, comment := range entry.ExtractedComments {
+			out.WriteString("#. " + comment + "\n")
+		}
+		if len(entry.References) > 0 {
+			out.WriteString("#: " + strings.Join(entry.References, " ") + "\n")
+		}
+		if len(entry.Flags) > 0 {
+			out.WriteString("#, " + strings.Join(entry.Flags, ", ") + "\n")
+		}
+
+		if entry.Context != nil {
+			writePOString(out, prefix, "msgctxt", *entry.Context)
+		}
+		writePOString(out, prefix, "msgid", entry.ID)
+		if entry.IDPlural != nil {
+			writePOString(out, prefix, "msgid_plural", *entry.IDPlural)
+			for i, text := range entry.Strings {
+				writePOString(out, prefix, "msgstr["+strconv.Itoa(i)+"]", text)
+			}
+		} else {
+			text := ""
+			if len(entry.Strings) > 0 {
+				text = entry.Strings[0]
+			}
+			writePOString(out, prefix, "msgstr", text)
+		}
+	}
+
+	return out.Flush()
+}
+
+// writePOString writes a keyword with its string, splitting multi-line
+// strings after each newline as gettext tools do.
+func writePOString(out *bufio.Writer, prefix, keyword, text string) {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) <= 1 {
+		out.WriteString(prefix + keyword + " " + quotePO(text) + "\n")
+		return
+	}
+
+	out.WriteString(prefix + keyword + ` ""` + "\n")
+	for _, line := range lines {
+		out.WriteString(prefix + quotePO(line) + "\n")
+	}
+}
+
+func quotePO(text string) string {
+	var result strings.Builder
+	result.WriteByte('"')
+	for _, r := range text {
+		switch r {
+		case '\n':
+			result.WriteString(`\n`)
+		case '\t':
+			result.WriteString(`\t`)
+		case '\r':
+			result.WriteString(`\r`)
+		case '"':
+			result.WriteString(`\"`)
+		case '\\':
+			result.WriteString(`\\`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&result, `\%03o`, r)
+			} else {
+				result.WriteRune(r)
+			}
+		}
+	}
+	result.WriteByte('"')
+	return result.String()
+}
+
+// ParseMO reads a compiled MO file in either byte order.
+func ParseMO(data []byte) (*Catalog, error) {
+	if len(data) < moHeaderSize {
+		return nil, fmt.Errorf("%w: truncated MO header", ErrInvalidCatalog)
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+	if binary.LittleEndian.Uint32(data) != moMagic {
+		order = binary.BigEndian
+		if order.Uint32(data) != moMagic {
+			return nil, fmt.Errorf("%w: not an MO file", ErrInvalidCatalog)
+		}
+	}
+	if revision := order.Uint32(data[4:]) >> 16; revision > 1 {
+		return nil, fmt.Errorf("%w: unsupported MO revision %d", ErrInvalidCatalog, revision)
+	}
+
+	count := int(order.Uint32(data[8:]))
+	originals := int(order.Uint32(data[12:]))
+	translations := int(order.Uint32(data[16:]))
+
+	if count < 0 || count > (len(data)-moHeaderSize)/16 {
+		return nil, fmt.Errorf("%w: invalid MO string count", ErrInvalidCatalog)
+	}
+
+	readString := func(table, i int) (string, error) {
+		position := table + 8*i
+		if position < 0 || position+8 > len(data) {
+			return "", fmt.Errorf("%w: MO string table out of range", ErrInvalidCatalog)
+		}
+		length := int(order.Uint32(data[position:]))
+		offset := int(order.Uint32(data[position+4:]))
+		if offset < 0 || length < 0 || offset+length > len(data) {
+			return "", fmt.Errorf("%w: MO string out of range", ErrInvalidCatalog)
+		}
+		return string(data[offset : offset+length]), nil
+	}
+
+	catalog := &Catalog{}
+	for i := range count {
+		original, err := readString(originals, i)
+		if err != nil {
+			return nil, err
+		}
+		translation, err := readString(translations, i)
+		if err != nil {
+			return nil, err
+		}
+
+		if original == "" {
+			catalog.Headers = parseHeaders(translation)
+			continue
+		}
+
+		entry := CatalogEntry{}
+		if context, id, ok := strings.Cut(original, GettextContextSeparator); ok {
+			entry.Context = &context
+			original = id
+		}
+		if id, plural, ok := strings.Cut(original, "\x00"); ok {
+			entry.ID = id
+			entry.IDPlural = &plural
+			entry.Strings = strings.Split(translation, "\x00")
+		} else {
+			entry.ID = original
+			entry.Strings = []string{translation}
+		}
+		catalog.Entries = append(catalog.Entries, entry)
+	}
+
+	return catalog, nil
+}
+
+// WriteMO writes the catalog as a little-endian MO file without hash table.
+// Like msgfmt, it leaves out fuzzy, obsolete and untranslated entries.
+func WriteMO(w io.Writer, catalog *Catalog) error {
+	type message struct {
+		original    string
+		translation string
+	}
+
+	var messages []message
+	if len(catalog.Headers) > 0 {
+		messages = append(messages, message{translation: catalog.headerText()})
+	}
+	for _, entry := range catalog.Entries {
+		if entry.Fuzzy() || entry.Obsolete || !entry.Translated() {
+			continue
+		}
+
+		original := entry.ID
+		if entry.IDPlural != nil {
+			original += "\x00" + *entry.IDPlural
+		}
+		if entry.Context != nil {
+			original = *entry.Context + GettextContextSeparator + original
+		}
+		messages = append(messages, message{original: original, translation: strings.Join(entry.Strings, "\x00")})
+	}
+
+	// Readers binary search the originals.
+	slices.SortFunc(messages, func(a, b message) int {
+		return strings.Compare(a.original, b.original)
+	})
+
+	count := uint32(len(messages))
+	originals := uint32(moHeaderSize)
+	translations := originals + 8*count
+	offset := translations + 8*count
+
+	var header, tables, strs bytes.Buffer
+	for _, value := range []uint32{moMagic, 0, count, originals, translations, 0, offset} {
+		_ = binary.Write(&header, binary.LittleEndian, value)
+	}
+
+	var originalTable, translationTable bytes.Buffer
+	for _, m := range messages {
+		_ = binary.Write(&originalTable, binary.LittleEndian, []uint32{uint32(len(m.original)), offset + uint32(strs.Len())})
+		strs.WriteString(m.original + "\x00")
+	}
+	for _, m := range messages {
+		_ = binary.Write(&translationTable, binary.LittleEndian, []uint32{uint32(len(m.translation)), offset + uint32(strs.Len())})
+		strs.WriteString(m.translation + "\x00")
+	}
+	tables.Write(originalTable.Bytes())
+	tables.Write(translationTable.Bytes())
+
+	for _, part := range [][]byte{header.Bytes(), tables.Bytes(), strs.Bytes()} {
+		if _, err := w.Write(part); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,388 @@
+package translation
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// pluralFormsHeader matches the Plural-Forms header of a catalog, e.g.
+// "nplurals=2; plural=(n != 1);".
+var pluralFormsHeader = regexp.MustCompile(`^\s*nplurals\s*=\s*(\d+)\s*;\s*plural\s*=\s*(.+?)\s*;?\s*$`)
+
+// gettextPluralForms holds the customary Plural-Forms of the languages whose
+// CLDR rules differ from "nplurals=2; plural=(n != 1);", by language or
+// language tag. Integer counts select the same CLDR category through the
+// expression as through the CLDR rules.
+var gettextPluralForms = map[string]string{
+	"ar":    "nplurals=6; plural=(n==0 ? 0 : n==1 ? 1 : n==2 ? 2 : n%100>=3 && n%100<=10 ? 3 : n%100>=11 ? 4 : 5);",
+	"be":    "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<12 || n%100>14) ? 1 : 2);",
+	"cs":    "nplurals=3; plural=(n==1 ? 0 : n>=2 && n<=4 ? 1 : 2);",
+	"cy":    "nplurals=6; plural=(n==0 ? 0 : n==1 ? 1 : n==2 ? 2 : n==3 ? 3 : n==6 ? 4 : 5);",
+	"fr":    "nplurals=2; plural=(n > 1);",
+	"ga":    "nplurals=5; plural=(n==1 ? 0 : n==2 ? 1 : n>=3 && n<=6 ? 2 : n>=7 && n<=10 ? 3 : 4);",
+	"hr":    "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<12 || n%100>14) ? 1 : 2);",
+	"id":    "nplurals=1; plural=0;",
+	"ja":    "nplurals=1; plural=0;",
+	"ko":    "nplurals=1; plural=0;",
+	"lt":    "nplurals=3; plural=(n%10==1 && (n%100<11 || n%100>19) ? 0 : n%10>=2 && n%10<=9 && (n%100<11 || n%100>19) ? 1 : 2);",
+	"lv":    "nplurals=3; plural=(n%10==0 || (n%100>=11 && n%100<=19) ? 0 : n%10==1 && n%100!=11 ? 1 : 2);",
+	"pl":    "nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<12 || n%100>14) ? 1 : 2);",
+	"pt":    "nplurals=2; plural=(n > 1);",
+	"pt-PT": "nplurals=2; plural=(n != 1);",
+	"ro":    "nplurals=3; plural=(n==1 ? 0 : n==0 || (n%100>=2 && n%100<=19) ? 1 : 2);",
+	"ru":    "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<12 || n%100>14) ? 1 : 2);",
+	"sk":    "nplurals=3; plural=(n==1 ? 0 : n>=2 && n<=4 ? 1 : 2);",
+	"sl":    "nplurals=4; plural=(n%100==1 ? 0 : n%100==2 ? 1 : n%100==3 || n%100==4 ? 2 : 3);",
+	"sr":    "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<12 || n%100>14) ? 1 : 2);",
+	"th":    "nplurals=1; plural=0;",
+	"uk":    "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<12 || n%100>14) ? 1 : 2);",
+	"vi":    "nplurals=1; plural=0;",
+	"zh":    "nplurals=1; plural=0;",
+}
+
+const defaultGettextPluralForms = "nplurals=2; plural=(n != 1);"
+
+// GettextPluralForms returns the Plural-Forms header value for locale.
+func GettextPluralForms(locale Locale) string {
+	tag := locale.LanguageTag()
+	if forms, ok := gettextPluralForms[tag]; ok {
+		return forms
+	}
+
+	base, _, _ := strings.Cut(tag, "-")
+	if forms, ok := gettextPluralForms[base]; ok {
+		return forms
+	}
+
+	return defaultGettextPluralForms
+}
+
+// gettextPlurals maps the msgstr indexes of a Plural-Forms expression to CLDR
+// plural categories of a locale.
+type gettextPlurals struct {
+	count int
+	// categories holds the CLDR category served by each msgstr index.
+	categories []PluralCategory
+	// indexes holds the msgstr index serving each CLDR category.
+	indexes map[PluralCategory]int
+}
+
+// newGettextPlurals evaluates the Plural-Forms header for integer counts and
+// pairs each msgstr index with the CLDR category most of its counts have.
+// Categories only reached by fractions, e.g. "other" in Russian, are served
+// by the last msgstr.
+func newGettextPlurals(header string, locale Locale) (*gettextPlurals, error) {
+	match := pluralFormsHeader.FindStringSubmatch(header)
+	if match == nil {
+		return nil, fmt.Errorf("%w: invalid Plural-Forms %q", ErrInvalidCatalog, header)
+	}
+
+	count, err := strconv.Atoi(match[1])
+	if err != nil || count < 1 || count > 6 {
+		return nil, fmt.Errorf("%w: invalid nplurals in %q", ErrInvalidCatalog, header)
+	}
+
+	expression, err := parsePluralExpression(match[2])
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := language.Parse(locale.LanguageTag())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidLocale, locale)
+	}
+
+	result := &gettextPlurals{
+		count:      count,
+		categories: make([]PluralCategory, count),
+		indexes:    map[PluralCategory]int{},
+	}
+
+	// Counts of the samples by msgstr index and CLDR category.
+	samples := make([]map[PluralCategory]int, count)
+	for index := range samples {
+		samples[index] = map[PluralCategory]int{}
+	}
+
+	for n := 0; n <= 1000; n++ {
+		index := expression(n)
+		if index < 0 || index >= count {
+			return nil, fmt.Errorf("%w: Plural-Forms %q selects msgstr[%d] for %d", ErrInvalidCatalog, header, index, n)
+		}
+		samples[index][pluralCategories[plural.Cardinal.MatchPlural(tag, n, 0, 0, 0, 0)]]++
+	}
+
+	for index, categories := range samples {
+		for _, category := range pluralCategoryOrder {
+			if categories[category] > categories[result.categories[index]] {
+				result.categories[index] = category
+			}
+		}
+		if result.categories[index] == "" {
+			return nil, fmt.Errorf("%w: Plural-Forms %q never selects msgstr[%d]", ErrInvalidCatalog, header, index)
+		}
+		if _, ok := result.indexes[result.categories[index]]; !ok {
+			result.indexes[result.categories[index]] = index
+		}
+	}
+
+	categories, err := PluralCategories(locale)
+	if err != nil {
+		return nil, err
+	}
+	for _, category := range categories {
+		if _, ok := result.indexes[category]; !ok {
+			result.indexes[category] = count - 1
+		}
+	}
+
+	return result, nil
+}
+
+// pluralForms maps the msgstr of an entry onto the categories of the locale.
+func (g *gettextPlurals) pluralForms(strs []string) (PluralForms, error) {
+	if len(strs) != g.count {
+		return nil, fmt.Errorf("%d msgstr instead of %d", len(strs), g.count)
+	}
+
+	result := PluralForms{}
+	for category, index := range g.indexes {
+		result[category] = strs[index]
+	}
+	return result, nil
+}
+
+// msgstrs maps plural forms onto msgstr indexes, falling back to the
+// PluralOther form for categories the forms lack.
+func (g *gettextPlurals) msgstrs(forms PluralForms) []string {
+	result := make([]string, g.count)
+	for index, category := range g.categories {
+		text, ok := forms[category]
+		if !ok {
+			text = forms[PluralOther]
+		}
+		result[index] = text
+	}
+	return result
+}
+
+// parsePluralExpression compiles the C expression of a Plural-Forms header,
+// which computes a msgstr index from the count n.
+func parsePluralExpression(source string) (func(n int) int, error) {
+	p := pluralExpressionParser{source: source}
+	p.tokenize()
+
+	expression, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	if p.position < len(p.tokens) {
+		return nil, p.errorf("unexpected %q", p.tokens[p.position])
+	}
+
+	return expression, nil
+}
+
+type pluralExpressionParser struct {
+	source   string
+	tokens   []string
+	position int
+}
+
+// binaryOperators lists the operators of Plural-Forms expressions by
+// precedence, loosest first.
+var binaryOperators = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *pluralExpressionParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: plural expression %q: %s", ErrInvalidCatalog, p.source, fmt.Sprintf(format, args...))
+}
+
+func (p *pluralExpressionParser) tokenize() {
+	for i := 0; i < len(p.source); {
+		c := p.source[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(p.source) && p.source[i] >= '0' && p.source[i] <= '9' {
+				i++
+			}
+			p.tokens = append(p.tokens, p.source[start:i])
+		case i+1 < len(p.source) && slices.Contains([]string{"||", "&&", "==", "!=", "<=", ">="}, p.source[i:i+2]):
+			p.tokens = append(p.tokens, p.source[i:i+2])
+			i += 2
+		default:
+			p.tokens = append(p.tokens, string(c))
+			i++
+		}
+	}
+}
+
+func (p *pluralExpressionParser) peek() string {
+	if p.position < len(p.tokens) {
+		return p.tokens[p.position]
+	}
+	return ""
+}
+
+func (p *pluralExpressionParser) parseTernary() (func(int) int, error) {
+	condition, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() != "?" {
+		return condition, nil
+	}
+	p.position++
+
+	then, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() != ":" {
+		return nil, p.errorf("expected ':'")
+	}
+	p.position++
+
+	otherwise, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(n int) int {
+		if condition(n) != 0 {
+			return then(n)
+		}
+		return otherwise(n)
+	}, nil
+}
+
+func (p *pluralExpressionParser) parseBinary(level int) (func(int) int, error) {
+	if level == len(binaryOperators) {
+		return p.parseUnary()
+	}
+
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for slices.Contains(binaryOperators[level], p.peek()) {
+		operator := p.peek()
+		p.position++
+
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		left = binaryOperation(operator, left, right)
+	}
+
+	return left, nil
+}
+
+func binaryOperation(operator string, left, right func(int) int) func(int) int {
+	boolean := func(value bool) int {
+		if value {
+			return 1
+		}
+		return 0
+	}
+
+	return func(n int) int {
+		a := left(n)
+		switch operator {
+		case "||":
+			return boolean(a != 0 || right(n) != 0)
+		case "&&":
+			return boolean(a != 0 && right(n) != 0)
+		}
+
+		b := right(n)
+		switch operator {
+		case "==":
+			return boolean(a == b)
+		case "!=":
+			return boolean(a != b)
+		case "<":
+			return boolean(a < b)
+		case "<=":
+			return boolean(a <= b)
+		case ">":
+			return boolean(a > b)
+		case ">=":
+			return boolean(a >= b)
+		case "+":
+			return a + b
+		case "-":
+			return a - b
+		case "*":
+			return a * b
+		case "/", "%":
+			if b == 0 {
+				return 0
+			}
+			if operator == "/" {
+				return a / b
+			}
+			return a % b
+		}
+		return 0
+	}
+}
+
+func (p *pluralExpressionParser) parseUnary() (func(int) int, error) {
+	token := p.peek()
+	p.position++
+
+	switch {
+	case token == "n":
+		return func(n int) int { return n }, nil
+	case token == "!":
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(n int) int {
+			if operand(n) == 0 {
+				return 1
+			}
+			return 0
+		}, nil
+	case token == "(":
+		inner, err := p.parseTernary()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, p.errorf("expected ')'")
+		}
+		p.position++
+		return inner, nil
+	case token != "" && token[0] >= '0' && token[0] <= '9':
+		value, err := strconv.Atoi(token)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", token)
+		}
+		return func(int) int { return value }, nil
+	case token == "":
+		return nil, p.errorf("unexpected end")
+	default:
+		return nil, p.errorf("unexpected %q", token)
+	}
+}
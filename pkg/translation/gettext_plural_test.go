@@ -0,0 +1,203 @@
+package translation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePluralExpression(t *testing.T) {
+	testCases := map[string]struct {
+		source   string
+		expected map[int]int
+	}{
+		"constant": {
+			source:   "0",
+			expected: map[int]int{0: 0, 1: 0, 5: 0},
+		},
+		"comparison": {
+			source:   "(n != 1)",
+			expected: map[int]int{0: 1, 1: 0, 2: 1},
+		},
+		"without parentheses or spaces": {
+			source:   "n>1",
+			expected: map[int]int{0: 0, 1: 0, 2: 1},
+		},
+		"chained ternary is right associative": {
+			source:   "n==1 ? 0 : n==2 ? 1 : 2",
+			expected: map[int]int{1: 0, 2: 1, 3: 2},
+		},
+		"nested ternary in the then branch": {
+			source:   "n<10 ? n<5 ? 0 : 1 : 2",
+			expected: map[int]int{4: 0, 5: 1, 10: 2},
+		},
+		"and binds tighter than or": {
+			source:   "n==1 || n==2 && n==3",
+			expected: map[int]int{1: 1, 2: 0, 3: 0},
+		},
+		"comparison binds tighter than equality": {
+			source:   "n > 1 == 1",
+			expected: map[int]int{1: 0, 2: 1},
+		},
+		"multiplication binds tighter than addition": {
+			source:   "1 + n * 2 - 3",
+			expected: map[int]int{0: -2, 2: 2},
+		},
+		"subtraction is left associative": {
+			source:   "10 - n - 1",
+			expected: map[int]int{2: 7},
+		},
+		"negation": {
+			source:   "!(n % 10)",
+			expected: map[int]int{10: 1, 11: 0},
+		},
+		"division and modulo by zero": {
+			source:   "n / 0 + n % 0",
+			expected: map[int]int{5: 0},
+		},
+		"tabs": {
+			source:   "n\t!=\t1",
+			expected: map[int]int{1: 0, 2: 1},
+		},
+		"russian": {
+			source:   "(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<12 || n%100>14) ? 1 : 2)",
+			expected: map[int]int{1: 0, 11: 2, 21: 0, 3: 1, 13: 2, 24: 1, 5: 2, 111: 2, 122: 1},
+		},
+		"arabic": {
+			source:   "(n==0 ? 0 : n==1 ? 1 : n==2 ? 2 : n%100>=3 && n%100<=10 ? 3 : n%100>=11 ? 4 : 5)",
+			expected: map[int]int{0: 0, 1: 1, 2: 2, 3: 3, 110: 3, 11: 4, 99: 4, 100: 5, 102: 5},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			expression, err := parsePluralExpression(tc.source)
+			require.NoError(t, err)
+
+			for n, expected := range tc.expected {
+				assert.Equal(t, expected, expression(n), "n = %d", n)
+			}
+		})
+	}
+}
+
+func TestParsePluralExpressionErrors(t *testing.T) {
+	testCases := map[string]string{
+		"empty":                "",
+		"missing operand":      "n ==",
+		"missing else branch":  "n == 1 ? 0",
+		"missing then branch":  "n == 1 ? : 1",
+		"unclosed parenthesis": "(n != 1",
+		"unopened parenthesis": "n != 1)",
+		"adjacent operands":    "n 1",
+		"adjacent operators":   "n + * 1",
+		"unknown variable":     "x != 1",
+		"assignment":           "n = 1",
+		"number out of range":  "99999999999999999999",
+	}
+
+	for name, source := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := parsePluralExpression(source)
+			assert.ErrorIs(t, err, ErrInvalidCatalog)
+		})
+	}
+}
+
+func TestNewGettextPlurals(t *testing.T) {
+	testCases := map[string]struct {
+		header     string
+		locale     Locale
+		expected   []PluralCategory
+		invalid    bool
+		strs       []string
+		forms      PluralForms
+		formsError bool
+	}{
+		"english": {
+			header:   "nplurals=2; plural=(n != 1);",
+			locale:   "en_GB",
+			expected: []PluralCategory{PluralOne, PluralOther},
+			strs:     []string{"# item", "# items"},
+			forms:    PluralForms{PluralOne: "# item", PluralOther: "# items"},
+		},
+		"without trailing semicolon and with spaces": {
+			header:   " nplurals = 2 ; plural = n>1 ",
+			locale:   "fr_FR",
+			expected: []PluralCategory{PluralOne, PluralOther},
+		},
+		"russian": {
+			header:   GettextPluralForms("ru_RU"),
+			locale:   "ru_RU",
+			expected: []PluralCategory{PluralOne, PluralFew, PluralMany},
+			strs:     []string{"# файл", "# файла", "# файлов"},
+			forms:    PluralForms{PluralOne: "# файл", PluralFew: "# файла", PluralMany: "# файлов", PluralOther: "# файлов"},
+		},
+		"single form": {
+			header:   "nplurals=1; plural=0;",
+			locale:   "ja_JP",
+			expected: []PluralCategory{PluralOther},
+		},
+		"wrong number of msgstrs": {
+			header:     "nplurals=2; plural=(n != 1);",
+			locale:     "en_GB",
+			expected:   []PluralCategory{PluralOne, PluralOther},
+			strs:       []string{"# items"},
+			formsError: true,
+		},
+		"missing plural": {
+			header:  "nplurals=2;",
+			locale:  "en_GB",
+			invalid: true,
+		},
+		"zero nplurals": {
+			header:  "nplurals=0; plural=0;",
+			locale:  "en_GB",
+			invalid: true,
+		},
+		"too many nplurals": {
+			header:  "nplurals=7; plural=n;",
+			locale:  "en_GB",
+			invalid: true,
+		},
+		"index out of range": {
+			header:  "nplurals=2; plural=n;",
+			locale:  "en_GB",
+			invalid: true,
+		},
+		"msgstr never selected": {
+			header:  "nplurals=3; plural=(n != 1);",
+			locale:  "en_GB",
+			invalid: true,
+		},
+		"invalid expression": {
+			header:  "nplurals=2; plural=(n != 1;",
+			locale:  "en_GB",
+			invalid: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			plurals, err := newGettextPlurals(tc.header, tc.locale)
+			if tc.invalid {
+				assert.ErrorIs(t, err, ErrInvalidCatalog)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, plurals.categories)
+
+			if tc.strs == nil {
+				return
+			}
+			forms, err := plurals.pluralForms(tc.strs)
+			if tc.formsError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.forms, forms)
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package translation
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePO(t *testing.T) {
+	testCases := map[string]struct {
+		po              string
+		expectedHeaders []CatalogHeader
+		expectedEntries []CatalogEntry
+	}{
+		"header and entry": {
+			po: `msgid ""
+msgstr ""
+"Language: de_DE\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "Hello"
+msgstr "Hallo"
+`,
+			expectedHeaders: []CatalogHeader{{Name: "Language", Value: "de_DE"}, {Name: "Plural-Forms", Value: "nplurals=2; plural=(n != 1);"}},
+			expectedEntries: []CatalogEntry{{ID: "Hello", Strings: []string{"Hallo"}}},
+		},
+		"escape sequences": {
+			po: `msgid "line\nnext\ttab\r\\ \"quoted\" \'single\' \?"
+msgstr "\a\b\f\v \x41\x4a2 \101\0z"
+`,
+			expectedEntries: []CatalogEntry{{ID: "line\nnext\ttab\r\\ \"quoted\" 'single' ?", Strings: []string{"\a\b\f\v AJ2 A\x00z"}}},
+		},
+		"continuation lines": {
+			po: `msgid ""
+"first "
+"second"
+msgstr ""
+"erste "
+"zweite"
+`,
+			expectedEntries: []CatalogEntry{{ID: "first second", Strings: []string{"erste zweite"}}},
+		},
+		"context, plural and comments": {
+			po: `# translator comment
+#. extracted comment
+#: src/cart.go:12 src/cart.go:40
+#, fuzzy, c-format
+#| msgid "previous"
+msgctxt "cart"
+msgid "item"
+msgid_plural "items"
+msgstr[0] "Artikel"
+msgstr[1] "Artikel"
+`,
+			expectedEntries: []CatalogEntry{{
+				Context:            ptr("cart"),
+				ID:                 "item",
+				IDPlural:           ptr("items"),
+				Strings:            []string{"Artikel", "Artikel"},
+				TranslatorComments: []string{"translator comment"},
+				ExtractedComments:  []string{"extracted comment"},
+				References:         []string{"src/cart.go:12", "src/cart.go:40"},
+				Flags:              []string{"fuzzy", "c-format"},
+			}},
+		},
+		"entries without blank line in between": {
+			po: `msgid "a"
+msgstr "A"
+msgid "b"
+msgstr "B"
+# comment of c
+msgid "c"
+msgstr "C"
+`,
+			expectedEntries: []CatalogEntry{
+				{ID: "a", Strings: []string{"A"}},
+				{ID: "b", Strings: []string{"B"}},
+				{ID: "c", Strings: []string{"C"}, TranslatorComments: []string{"comment of c"}},
+			},
+		},
+		"obsolete entry": {
+			po: `#~ msgid "old"
+#~ msgstr "alt"
+`,
+			expectedEntries: []CatalogEntry{{ID: "old", Strings: []string{"alt"}, Obsolete: true}},
+		},
+		"indented lines and CRLF": {
+			po:              "  msgid \"a\"\r\n\tmsgstr \"A\"\r\n",
+			expectedEntries: []CatalogEntry{{ID: "a", Strings: []string{"A"}}},
+		},
+		"empty file": {
+			po: "",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			catalog, err := ParsePO(strings.NewReader(tc.po))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedHeaders, catalog.Headers)
+			assert.Equal(t, tc.expectedEntries, catalog.Entries)
+		})
+	}
+}
+
+func TestParsePOErrors(t *testing.T) {
+	testCases := map[string]string{
+		"unknown escape":              `msgid "\q"` + "\nmsgstr \"\"\n",
+		"trailing backslash":          `msgid "a\"` + "\nmsgstr \"\"\n",
+		"hex escape without digits":   `msgid "\xg"` + "\nmsgstr \"\"\n",
+		"unescaped quote":             `msgid "a"b"` + "\nmsgstr \"\"\n",
+		"unquoted string":             "msgid a\nmsgstr \"\"\n",
+		"unclosed string":             "msgid \"a\nmsgstr \"\"\n",
+		"string without keyword":      "\"a\"\n",
+		"unknown keyword":             "msgfoo \"a\"\n",
+		"no keyword":                  "hello\n",
+		"msgstr without msgid":        "msgstr \"a\"\n",
+		"msgid without msgstr":        "msgid \"a\"\n\nmsgid \"b\"\nmsgstr \"B\"\n",
+		"two msgids":                  "msgid \"a\"\nmsgid \"b\"\nmsgstr \"B\"\n",
+		"plural with plain msgstr":    "msgid \"a\"\nmsgid_plural \"as\"\nmsgstr \"A\"\n",
+		"msgstr index out of order":   "msgid \"a\"\nmsgid_plural \"as\"\nmsgstr[1] \"A\"\n",
+		"msgstr index without plural": "msgid \"a\"\nmsgstr[0] \"A\"\n",
+		"header after entry":          "msgid \"a\"\nmsgstr \"A\"\n\nmsgid \"\"\nmsgstr \"Language: de\\n\"\n",
+	}
+
+	for name, po := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParsePO(strings.NewReader(po))
+			assert.ErrorIs(t, err, ErrInvalidCatalog)
+		})
+	}
+}
+
+// testMO compiles a catalog with a header, a plain, a plural and a context
+// entry.
+func testMO(t *testing.T) []byte {
+	t.Helper()
+
+	catalog := &Catalog{
+		Headers: []CatalogHeader{{Name: "Language", Value: "de_DE"}},
+		Entries: []CatalogEntry{
+			{ID: "Hello", Strings: []string{"Hallo"}},
+			{ID: "item", IDPlural: ptr("items"), Strings: []string{"# Artikel", "# Artikel"}},
+			{Context: ptr("menu"), ID: "Open", Strings: []string{"Öffnen"}},
+		},
+	}
+
+	var data bytes.Buffer
+	require.NoError(t, WriteMO(&data, catalog))
+	return data.Bytes()
+}
+
+// swapMO converts a little-endian MO file into big-endian byte order.
+func swapMO(data []byte) []byte {
+	result := bytes.Clone(data)
+	count := int(binary.LittleEndian.Uint32(data[8:]))
+	words := moHeaderSize/4 + 2*2*count
+	for i := range words {
+		binary.BigEndian.PutUint32(result[4*i:], binary.LittleEndian.Uint32(data[4*i:]))
+	}
+	return result
+}
+
+func TestParseMO(t *testing.T) {
+	expectedEntries := []CatalogEntry{
+		{ID: "Hello", Strings: []string{"Hallo"}},
+		{ID: "item", IDPlural: ptr("items"), Strings: []string{"# Artikel", "# Artikel"}},
+		{Context: ptr("menu"), ID: "Open", Strings: []string{"Öffnen"}},
+	}
+
+	testCases := map[string]func(t *testing.T) []byte{
+		"little-endian": testMO,
+		"big-endian": func(t *testing.T) []byte {
+			return swapMO(testMO(t))
+		},
+	}
+
+	for name, data := range testCases {
+		t.Run(name, func(t *testing.T) {
+			catalog, err := ParseMO(data(t))
+			require.NoError(t, err)
+
+			assert.Equal(t, []CatalogHeader{{Name: "Language", Value: "de_DE"}}, catalog.Headers)
+			assert.ElementsMatch(t, expectedEntries, catalog.Entries)
+		})
+	}
+}
+
+func TestParseMOErrors(t *testing.T) {
+	valid := testMO(t)
+	count := int(binary.LittleEndian.Uint32(valid[8:]))
+	originals := int(binary.LittleEndian.Uint32(valid[12:]))
+
+	modified := func(modify func(data []byte)) []byte {
+		data := bytes.Clone(valid)
+		modify(data)
+		return data
+	}
+
+	testCases := map[string][]byte{
+		"empty":            {},
+		"truncated header": valid[:moHeaderSize-1],
+		"truncated tables": valid[:originals+8],
+		"truncated string": valid[:len(valid)-2],
+		"wrong magic": modified(func(data []byte) {
+			binary.LittleEndian.PutUint32(data, 0x12345678)
+		}),
+		"unsupported revision": modified(func(data []byte) {
+			binary.LittleEndian.PutUint32(data[4:], 2<<16)
+		}),
+		"string count beyond the file": modified(func(data []byte) {
+			binary.LittleEndian.PutUint32(data[8:], uint32(len(data)))
+		}),
+		"table offset beyond the file": modified(func(data []byte) {
+			binary.LittleEndian.PutUint32(data[16:], uint32(len(data)))
+		}),
+		"string offset beyond the file": modified(func(data []byte) {
+			binary.LittleEndian.PutUint32(data[originals+8+4:], uint32(len(data)))
+		}),
+		"string length beyond the file": modified(func(data []byte) {
+			binary.LittleEndian.PutUint32(data[originals+8*(count-1):], 0xffffffff)
+		}),
+	}
+
+	for name, data := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseMO(data)
+			assert.ErrorIs(t, err, ErrInvalidCatalog)
+		})
+	}
+}
+
+func TestParseCatalogDetectsMO(t *testing.T) {
+	catalog, err := ParseCatalog(testMO(t))
+	require.NoError(t, err)
+	assert.Len(t, catalog.Entries, 3)
+
+	catalog, err = ParseCatalog([]byte("msgid \"a\"\nmsgstr \"A\"\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []CatalogEntry{{ID: "a", Strings: []string{"A"}}}, catalog.Entries)
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
@@ -0,0 +1,86 @@
+package translation
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteAppleStrings writes translations as iOS Localizable.strings file.
+// Pluralised translations are written with their PluralOther form, the
+// stringsdict written by WriteStringsdict takes precedence over them.
+// Placeholders are converted by printfMessages into object arguments, e.g.
+// "{name}" into "%1$@".
+func WriteAppleStrings(w io.Writer, translations []Translation) error {
+	out := bufio.NewWriter(w)
+
+	for i, entity := range translations {
+		if i > 0 {
+			out.WriteString("\n")
+		}
+		if entity.Comment != "" {
+			fmt.Fprintf(out, "/* %s */\n", strings.ReplaceAll(entity.Comment, "*/", "* /"))
+		}
+		fmt.Fprintf(out, "\"%s\" = \"%s\";\n", appleEscape(entity.LanguageKey), appleEscape(printfMessages(entity, "@").text))
+	}
+
+	return out.Flush()
+}
+
+// WriteStringsdict writes the pluralised translations as iOS stringsdict
+// property list. Every entry selects its forms by the integer first argument,
+// placeholders are converted as by WriteAppleStrings.
+func WriteStringsdict(w io.Writer, translations []Translation) error {
+	out := bufio.NewWriter(w)
+
+	out.WriteString(xml.Header)
+	out.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	out.WriteString("<plist version=\"1.0\">\n<dict>\n")
+
+	for _, entity := range translations {
+		if len(entity.PluralForms) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(out, "    <key>%s</key>\n    <dict>\n", xmlEscape(entity.LanguageKey))
+		fmt.Fprintf(out, "        <key>NSStringLocalizedFormatKey</key>\n        <string>%%#@%s@</string>\n", countArgument)
+		fmt.Fprintf(out, "        <key>%s</key>\n        <dict>\n", countArgument)
+		out.WriteString("            <key>NSStringFormatSpecTypeKey</key>\n            <string>NSStringPluralRuleType</string>\n")
+		out.WriteString("            <key>NSStringFormatValueTypeKey</key>\n            <string>d</string>\n")
+		forms := printfMessages(entity, "@").forms
+		for _, category := range forms.Categories() {
+			fmt.Fprintf(out, "            <key>%s</key>\n            <string>%s</string>\n", category, xmlEscape(forms[category]))
+		}
+		out.WriteString("        </dict>\n    </dict>\n")
+	}
+
+	out.WriteString("</dict>\n</plist>\n")
+
+	return out.Flush()
+}
+
+// appleEscape escapes text for a quoted string of a strings file.
+func appleEscape(text string) string {
+	var result strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\':
+			result.WriteString(`\\`)
+		case r == '"':
+			result.WriteString(`\"`)
+		case r == '\n':
+			result.WriteString(`\n`)
+		case r == '\r':
+			result.WriteString(`\r`)
+		case r == '\t':
+			result.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&result, `\U%04x`, r)
+		default:
+			result.WriteRune(r)
+		}
+	}
+	return result.String()
+}
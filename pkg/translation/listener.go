@@ -0,0 +1,159 @@
+package translation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/gorm"
+)
+
+// ChangeChannel is the channel the translation table triggers notify on.
+const ChangeChannel = "translation_changed"
+
+const (
+	OperationInsert = "INSERT"
+	OperationUpdate = "UPDATE"
+	OperationDelete = "DELETE"
+	// OperationNamespace reports a change of the settings of a namespace, such
+	// changes carry neither a locale nor a version.
+	OperationNamespace = "NAMESPACE"
+)
+
+// Change is the payload of a notification on ChangeChannel. Version refers to
+// the TranslationChange recording it.
+type Change struct {
+	Operation   string `json:"operation"`
+	Namespace   string `json:"namespace"`
+	Locale      Locale `json:"locale"`
+	LanguageKey string `json:"language_key"`
+	Version     int64  `json:"version"`
+}
+
+type ChangeHandler interface {
+	TranslationChanged(change Change)
+	// ChangesLost is called whenever notifications may have been missed, i.e.
+	// when the listener (re)connects.
+	ChangesLost()
+}
+
+// ChangeListener holds a dedicated database connection listening on
+// ChangeChannel and dispatches the notifications to its handlers.
+type ChangeListener struct {
+	db       *gorm.DB
+	mu       sync.RWMutex
+	handlers []ChangeHandler
+}
+
+func NewChangeListener(db *gorm.DB) *ChangeListener {
+	return &ChangeListener{db: db}
+}
+
+func (l *ChangeListener) AddHandler(handler ChangeHandler) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.handlers = append(l.handlers, handler)
+}
+
+// Run listens until ctx is cancelled and reconnects with a growing delay if
+// the connection is lost.
+func (l *ChangeListener) Run(ctx context.Context) {
+	const maxDelay = 30 * time.Second
+
+	delay := time.Second
+	for {
+		err := l.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		slog.Error("Listening for translation changes failed", "error", err, "retryIn", delay)
+		l.changesLost()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, maxDelay)
+	}
+}
+
+func (l *ChangeListener) listen(ctx context.Context) error {
+	sqlDB, err := l.db.DB()
+	if err != nil {
+		return err
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		stdlibConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("unsupported database driver %T", driverConn)
+		}
+		pgxConn := stdlibConn.Conn()
+
+		if _, err := pgxConn.Exec(ctx, "LISTEN "+ChangeChannel); err != nil {
+			return err
+		}
+		defer func() {
+			// The connection goes back to the pool, so it must stop listening.
+			if pgxConn.IsClosed() {
+				return
+			}
+			unlistenCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if _, err := pgxConn.Exec(unlistenCtx, "UNLISTEN "+ChangeChannel); err != nil {
+				slog.Error("Unlisten translation changes failed", "error", err)
+			}
+		}()
+
+		// Anything that happened before LISTEN took effect is unknown.
+		l.changesLost()
+		slog.Info("Listening for translation changes", "channel", ChangeChannel)
+
+		for {
+			notification, err := pgxConn.WaitForNotification(ctx)
+			if err != nil {
+				return err
+			}
+
+			change := Change{}
+			if err := json.Unmarshal([]byte(notification.Payload), &change); err != nil {
+				slog.Error("Invalid translation change notification", "payload", notification.Payload, "error", err)
+				l.changesLost()
+				continue
+			}
+
+			l.translationChanged(change)
+		}
+	})
+}
+
+func (l *ChangeListener) translationChanged(change Change) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for _, handler := range l.handlers {
+		handler.TranslationChanged(change)
+	}
+}
+
+func (l *ChangeListener) changesLost() {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for _, handler := range l.handlers {
+		handler.ChangesLost()
+	}
+}
@@ -0,0 +1,168 @@
+package translation
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// localeCodePattern accepts the underscore separated codes stored in the
+// locale table, e.g. "fr", "de_DE", "zh_Hant_TW" or "es_419".
+var localeCodePattern = regexp.MustCompile(`^[a-z]{2,3}(_[A-Z][a-z]{3})?(_([A-Z]{2}|[0-9]{3}))?$`)
+
+// fallbackChainQuery follows the fallback pointers starting at a locale and
+// stops as soon as a locale repeats, so a misconfigured cycle cannot loop.
+const fallbackChainQuery = `
+WITH RECURSIVE chain (code, fallback, enabled, depth, path) AS (
+    SELECT code, fallback, enabled, 0, ARRAY[code]
+    FROM locale
+    WHERE code = ?
+    UNION ALL
+    SELECT l.code, l.fallback, l.enabled, c.depth + 1, c.path || l.code
+    FROM locale AS l
+    INNER JOIN chain AS c ON l.code = c.fallback
+    WHERE NOT l.code = ANY(c.path)
+)
+SELECT code FROM chain WHERE enabled OR ? ORDER BY depth`
+
+type LocaleRegistry interface {
+	ParseLocale(code string) (Locale, error)
+	FallbackChain(locale Locale) ([]Locale, error)
+	GetLocales(includeDisabled bool) ([]LocaleDefinition, error)
+	AddLocale(code, fallback string) (*LocaleDefinition, error)
+	UpdateLocale(code string, update LocaleUpdate) (*LocaleDefinition, error)
+}
+
+type localeRegistry struct {
+	db *gorm.DB
+}
+
+func NewLocaleRegistry(db *gorm.DB) LocaleRegistry {
+	return &localeRegistry{
+		db: db,
+	}
+}
+
+// ParseLocale returns the locale for code if it is registered and enabled.
+func (r localeRegistry) ParseLocale(code string) (Locale, error) {
+	result := LocaleDefinition{}
+
+	err := r.db.Where("code = ? AND enabled", code).First(&result).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedLocale, code)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return result.Code, nil
+}
+
+// FallbackChain returns locale followed by its enabled fallbacks in the order
+// they should be consulted, e.g. de_AT, de_DE, en_GB. Disabled locales are
+// skipped but their own fallbacks are still followed.
+func (r localeRegistry) FallbackChain(locale Locale) ([]Locale, error) {
+	return fallbackChain(r.db, locale, false)
+}
+
+func (r localeRegistry) GetLocales(includeDisabled bool) ([]LocaleDefinition, error) {
+	var result []LocaleDefinition
+
+	query := r.db.Order("code")
+	if !includeDisabled {
+		query = query.Where("enabled")
+	}
+
+	err := query.Find(&result).Error
+
+	return result, err
+}
+
+func (r localeRegistry) AddLocale(code, fallback string) (*LocaleDefinition, error) {
+	if !localeCodePattern.MatchString(code) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidLocale, code)
+	}
+
+	result := LocaleDefinition{Code: Locale(code), Enabled: true}
+	if fallback != "" {
+		fallbackLocale := Locale(fallback)
+		result.Fallback = &fallbackLocale
+	}
+
+	err := r.db.Create(&result).Error
+	if isUniqueViolation(err, localePrimaryKeyConstraint) {
+		return nil, ErrLocaleExists
+	}
+	if isForeignKeyViolation(err, localeFallbackConstraint) {
+		return nil, fmt.Errorf("%w: fallback %q", ErrUnsupportedLocale, fallback)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (r localeRegistry) UpdateLocale(code string, update LocaleUpdate) (*LocaleDefinition, error) {
+	result := LocaleDefinition{}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("code = ?", code).First(&result).Error; err != nil {
+			return err
+		}
+
+		changes := map[string]any{}
+
+		if update.Enabled != nil {
+			changes["enabled"] = *update.Enabled
+		}
+
+		if update.Fallback != nil {
+			if *update.Fallback == "" {
+				changes["fallback"] = nil
+			} else {
+				chain, err := fallbackChain(tx, Locale(*update.Fallback), true)
+				if err != nil {
+					return err
+				}
+				if len(chain) == 0 {
+					return fmt.Errorf("%w: fallback %q", ErrUnsupportedLocale, *update.Fallback)
+				}
+				if slices.Contains(chain, result.Code) {
+					return fmt.Errorf("%w: fallback %q would create a cycle", ErrInvalidLocale, *update.Fallback)
+				}
+				changes["fallback"] = *update.Fallback
+			}
+		}
+
+		if len(changes) == 0 {
+			return nil
+		}
+
+		return tx.Model(&result).Clauses(clause.Returning{}).Updates(changes).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func fallbackChain(db *gorm.DB, locale Locale, includeDisabled bool) ([]Locale, error) {
+	var codes []string
+
+	if err := db.Raw(fallbackChainQuery, locale, includeDisabled).Scan(&codes).Error; err != nil {
+		return nil, err
+	}
+
+	chain := make([]Locale, 0, len(codes))
+	for _, code := range codes {
+		chain = append(chain, Locale(code))
+	}
+
+	return chain, nil
+}
@@ -0,0 +1,628 @@
+package translation
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	// The time zones of LoadTimeZone must not depend on the system.
+	_ "time/tzdata"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// messageNode is a piece of a parsed message: literal text, the "#" of a
+// plural branch or an argument.
+type messageNode struct {
+	text     string
+	pound    bool
+	argument *messageArgument
+}
+
+type messageArgument struct {
+	name string
+	// kind is empty for a simple argument, otherwise one of number, date,
+	// time, plural, selectordinal or select.
+	kind    string
+	style   string
+	offset  float64
+	options []messageOption
+}
+
+type messageOption struct {
+	// selector is a keyword such as "one" or "male", or "=" followed by an
+	// exact value.
+	selector string
+	message  []messageNode
+	// source is the pattern of the branch.
+	source string
+}
+
+// ValidateMessage reports whether pattern is valid ICU MessageFormat.
+func ValidateMessage(pattern string) error {
+	_, err := parseMessage(pattern)
+	return err
+}
+
+// RenderMessage formats pattern as ICU MessageFormat with the CLDR rules of
+// locale. Simple, number, date, time, plural, selectordinal and select
+// arguments are supported, number and date styles may be given as skeletons
+// such as "::percent" or "::yMMMd". Numbers are passed as Go numbers, dates as
+// RFC 3339 strings or milliseconds since the epoch. Dates and times are shown
+// in location, UTC if nil, and in ISO 8601 order for locales missing from
+// dateSymbolsByTag.
+func RenderMessage(locale Locale, pattern string, args map[string]any, location *time.Location) (string, error) {
+	nodes, err := parseMessage(pattern)
+	if err != nil {
+		return "", err
+	}
+
+	tag, err := language.Parse(locale.LanguageTag())
+	if err != nil {
+		return "", fmt.Errorf("%w: %q", ErrInvalidLocale, locale)
+	}
+
+	if location == nil {
+		location = time.UTC
+	}
+
+	r := messageRenderer{tag: tag, printer: message.NewPrinter(tag), args: args, location: location}
+	result := strings.Builder{}
+	if err := r.render(&result, nodes, nil); err != nil {
+		return "", err
+	}
+
+	return result.String(), nil
+}
+
+// LoadTimeZone returns the location of an IANA time zone such as
+// "Europe/Berlin" for RenderMessage.
+func LoadTimeZone(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("%w: unknown time zone %q", ErrInvalidArgument, name)
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown time zone %q", ErrInvalidArgument, name)
+	}
+	return location, nil
+}
+
+type messageParser struct {
+	pattern string
+	pos     int
+}
+
+func parseMessage(pattern string) ([]messageNode, error) {
+	p := messageParser{pattern: pattern}
+
+	nodes, err := p.parseNodes(false, false)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.pattern) {
+		return nil, p.errorf("unmatched }")
+	}
+
+	return nodes, nil
+}
+
+// parsePluralForm parses the text of a plural form, the pattern of a plural
+// branch in which "#" stands for the count.
+func parsePluralForm(form string) ([]messageNode, error) {
+	p := messageParser{pattern: form}
+	return p.parseNodes(false, true)
+}
+
+// quotePluralForm returns the plural form pattern of literal text.
+func quotePluralForm(text string) string {
+	var result strings.Builder
+	for _, r := range text {
+		switch r {
+		case '\'':
+			result.WriteString("''")
+		case '{', '}', '#':
+			result.WriteString("'" + string(r) + "'")
+		default:
+			result.WriteRune(r)
+		}
+	}
+	return result.String()
+}
+
+func (p *messageParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s at offset %d", ErrInvalidMessage, fmt.Sprintf(format, args...), p.pos)
+}
+
+// parseNodes reads a message up to the end of the pattern, or up to the "}"
+// closing a branch if nested is set. inPlural makes "#" a placeholder.
+func (p *messageParser) parseNodes(nested, inPlural bool) ([]messageNode, error) {
+	var nodes []messageNode
+	text := strings.Builder{}
+
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, messageNode{text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for p.pos < len(p.pattern) {
+		c := p.pattern[p.pos]
+
+		switch {
+		case c == '\'':
+			p.parseQuoted(&text, inPlural)
+		case c == '{':
+			flush()
+			argument, err := p.parseArgument(inPlural)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, messageNode{argument: argument})
+		case c == '}':
+			if !nested {
+				return nil, p.errorf("unmatched }")
+			}
+			flush()
+			return nodes, nil
+		case c == '#' && inPlural:
+			flush()
+			nodes = append(nodes, messageNode{pound: true})
+			p.pos++
+		default:
+			text.WriteByte(c)
+			p.pos++
+		}
+	}
+
+	if nested {
+		return nil, p.errorf("unclosed {")
+	}
+
+	flush()
+	return nodes, nil
+}
+
+// parseQuoted handles an apostrophe: two apostrophes are a literal one, an
+// apostrophe before a syntax character starts quoted literal text, any other
+// apostrophe is literal.
+func (p *messageParser) parseQuoted(text *strings.Builder, inPlural bool) {
+	p.pos++
+
+	if p.pos < len(p.pattern) && p.pattern[p.pos] == '\'' {
+		text.WriteByte('\'')
+		p.pos++
+		return
+	}
+
+	if p.pos >= len(p.pattern) || !strings.ContainsRune("{}|", rune(p.pattern[p.pos])) && (!inPlural || p.pattern[p.pos] != '#') {
+		text.WriteByte('\'')
+		return
+	}
+
+	for p.pos < len(p.pattern) {
+		if p.pattern[p.pos] == '\'' {
+			if p.pos+1 < len(p.pattern) && p.pattern[p.pos+1] == '\'' {
+				text.WriteByte('\'')
+				p.pos += 2
+				continue
+			}
+			p.pos++
+			return
+		}
+		text.WriteByte(p.pattern[p.pos])
+		p.pos++
+	}
+}
+
+func (p *messageParser) skipSpace() {
+	for p.pos < len(p.pattern) {
+		r, size := utf8.DecodeRuneInString(p.pattern[p.pos:])
+		if !unicode.IsSpace(r) {
+			return
+		}
+		p.pos += size
+	}
+}
+
+// parseWord reads an identifier, keyword or selector.
+func (p *messageParser) parseWord() string {
+	start := p.pos
+	for p.pos < len(p.pattern) {
+		r, size := utf8.DecodeRuneInString(p.pattern[p.pos:])
+		if unicode.IsSpace(r) || strings.ContainsRune("{},#'", r) {
+			break
+		}
+		p.pos += size
+	}
+	return p.pattern[start:p.pos]
+}
+
+func (p *messageParser) expect(c byte) error {
+	p.skipSpace()
+	if p.pos >= len(p.pattern) || p.pattern[p.pos] != c {
+		return p.errorf("expected %q", c)
+	}
+	p.pos++
+	return nil
+}
+
+// parseArgument reads an argument, inPlural is passed on to the branches of a
+// select so "#" keeps referring to the enclosing plural.
+func (p *messageParser) parseArgument(inPlural bool) (*messageArgument, error) {
+	p.pos++
+	p.skipSpace()
+
+	argument := &messageArgument{name: p.parseWord()}
+	if argument.name == "" {
+		return nil, p.errorf("missing argument name")
+	}
+
+	p.skipSpace()
+	if p.pos < len(p.pattern) && p.pattern[p.pos] == '}' {
+		p.pos++
+		return argument, nil
+	}
+	if err := p.expect(','); err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	argument.kind = p.parseWord()
+
+	switch argument.kind {
+	case "number", "date", "time":
+		p.skipSpace()
+		if p.pos < len(p.pattern) && p.pattern[p.pos] == ',' {
+			p.pos++
+			end := strings.IndexByte(p.pattern[p.pos:], '}')
+			if end < 0 {
+				return nil, p.errorf("unclosed {")
+			}
+			argument.style = strings.TrimSpace(p.pattern[p.pos : p.pos+end])
+			p.pos += end
+		}
+		if err := p.expect('}'); err != nil {
+			return nil, err
+		}
+		return argument, nil
+	case "plural", "selectordinal", "select":
+		if err := p.expect(','); err != nil {
+			return nil, err
+		}
+		if err := p.parseOptions(argument, inPlural || argument.kind != "select"); err != nil {
+			return nil, err
+		}
+		return argument, nil
+	case "":
+		return nil, p.errorf("missing type of argument %q", argument.name)
+	default:
+		return nil, p.errorf("unknown argument type %q", argument.kind)
+	}
+}
+
+func (p *messageParser) parseOptions(argument *messageArgument, inPlural bool) error {
+	p.skipSpace()
+	if argument.kind != "select" && strings.HasPrefix(p.pattern[p.pos:], "offset:") {
+		p.pos += len("offset:")
+		p.skipSpace()
+		start := p.pos
+		offset, err := strconv.ParseFloat(p.parseWord(), 64)
+		if err != nil {
+			p.pos = start
+			return p.errorf("invalid offset")
+		}
+		argument.offset = offset
+	}
+
+	seen := map[string]bool{}
+
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.pattern) {
+			return p.errorf("unclosed {")
+		}
+		if p.pattern[p.pos] == '}' {
+			p.pos++
+			break
+		}
+
+		selector := p.parseWord()
+		if selector == "" {
+			return p.errorf("missing selector")
+		}
+		if err := p.validateSelector(argument.kind, selector); err != nil {
+			return err
+		}
+		if seen[selector] {
+			return p.errorf("duplicate selector %q", selector)
+		}
+		seen[selector] = true
+
+		if err := p.expect('{'); err != nil {
+			return err
+		}
+		start := p.pos
+		nodes, err := p.parseNodes(true, inPlural)
+		if err != nil {
+			return err
+		}
+		source := p.pattern[start:p.pos]
+		p.pos++
+
+		argument.options = append(argument.options, messageOption{selector: selector, message: nodes, source: source})
+	}
+
+	if !seen["other"] {
+		return p.errorf("%s argument %q lacks an other branch", argument.kind, argument.name)
+	}
+
+	return nil
+}
+
+func (p *messageParser) validateSelector(kind, selector string) error {
+	if kind == "select" {
+		return nil
+	}
+
+	if value, ok := strings.CutPrefix(selector, "="); ok {
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return p.errorf("invalid exact selector %q", selector)
+		}
+		return nil
+	}
+
+	for _, category := range pluralCategories {
+		if selector == string(category) {
+			return nil
+		}
+	}
+	return p.errorf("unknown plural category %q", selector)
+}
+
+type messageRenderer struct {
+	tag      language.Tag
+	printer  *message.Printer
+	args     map[string]any
+	location *time.Location
+}
+
+// render writes nodes to result, pound holds the value "#" stands for inside
+// a plural branch.
+func (r messageRenderer) render(result *strings.Builder, nodes []messageNode, pound *float64) error {
+	for _, node := range nodes {
+		switch {
+		case node.argument != nil:
+			if err := r.renderArgument(result, node.argument, pound); err != nil {
+				return err
+			}
+		case node.pound && pound != nil:
+			result.WriteString(r.printer.Sprint(number.Decimal(*pound)))
+		default:
+			result.WriteString(node.text)
+		}
+	}
+	return nil
+}
+
+func (r messageRenderer) renderArgument(result *strings.Builder, argument *messageArgument, pound *float64) error {
+	value, ok := r.args[argument.name]
+	if !ok || value == nil {
+		return fmt.Errorf("%w: missing argument %q", ErrInvalidArgument, argument.name)
+	}
+
+	switch argument.kind {
+	case "":
+		if text, ok := value.(string); ok {
+			result.WriteString(text)
+			return nil
+		}
+		if n, err := numberArgument(argument, value); err == nil {
+			result.WriteString(r.printer.Sprint(number.Decimal(n)))
+			return nil
+		}
+		result.WriteString(fmt.Sprint(value))
+		return nil
+	case "number":
+		n, err := numberArgument(argument, value)
+		if err != nil {
+			return err
+		}
+		formatted, err := r.formatNumber(n, argument.style)
+		if err != nil {
+			return err
+		}
+		result.WriteString(formatted)
+		return nil
+	case "date", "time":
+		t, err := timeArgument(argument, value)
+		if err != nil {
+			return err
+		}
+		formatted, err := formatDateTime(r.tag, t.In(r.location), argument.kind, argument.style)
+		if err != nil {
+			return err
+		}
+		result.WriteString(formatted)
+		return nil
+	case "select":
+		text, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%w: select argument %q must be a string, got %s", ErrInvalidArgument, argument.name, argumentType(value))
+		}
+		return r.render(result, argument.selectOption(text), pound)
+	default:
+		n, err := numberArgument(argument, value)
+		if err != nil {
+			return err
+		}
+
+		rules := plural.Cardinal
+		if argument.kind == "selectordinal" {
+			rules = plural.Ordinal
+		}
+
+		offsetValue := n - argument.offset
+		return r.render(result, argument.pluralOption(n, string(pluralCategory(rules, r.tag, offsetValue))), &offsetValue)
+	}
+}
+
+func (a *messageArgument) selectOption(selector string) []messageNode {
+	var other []messageNode
+	for _, option := range a.options {
+		if option.selector == selector {
+			return option.message
+		}
+		if option.selector == "other" {
+			other = option.message
+		}
+	}
+	return other
+}
+
+// pluralOption picks the branch matching value exactly, then the one of its
+// plural category and finally the other branch.
+func (a *messageArgument) pluralOption(value float64, category string) []messageNode {
+	for _, option := range a.options {
+		if exact, ok := strings.CutPrefix(option.selector, "="); ok {
+			if n, err := strconv.ParseFloat(exact, 64); err == nil && n == value {
+				return option.message
+			}
+		}
+	}
+	return a.selectOption(category)
+}
+
+func (r messageRenderer) formatNumber(n float64, style string) (string, error) {
+	switch style {
+	case "":
+		return r.printer.Sprint(number.Decimal(n)), nil
+	case "integer":
+		return r.printer.Sprint(number.Decimal(n, number.MaxFractionDigits(0))), nil
+	case "percent":
+		return r.printer.Sprint(number.Percent(n)), nil
+	case "currency":
+		unit, _ := currency.FromTag(r.tag)
+		return r.printer.Sprint(currency.Symbol(unit.Amount(n))), nil
+	}
+
+	skeleton, ok := strings.CutPrefix(style, "::")
+	if !ok {
+		return "", fmt.Errorf("%w: unsupported number style %q", ErrInvalidMessage, style)
+	}
+
+	var (
+		options []number.Option
+		percent bool
+		unit    *currency.Unit
+	)
+
+	for _, token := range strings.Fields(skeleton) {
+		switch {
+		case token == "percent" || token == "%":
+			percent = true
+		case token == "integer" || token == "precision-integer":
+			options = append(options, number.MaxFractionDigits(0))
+		case token == "group-off" || token == ",_":
+			options = append(options, number.NoSeparator())
+		case strings.HasPrefix(token, "currency/"):
+			parsed, err := currency.ParseISO(strings.TrimPrefix(token, "currency/"))
+			if err != nil {
+				return "", fmt.Errorf("%w: unknown currency in %q", ErrInvalidMessage, token)
+			}
+			unit = &parsed
+		case strings.HasPrefix(token, "scale/"):
+			scale, err := strconv.ParseFloat(strings.TrimPrefix(token, "scale/"), 64)
+			if err != nil {
+				return "", fmt.Errorf("%w: invalid scale %q", ErrInvalidMessage, token)
+			}
+			n *= scale
+		case strings.HasPrefix(token, "."):
+			digits := strings.TrimPrefix(token, ".")
+			if strings.Trim(digits, "0#") != "" {
+				return "", fmt.Errorf("%w: unsupported precision %q", ErrInvalidMessage, token)
+			}
+			options = append(options,
+				number.MinFractionDigits(strings.Count(digits, "0")),
+				number.MaxFractionDigits(len(digits)))
+		default:
+			return "", fmt.Errorf("%w: unsupported number skeleton token %q", ErrInvalidMessage, token)
+		}
+	}
+
+	switch {
+	case unit != nil:
+		return r.printer.Sprint(currency.Symbol(unit.Amount(n))), nil
+	case percent:
+		return r.printer.Sprint(number.Percent(n, options...)), nil
+	default:
+		return r.printer.Sprint(number.Decimal(n, options...)), nil
+	}
+}
+
+func numberArgument(argument *messageArgument, value any) (float64, error) {
+	switch n := value.(type) {
+	case float64:
+		return n, nil
+	case float32:
+		return float64(n), nil
+	case int:
+		return float64(n), nil
+	case int32:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case json.Number:
+		if f, err := n.Float64(); err == nil {
+			return f, nil
+		}
+	}
+
+	kind := argument.kind
+	if kind == "" {
+		kind = "simple"
+	}
+	return 0, fmt.Errorf("%w: %s argument %q must be a number, got %s", ErrInvalidArgument, kind, argument.name, argumentType(value))
+}
+
+func timeArgument(argument *messageArgument, value any) (time.Time, error) {
+	if text, ok := value.(string); ok {
+		t, err := time.Parse(time.RFC3339, text)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: %s argument %q must be an RFC 3339 timestamp, got %q", ErrInvalidArgument, argument.kind, argument.name, text)
+		}
+		return t, nil
+	}
+
+	millis, err := numberArgument(argument, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s argument %q must be an RFC 3339 timestamp or milliseconds since the epoch, got %s", ErrInvalidArgument, argument.kind, argument.name, argumentType(value))
+	}
+	return time.UnixMilli(int64(millis)).UTC(), nil
+}
+
+// argumentType names the type of a decoded JSON value for error messages.
+func argumentType(value any) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64, float32, int, int32, int64, json.Number:
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
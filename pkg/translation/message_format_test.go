@@ -0,0 +1,248 @@
+package translation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderMessage(t *testing.T) {
+	testCases := map[string]struct {
+		locale       Locale
+		pattern      string
+		args         map[string]any
+		location     string
+		expectedText string
+	}{
+		"plain text": {
+			pattern:      "Hello",
+			expectedText: "Hello",
+		},
+		"simple argument": {
+			pattern:      "Hello {name}",
+			args:         map[string]any{"name": "Ann"},
+			expectedText: "Hello Ann",
+		},
+		"doubled apostrophe": {
+			pattern:      "It''s {name}",
+			args:         map[string]any{"name": "Ann"},
+			expectedText: "It's Ann",
+		},
+		"apostrophe before text is literal": {
+			pattern:      "It's {name}",
+			args:         map[string]any{"name": "Ann"},
+			expectedText: "It's Ann",
+		},
+		"quoted braces": {
+			pattern:      "'{name}' is {name}",
+			args:         map[string]any{"name": "Ann"},
+			expectedText: "{name} is Ann",
+		},
+		"doubled apostrophe within quoted text": {
+			pattern:      "'{it''s}'",
+			expectedText: "{it's}",
+		},
+		"unclosed quote runs to the end": {
+			pattern:      "'{name}",
+			expectedText: "{name}",
+		},
+		"pound outside of plural is literal": {
+			pattern:      "#{name}",
+			args:         map[string]any{"name": "Ann"},
+			expectedText: "#Ann",
+		},
+		"plural with pound": {
+			pattern:      "{count, plural, one {# item} other {# items}}",
+			args:         map[string]any{"count": 1200},
+			expectedText: "1,200 items",
+		},
+		"plural category": {
+			pattern:      "{count, plural, one {# item} other {# items}}",
+			args:         map[string]any{"count": 1},
+			expectedText: "1 item",
+		},
+		"exact selector wins over category": {
+			pattern:      "{count, plural, =1 {a single item} one {# item} other {# items}}",
+			args:         map[string]any{"count": 1},
+			expectedText: "a single item",
+		},
+		"quoted pound in plural": {
+			pattern:      "{count, plural, other {'#'#}}",
+			args:         map[string]any{"count": 3},
+			expectedText: "#3",
+		},
+		"offset shifts pound and category": {
+			pattern:      "{count, plural, offset:1 =0 {nobody} =1 {{host}} one {{host} and # other} other {{host} and # others}}",
+			args:         map[string]any{"count": 2, "host": "Ann"},
+			expectedText: "Ann and 1 other",
+		},
+		"exact selector ignores offset": {
+			pattern:      "{count, plural, offset:1 =0 {nobody} =1 {{host}} one {{host} and # other} other {{host} and # others}}",
+			args:         map[string]any{"count": 1, "host": "Ann"},
+			expectedText: "Ann",
+		},
+		"select nested in plural keeps pound": {
+			pattern:      "{count, plural, one {{gender, select, female {She has # item} other {They have # item}}} other {{gender, select, female {She has # items} other {They have # items}}}}",
+			args:         map[string]any{"count": 1, "gender": "female"},
+			expectedText: "She has 1 item",
+		},
+		"plural nested in select": {
+			pattern:      "{gender, select, male {He has {count, plural, one {# item} other {# items}}} other {They have {count, plural, one {# item} other {# items}}}}",
+			args:         map[string]any{"count": 2, "gender": "male"},
+			expectedText: "He has 2 items",
+		},
+		"select falls back to other": {
+			pattern:      "{gender, select, female {She} other {They}}",
+			args:         map[string]any{"gender": "unknown"},
+			expectedText: "They",
+		},
+		"ordinal": {
+			pattern:      "{place, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}",
+			args:         map[string]any{"place": 23},
+			expectedText: "23rd",
+		},
+		"plural rules of the locale": {
+			locale:       "pl_PL",
+			pattern:      "{count, plural, one {# plik} few {# pliki} many {# plików} other {# pliku}}",
+			args:         map[string]any{"count": 5},
+			expectedText: "5 plików",
+		},
+		"date in UTC": {
+			pattern:      "{day, date, ::MMMMd}",
+			args:         map[string]any{"day": "2025-03-05T23:30:00-02:00"},
+			expectedText: "6 March",
+		},
+		"date in time zone": {
+			pattern:      "{day, date, ::MMMMd} {day, time, short}",
+			args:         map[string]any{"day": "2025-03-05T23:30:00Z"},
+			location:     "Asia/Tokyo",
+			expectedText: "6 March 08:30",
+		},
+		"date in a locale without date symbols": {
+			locale:       "ja_JP",
+			pattern:      "{day, date, long} {day, date, ::MMMMdd} {day, time, medium}",
+			args:         map[string]any{"day": "2025-03-05T12:00:00Z"},
+			expectedText: "2025-03-05 03-05 12:00:00",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			locale := tc.locale
+			if locale == "" {
+				locale = "en_GB"
+			}
+
+			var location *time.Location
+			if tc.location != "" {
+				var err error
+				location, err = LoadTimeZone(tc.location)
+				require.NoError(t, err)
+			}
+
+			text, err := RenderMessage(locale, tc.pattern, tc.args, location)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedText, text)
+		})
+	}
+}
+
+func TestRenderMessageErrors(t *testing.T) {
+	testCases := map[string]struct {
+		locale      Locale
+		pattern     string
+		args        map[string]any
+		expectedErr error
+	}{
+		"unclosed argument": {
+			pattern:     "Hello {name",
+			expectedErr: ErrInvalidMessage,
+		},
+		"unmatched closing brace": {
+			pattern:     "Hello }",
+			expectedErr: ErrInvalidMessage,
+		},
+		"unclosed branch": {
+			pattern:     "{count, plural, other {# items}",
+			expectedErr: ErrInvalidMessage,
+		},
+		"missing other branch": {
+			pattern:     "{count, plural, one {# item}}",
+			expectedErr: ErrInvalidMessage,
+		},
+		"unknown argument type": {
+			pattern:     "{count, choice, other {x}}",
+			expectedErr: ErrInvalidMessage,
+		},
+		"unknown plural category": {
+			pattern:     "{count, plural, several {x} other {y}}",
+			expectedErr: ErrInvalidMessage,
+		},
+		"duplicate selector": {
+			pattern:     "{gender, select, other {x} other {y}}",
+			expectedErr: ErrInvalidMessage,
+		},
+		"invalid offset": {
+			pattern:     "{count, plural, offset:one other {x}}",
+			expectedErr: ErrInvalidMessage,
+		},
+		"invalid exact selector": {
+			pattern:     "{count, plural, =one {x} other {y}}",
+			expectedErr: ErrInvalidMessage,
+		},
+		"missing argument": {
+			pattern:     "Hello {name}",
+			expectedErr: ErrInvalidArgument,
+		},
+		"mistyped plural argument": {
+			pattern:     "{count, plural, other {# items}}",
+			args:        map[string]any{"count": "two"},
+			expectedErr: ErrInvalidArgument,
+		},
+		"mistyped date argument": {
+			pattern:     "{day, date}",
+			args:        map[string]any{"day": "yesterday"},
+			expectedErr: ErrInvalidArgument,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			locale := tc.locale
+			if locale == "" {
+				locale = "en_GB"
+			}
+
+			_, err := RenderMessage(locale, tc.pattern, tc.args, nil)
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestLoadTimeZone(t *testing.T) {
+	location, err := LoadTimeZone("Europe/Berlin")
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", location.String())
+
+	for _, name := range []string{"", "Local", "Mars/Olympus_Mons"} {
+		_, err := LoadTimeZone(name)
+		assert.ErrorIs(t, err, ErrInvalidArgument, name)
+	}
+}
+
+func TestQuotePluralForm(t *testing.T) {
+	for _, text := range []string{"# items", "{count}", "It's '{'", "50% off", "''#''", ""} {
+		nodes, err := parsePluralForm(quotePluralForm(text))
+		require.NoError(t, err, text)
+
+		parsed := ""
+		for _, node := range nodes {
+			assert.False(t, node.pound, text)
+			assert.Nil(t, node.argument, text)
+			parsed += node.text
+		}
+		assert.Equal(t, text, parsed)
+	}
+}
@@ -0,0 +1,196 @@
+package translation
+
+import (
+	"fmt"
+	"regexp"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// namespaceNamePattern accepts names usable as a URL path segment, e.g. "web",
+// "mobile-app" or "email_templates".
+var namespaceNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+type NamespaceRegistry interface {
+	GetNamespaces() ([]Namespace, error)
+	GetNamespace(name string) (*Namespace, error)
+	CreateNamespace(name string, settings NamespaceUpdate) (*Namespace, error)
+	UpdateNamespace(name string, update NamespaceUpdate) (*Namespace, error)
+	// DeleteNamespace removes a namespace without translations or drafts, the
+	// default namespace cannot be removed.
+	DeleteNamespace(name string) error
+}
+
+type namespaceRegistry struct {
+	db *gorm.DB
+}
+
+func NewNamespaceRegistry(db *gorm.DB) NamespaceRegistry {
+	return &namespaceRegistry{
+		db: db,
+	}
+}
+
+func (r namespaceRegistry) GetNamespaces() ([]Namespace, error) {
+	var result []Namespace
+	if err := r.db.Order("name").Find(&result).Error; err != nil {
+		return nil, err
+	}
+
+	var locales []namespaceLocale
+	if err := r.db.Order("namespace, locale").Find(&locales).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range result {
+		for _, locale := range locales {
+			if locale.Namespace == result[i].Name {
+				result[i].Locales = append(result[i].Locales, locale.Locale)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (r namespaceRegistry) GetNamespace(name string) (*Namespace, error) {
+	return getNamespace(r.db, name)
+}
+
+func (r namespaceRegistry) CreateNamespace(name string, settings NamespaceUpdate) (*Namespace, error) {
+	if !namespaceNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidNamespace, name)
+	}
+
+	var result *Namespace
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		namespace := Namespace{Name: name}
+		if err := tx.Create(&namespace).Error; err != nil {
+			return err
+		}
+
+		var err error
+		result, err = updateNamespace(tx, &namespace, settings)
+		return err
+	})
+	if isUniqueViolation(err, namespacePrimaryKeyConstraint) {
+		return nil, ErrNamespaceExists
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r namespaceRegistry) UpdateNamespace(name string, update NamespaceUpdate) (*Namespace, error) {
+	var result *Namespace
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		namespace := Namespace{}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("name = ?", name).Take(&namespace).Error; err != nil {
+			return err
+		}
+
+		var err error
+		result, err = updateNamespace(tx, &namespace, update)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r namespaceRegistry) DeleteNamespace(name string) error {
+	if name == DefaultNamespace {
+		return fmt.Errorf("%w: the default namespace cannot be deleted", ErrInvalidNamespace)
+	}
+
+	deletion := r.db.Where("name = ?", name).Delete(&Namespace{})
+	if isForeignKeyViolation(deletion.Error, translationNamespaceConstraint) ||
+		isForeignKeyViolation(deletion.Error, draftNamespaceConstraint) {
+		return fmt.Errorf("%w: %q", ErrNamespaceNotEmpty, name)
+	}
+	if deletion.Error != nil {
+		return deletion.Error
+	}
+	if deletion.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// updateNamespace applies update to the locked namespace and returns it with
+// its locales.
+func updateNamespace(tx *gorm.DB, namespace *Namespace, update NamespaceUpdate) (*Namespace, error) {
+	changes := map[string]any{}
+
+	if update.Description != nil {
+		changes["description"] = *update.Description
+	}
+	if update.DefaultLocale != nil {
+		if *update.DefaultLocale == "" {
+			changes["default_locale"] = nil
+		} else {
+			changes["default_locale"] = *update.DefaultLocale
+		}
+	}
+
+	if len(changes) > 0 {
+		err := tx.Model(namespace).Clauses(clause.Returning{}).Updates(changes).Error
+		if isForeignKeyViolation(err, namespaceDefaultLocaleConstraint) {
+			return nil, fmt.Errorf("%w: default locale %q", ErrUnsupportedLocale, *update.DefaultLocale)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if update.Locales != nil {
+		if err := tx.Where("namespace = ?", namespace.Name).Delete(&namespaceLocale{}).Error; err != nil {
+			return nil, err
+		}
+
+		for _, code := range *update.Locales {
+			err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&namespaceLocale{Namespace: namespace.Name, Locale: Locale(code)}).Error
+			if isForeignKeyViolation(err, namespaceLocaleConstraint) {
+				return nil, fmt.Errorf("%w: %q", ErrUnsupportedLocale, code)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	result, err := getNamespace(tx, namespace.Name)
+	if err != nil {
+		return nil, err
+	}
+	if defaultLocale := result.EffectiveDefaultLocale(); !result.Enables(defaultLocale) {
+		return nil, fmt.Errorf("%w: default locale %q is not enabled in the namespace", ErrInvalidNamespace, defaultLocale)
+	}
+
+	return result, nil
+}
+
+func getNamespace(db *gorm.DB, name string) (*Namespace, error) {
+	result := Namespace{}
+	if err := db.Where("name = ?", name).Take(&result).Error; err != nil {
+		return nil, err
+	}
+
+	var locales []namespaceLocale
+	if err := db.Where("namespace = ?", name).Order("locale").Find(&locales).Error; err != nil {
+		return nil, err
+	}
+	for _, locale := range locales {
+		result.Locales = append(result.Locales, locale.Locale)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,104 @@
+package translation
+
+import (
+	"slices"
+	"sync"
+)
+
+// CachedNamespaceRegistry is a NamespaceRegistry keeping the settings of every
+// requested namespace in memory, as every read of a translation consults them.
+// Writes go to the wrapped registry and drop the settings of the written
+// namespace. Changes made by other replicas reach the cache through a
+// ChangeListener, see AddHandler.
+type CachedNamespaceRegistry struct {
+	registry NamespaceRegistry
+
+	mu         sync.RWMutex
+	namespaces map[string]*Namespace
+	// generation counts the invalidations, so settings loaded while their
+	// namespace changed are not stored.
+	generation uint64
+}
+
+func NewCachedNamespaceRegistry(registry NamespaceRegistry) *CachedNamespaceRegistry {
+	return &CachedNamespaceRegistry{
+		registry:   registry,
+		namespaces: map[string]*Namespace{},
+	}
+}
+
+func (c *CachedNamespaceRegistry) GetNamespaces() ([]Namespace, error) {
+	return c.registry.GetNamespaces()
+}
+
+// GetNamespace returns a copy of the cached settings, unknown namespaces are
+// not cached.
+func (c *CachedNamespaceRegistry) GetNamespace(name string) (*Namespace, error) {
+	c.mu.RLock()
+	cached, ok := c.namespaces[name]
+	generation := c.generation
+	c.mu.RUnlock()
+
+	if ok {
+		return cloneNamespace(cached), nil
+	}
+
+	loaded, err := c.registry.GetNamespace(name)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.generation == generation {
+		c.namespaces[name] = cloneNamespace(loaded)
+	}
+	c.mu.Unlock()
+
+	return loaded, nil
+}
+
+func (c *CachedNamespaceRegistry) CreateNamespace(name string, settings NamespaceUpdate) (*Namespace, error) {
+	defer c.invalidate(name)
+	return c.registry.CreateNamespace(name, settings)
+}
+
+func (c *CachedNamespaceRegistry) UpdateNamespace(name string, update NamespaceUpdate) (*Namespace, error) {
+	defer c.invalidate(name)
+	return c.registry.UpdateNamespace(name, update)
+}
+
+func (c *CachedNamespaceRegistry) DeleteNamespace(name string) error {
+	defer c.invalidate(name)
+	return c.registry.DeleteNamespace(name)
+}
+
+// TranslationChanged implements ChangeHandler, only changes of the settings
+// of a namespace concern the cache.
+func (c *CachedNamespaceRegistry) TranslationChanged(change Change) {
+	if change.Operation == OperationNamespace {
+		c.invalidate(change.Namespace)
+	}
+}
+
+// ChangesLost implements ChangeHandler.
+func (c *CachedNamespaceRegistry) ChangesLost() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.namespaces = map[string]*Namespace{}
+	c.generation++
+}
+
+func (c *CachedNamespaceRegistry) invalidate(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.namespaces, name)
+	c.generation++
+}
+
+func cloneNamespace(namespace *Namespace) *Namespace {
+	clone := *namespace
+	clone.Locales = slices.Clone(namespace.Locales)
+	return &clone
+}
@@ -0,0 +1,222 @@
+package translation
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type languageRange struct {
+	tag     string
+	quality float64
+}
+
+// NegotiateLocale picks the best supported locale for an Accept-Language
+// header following the lookup scheme of RFC 4647. Ranges are tried by
+// descending quality, each one is matched exactly first and then with its
+// subtags stripped from the right, so "de-CH" is served by "de_DE" if no Swiss
+// locale exists. A "*" range matches the default locale or else the first
+// supported locale, ranges with q=0 exclude matching locales.
+func NegotiateLocale(acceptLanguage string, supported []Locale) (Locale, bool) {
+	ranges := parseAcceptLanguage(acceptLanguage)
+
+	excluded := map[Locale]bool{}
+	for _, r := range ranges {
+		if r.quality == 0 && r.tag != "*" {
+			for _, locale := range supported {
+				if tag := languageTag(locale); tag == r.tag || strings.HasPrefix(tag, r.tag+"-") {
+					excluded[locale] = true
+				}
+			}
+		}
+	}
+
+	candidates := slices.DeleteFunc(slices.Clone(supported), func(locale Locale) bool {
+		return excluded[locale]
+	})
+
+	for _, r := range ranges {
+		if r.quality == 0 {
+			continue
+		}
+
+		if r.tag == "*" {
+			if slices.Contains(candidates, DefaultLocale) {
+				return DefaultLocale, true
+			}
+			if len(candidates) > 0 {
+				return candidates[0], true
+			}
+			continue
+		}
+
+		if locale, ok := lookupLocale(r.tag, candidates); ok {
+			return locale, true
+		}
+	}
+
+	return "", false
+}
+
+// LanguageTag returns the BCP 47 form of a locale, e.g. "de-DE" for de_DE,
+// as used by the Content-Language header.
+func (l Locale) LanguageTag() string {
+	return strings.ReplaceAll(string(l), "_", "-")
+}
+
+func lookupLocale(tag string, candidates []Locale) (Locale, bool) {
+	for {
+		for _, locale := range candidates {
+			if languageTag(locale) == tag {
+				return locale, true
+			}
+		}
+
+		i := strings.LastIndex(tag, "-")
+		if i < 0 {
+			break
+		}
+		tag = tag[:i]
+
+		// A trailing singleton such as the "x" of a private use sequence
+		// cannot stand on its own and is stripped along with its subtag.
+		if j := strings.LastIndex(tag, "-"); j >= 0 && len(tag)-j == 2 {
+			tag = tag[:j]
+		}
+	}
+
+	// Nothing matched down to the primary language subtag, so serve any
+	// region of the same language instead.
+	for _, locale := range candidates {
+		if primary, _, _ := strings.Cut(languageTag(locale), "-"); primary == tag {
+			return locale, true
+		}
+	}
+
+	return "", false
+}
+
+func parseAcceptLanguage(header string) []languageRange {
+	var ranges []languageRange
+
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+
+		quality := 1.0
+		if params != "" {
+			name, value, _ := strings.Cut(strings.TrimSpace(params), "=")
+			if !strings.EqualFold(strings.TrimSpace(name), "q") {
+				continue
+			}
+			q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil || q < 0 || q > 1 {
+				continue
+			}
+			quality = q
+		}
+
+		ranges = append(ranges, languageRange{tag: tag, quality: quality})
+	}
+
+	slices.SortStableFunc(ranges, func(a, b languageRange) int {
+		switch {
+		case a.quality > b.quality:
+			return -1
+		case a.quality < b.quality:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	return ranges
+}
+
+func languageTag(locale Locale) string {
+	return strings.ToLower(locale.LanguageTag())
+}
+
+type mediaRange struct {
+	mediaType string
+	subtype   string
+	quality   float64
+}
+
+// NegotiateMediaType picks the offered media type an Accept header prefers.
+// The most specific range matching an offer, e.g. "text/plain" before
+// "text/*" before "*/*", decides its quality and ties go to the earlier
+// offer. An empty header accepts the first offer, offers with quality 0 are
+// never picked.
+func NegotiateMediaType(accept string, offered []string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		if len(offered) == 0 {
+			return "", false
+		}
+		return offered[0], true
+	}
+
+	ranges := parseAccept(accept)
+
+	best, bestQuality := "", 0.0
+	for _, offer := range offered {
+		mediaType, subtype, _ := strings.Cut(strings.ToLower(offer), "/")
+
+		quality, specificity := 0.0, -1
+		for _, r := range ranges {
+			var matched int
+			switch {
+			case r.mediaType == mediaType && r.subtype == subtype:
+				matched = 2
+			case r.mediaType == mediaType && r.subtype == "*":
+				matched = 1
+			case r.mediaType == "*" && r.subtype == "*":
+				matched = 0
+			default:
+				continue
+			}
+			if matched > specificity {
+				quality, specificity = r.quality, matched
+			}
+		}
+
+		if quality > bestQuality {
+			best, bestQuality = offer, quality
+		}
+	}
+
+	return best, bestQuality > 0
+}
+
+func parseAccept(header string) []mediaRange {
+	var ranges []mediaRange
+
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		mediaType, subtype, ok := strings.Cut(strings.ToLower(strings.TrimSpace(params[0])), "/")
+		if !ok || mediaType == "" || subtype == "" {
+			continue
+		}
+
+		quality, valid := 1.0, true
+		for _, param := range params[1:] {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if !strings.EqualFold(strings.TrimSpace(name), "q") {
+				continue
+			}
+			q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			valid = err == nil && q >= 0 && q <= 1
+			quality = q
+		}
+		if !valid {
+			continue
+		}
+
+		ranges = append(ranges, mediaRange{mediaType: mediaType, subtype: subtype, quality: quality})
+	}
+
+	return ranges
+}
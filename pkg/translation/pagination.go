@@ -0,0 +1,210 @@
+package translation
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	OrderByKey       = "key"
+	OrderByUpdatedAt = "updated_at"
+
+	// DefaultPageSize is the page size of listings that do not ask for one.
+	DefaultPageSize = 100
+	// MaxPageSize is the largest page size clients may request.
+	MaxPageSize = 1000
+	// MaxBatchSize caps the number of keys of a batch lookup.
+	MaxBatchSize = 1000
+)
+
+// ListOptions controls ListTranslations. OrderBy is either "key" (default) or
+// "updated_at", optionally followed by "asc" or "desc". A PageSize of zero
+// means DefaultPageSize.
+type ListOptions struct {
+	KeyPrefix string
+	OrderBy   string
+	PageSize  int
+	PageToken string
+}
+
+type TranslationPage struct {
+	Translations []Translation
+	// NextPageToken is empty on the last page.
+	NextPageToken string
+}
+
+type sortOrder struct {
+	field      string
+	descending bool
+}
+
+func parseOrderBy(value string) (sortOrder, error) {
+	fields := strings.Fields(strings.ToLower(value))
+	if len(fields) == 0 {
+		return sortOrder{field: OrderByKey}, nil
+	}
+
+	order := sortOrder{field: fields[0]}
+	if order.field != OrderByKey && order.field != OrderByUpdatedAt {
+		return sortOrder{}, fmt.Errorf("%w: cannot order by %q", ErrInvalidListOptions, fields[0])
+	}
+
+	switch {
+	case len(fields) == 1, len(fields) == 2 && fields[1] == "asc":
+	case len(fields) == 2 && fields[1] == "desc":
+		order.descending = true
+	default:
+		return sortOrder{}, fmt.Errorf("%w: invalid order %q", ErrInvalidListOptions, value)
+	}
+
+	return order, nil
+}
+
+// pageToken is the opaque cursor handed to clients. It records the position
+// after the last returned row and the query it belongs to, so a token cannot
+// be replayed against a different ordering or filter.
+type pageToken struct {
+	OrderBy    string    `json:"o"`
+	Descending bool      `json:"d,omitempty"`
+	KeyPrefix  string    `json:"p,omitempty"`
+	Key        string    `json:"k"`
+	UpdatedAt  time.Time `json:"u"`
+}
+
+func (p pageToken) encode() string {
+	data, _ := json.Marshal(p)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePageToken(value string, order sortOrder, keyPrefix string) (*pageToken, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed page token", ErrInvalidListOptions)
+	}
+
+	token := pageToken{}
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("%w: malformed page token", ErrInvalidListOptions)
+	}
+
+	if token.OrderBy != order.field || token.Descending != order.descending || token.KeyPrefix != keyPrefix {
+		return nil, fmt.Errorf("%w: page token does not match the query", ErrInvalidListOptions)
+	}
+
+	return &token, nil
+}
+
+// listQuery is the validated form of ListOptions.
+type listQuery struct {
+	order     sortOrder
+	keyPrefix string
+	pageSize  int
+	cursor    *pageToken
+}
+
+func parseListOptions(options ListOptions) (listQuery, error) {
+	order, err := parseOrderBy(options.OrderBy)
+	if err != nil {
+		return listQuery{}, err
+	}
+	if options.PageSize < 0 {
+		return listQuery{}, fmt.Errorf("%w: negative page size", ErrInvalidListOptions)
+	}
+	if options.PageSize > MaxPageSize {
+		return listQuery{}, fmt.Errorf("%w: page size exceeds %d", ErrInvalidListOptions, MaxPageSize)
+	}
+
+	query := listQuery{
+		order:     order,
+		keyPrefix: options.KeyPrefix,
+		pageSize:  options.PageSize,
+	}
+	if query.pageSize == 0 {
+		query.pageSize = DefaultPageSize
+	}
+
+	if options.PageToken != "" {
+		if query.cursor, err = decodePageToken(options.PageToken, order, options.KeyPrefix); err != nil {
+			return listQuery{}, err
+		}
+	}
+
+	return query, nil
+}
+
+// page turns the rows following the cursor, fetched with a limit of one more
+// than the page size, into a page. The extra row only signals that another
+// page exists.
+func (q listQuery) page(translations []Translation) *TranslationPage {
+	page := &TranslationPage{Translations: translations}
+
+	if len(translations) > q.pageSize {
+		page.Translations = translations[:q.pageSize]
+		last := page.Translations[q.pageSize-1]
+		page.NextPageToken = pageToken{
+			OrderBy:    q.order.field,
+			Descending: q.order.descending,
+			KeyPrefix:  q.keyPrefix,
+			Key:        last.LanguageKey,
+			UpdatedAt:  last.UpdatedAt,
+		}.encode()
+	}
+
+	return page
+}
+
+// apply evaluates the query in memory on translations with unique keys, the
+// equivalent of the SQL built by repository.ListTranslations.
+func (q listQuery) apply(translations []Translation) *TranslationPage {
+	result := make([]Translation, 0, len(translations))
+	for _, entity := range translations {
+		if !strings.HasPrefix(entity.LanguageKey, q.keyPrefix) {
+			continue
+		}
+		if q.cursor != nil && !q.after(entity, q.cursor.UpdatedAt, q.cursor.Key) {
+			continue
+		}
+		result = append(result, entity)
+	}
+
+	slices.SortFunc(result, func(a, b Translation) int {
+		switch {
+		case q.after(a, b.UpdatedAt, b.LanguageKey):
+			return 1
+		case q.after(b, a.UpdatedAt, a.LanguageKey):
+			return -1
+		default:
+			return 0
+		}
+	})
+
+	if len(result) > q.pageSize+1 {
+		result = result[:q.pageSize+1]
+	}
+
+	return q.page(result)
+}
+
+// after reports whether entity sorts after the position given by updatedAt
+// and key in the order of the query.
+func (q listQuery) after(entity Translation, updatedAt time.Time, key string) bool {
+	c := 0
+	if q.order.field == OrderByUpdatedAt {
+		c = entity.UpdatedAt.Compare(updatedAt)
+	}
+	if c == 0 {
+		c = strings.Compare(entity.LanguageKey, key)
+	}
+	if q.order.descending {
+		c = -c
+	}
+	return c > 0
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
@@ -0,0 +1,247 @@
+package translation
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// PluralCategory is a CLDR plural category. Which categories a locale
+// distinguishes is defined by its CLDR plural rules, every locale uses
+// PluralOther.
+type PluralCategory string
+
+const (
+	PluralZero  PluralCategory = "zero"
+	PluralOne   PluralCategory = "one"
+	PluralTwo   PluralCategory = "two"
+	PluralFew   PluralCategory = "few"
+	PluralMany  PluralCategory = "many"
+	PluralOther PluralCategory = "other"
+)
+
+// pluralCategories names the CLDR plural categories by plural.Form.
+var pluralCategories = map[plural.Form]PluralCategory{
+	plural.Zero:  PluralZero,
+	plural.One:   PluralOne,
+	plural.Two:   PluralTwo,
+	plural.Few:   PluralFew,
+	plural.Many:  PluralMany,
+	plural.Other: PluralOther,
+}
+
+// pluralCategoryOrder lists the categories in CLDR order.
+var pluralCategoryOrder = []PluralCategory{PluralZero, PluralOne, PluralTwo, PluralFew, PluralMany, PluralOther}
+
+// countPattern accepts the plain decimal notation of a non-negative number,
+// e.g. "3" or "1.50".
+var countPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+// PluralForms holds the variants of a pluralised translation by category. It
+// is stored as a JSON object, NULL if empty.
+type PluralForms map[PluralCategory]string
+
+func (p PluralForms) Value() (driver.Value, error) {
+	if len(p) == 0 {
+		return nil, nil
+	}
+
+	value, err := json.Marshal(map[PluralCategory]string(p))
+	if err != nil {
+		return nil, err
+	}
+
+	return string(value), nil
+}
+
+func (p *PluralForms) Scan(src any) error {
+	var data []byte
+
+	switch value := src.(type) {
+	case nil:
+		*p = nil
+		return nil
+	case []byte:
+		data = value
+	case string:
+		data = []byte(value)
+	default:
+		return fmt.Errorf("cannot scan %T into plural forms", src)
+	}
+
+	return json.Unmarshal(data, (*map[PluralCategory]string)(p))
+}
+
+// Categories returns the categories of the forms in CLDR order.
+func (p PluralForms) Categories() []PluralCategory {
+	var result []PluralCategory
+	for _, category := range pluralCategoryOrder {
+		if _, ok := p[category]; ok {
+			result = append(result, category)
+		}
+	}
+	return result
+}
+
+// pluralCategoryCache holds the categories by language.Tag, deriving them
+// takes some 20000 rule evaluations.
+var pluralCategoryCache sync.Map
+
+// PluralCategories returns the categories the CLDR cardinal plural rules of
+// locale distinguish, in CLDR order.
+func PluralCategories(locale Locale) ([]PluralCategory, error) {
+	tag, err := language.Parse(locale.LanguageTag())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidLocale, locale)
+	}
+
+	if cached, ok := pluralCategoryCache.Load(tag); ok {
+		return slices.Clone(cached.([]PluralCategory)), nil
+	}
+
+	result := matchPluralCategories(tag)
+	pluralCategoryCache.Store(tag, result)
+
+	return slices.Clone(result), nil
+}
+
+// matchPluralCategories samples the plural rules of tag for the categories
+// they produce.
+func matchPluralCategories(tag language.Tag) []PluralCategory {
+	// The rules only look at the last digits of the integer part and at most
+	// two fraction digits, which the samples cover.
+	seen := map[PluralCategory]bool{}
+	for i := 0; i <= 1000; i++ {
+		seen[pluralCategories[plural.Cardinal.MatchPlural(tag, i, 0, 0, 0, 0)]] = true
+		seen[pluralCategories[plural.Cardinal.MatchPlural(tag, i*1000, 0, 0, 0, 0)]] = true
+	}
+	for i := 0; i <= 100; i++ {
+		for f := 0; f <= 99; f++ {
+			seen[pluralCategories[plural.Cardinal.MatchPlural(tag, i, 1, 1, f%10, f%10)]] = true
+			seen[pluralCategories[plural.Cardinal.MatchPlural(tag, i, 2, 2, f, f)]] = true
+		}
+	}
+
+	var result []PluralCategory
+	for _, category := range pluralCategoryOrder {
+		if seen[category] {
+			result = append(result, category)
+		}
+	}
+	return result
+}
+
+// validatePluralForms reports whether forms holds a non-empty text for every
+// category locale distinguishes and no other categories. Every text must be
+// the pattern of an ICU MessageFormat plural branch, "#" standing for the
+// count and literal "{", "}", "#" and "'" quoted.
+func validatePluralForms(locale Locale, forms PluralForms) error {
+	required, err := PluralCategories(locale)
+	if err != nil {
+		return err
+	}
+
+	for category, text := range forms {
+		if !slices.Contains(required, category) {
+			return fmt.Errorf("%w: plural category %q is not used by %s, expected %s", ErrInvalidTranslation, category, locale, joinCategories(required))
+		}
+		if text == "" {
+			return fmt.Errorf("%w: plural form %q is empty", ErrInvalidTranslation, category)
+		}
+		if _, err := parsePluralForm(text); err != nil {
+			return fmt.Errorf("%w: plural form %q: %v", ErrInvalidTranslation, category, err)
+		}
+	}
+
+	var missing []PluralCategory
+	for _, category := range required {
+		if _, ok := forms[category]; !ok {
+			missing = append(missing, category)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s requires the plural forms %s, missing %s", ErrInvalidTranslation, locale, joinCategories(required), joinCategories(missing))
+	}
+
+	return nil
+}
+
+func joinCategories(categories []PluralCategory) string {
+	names := make([]string, len(categories))
+	for i, category := range categories {
+		names[i] = strconv.Quote(string(category))
+	}
+	return strings.Join(names, ", ")
+}
+
+// pluralText returns the text stored alongside forms, an empty text defaults
+// to the PluralOther form.
+func pluralText(text string, forms PluralForms) string {
+	if text == "" {
+		return forms[PluralOther]
+	}
+	return text
+}
+
+// PluralForm returns the text of the translation for count, a non-negative
+// decimal such as "3" or "1.5". Visible fraction digits matter, in English
+// "1" selects the "one" form and "1.0" the "other" form. Translations without
+// plural forms return their text for any count.
+func (t Translation) PluralForm(count string) (string, error) {
+	if !countPattern.MatchString(count) {
+		return "", fmt.Errorf("%w: %q is no non-negative decimal", ErrInvalidCount, count)
+	}
+	if len(t.PluralForms) == 0 {
+		return t.Translation, nil
+	}
+
+	tag, err := language.Parse(t.Locale.LanguageTag())
+	if err != nil {
+		return "", fmt.Errorf("%w: %q", ErrInvalidLocale, t.Locale)
+	}
+
+	if text, ok := t.PluralForms[pluralCategoryOf(plural.Cardinal, tag, count)]; ok {
+		return text, nil
+	}
+	return t.PluralForms[PluralOther], nil
+}
+
+// pluralCategoryOf returns the CLDR plural category of a decimal in plain
+// notation, computing the plural operands from its digits.
+func pluralCategoryOf(rules *plural.Rules, tag language.Tag, decimal string) PluralCategory {
+	integer, fraction, _ := strings.Cut(decimal, ".")
+
+	i, _ := strconv.Atoi(lastDigits(integer))
+
+	v := len(fraction)
+	f, _ := strconv.Atoi(lastDigits(fraction))
+	trimmed := strings.TrimRight(fraction, "0")
+	w := len(trimmed)
+	t, _ := strconv.Atoi(lastDigits(trimmed))
+
+	return pluralCategories[rules.MatchPlural(tag, i, v, w, f, t)]
+}
+
+// pluralCategory returns the CLDR plural category of n, computing the plural
+// operands from its shortest decimal representation.
+func pluralCategory(rules *plural.Rules, tag language.Tag, n float64) PluralCategory {
+	return pluralCategoryOf(rules, tag, strconv.FormatFloat(math.Abs(n), 'f', -1, 64))
+}
+
+// lastDigits cuts digits to fit an int, the rules only distinguish the last
+// digits of an operand.
+func lastDigits(digits string) string {
+	if len(digits) > 9 {
+		return digits[len(digits)-9:]
+	}
+	return digits
+}
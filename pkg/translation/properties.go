@@ -0,0 +1,68 @@
+package translation
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+)
+
+// WriteProperties writes translations as Java properties file for resource
+// bundles. Characters outside of printable ASCII are written as Unicode
+// escapes, so the file reads the same as ISO-8859-1 and as UTF-8. Translator
+// comments become comments, pluralised translations a plural message of a
+// count argument for ICU4J.
+func WriteProperties(w io.Writer, translations []Translation) error {
+	out := bufio.NewWriter(w)
+
+	for _, entity := range translations {
+		if entity.Comment != "" {
+			for _, line := range strings.Split(entity.Comment, "\n") {
+				out.WriteString("# " + propertiesEscape(line, false, true) + "\n")
+			}
+		}
+		out.WriteString(propertiesEscape(entity.LanguageKey, true, false))
+		out.WriteString("=")
+		out.WriteString(propertiesEscape(bundleMessage(entity), false, false))
+		out.WriteString("\n")
+	}
+
+	return out.Flush()
+}
+
+// propertiesEscape escapes a key, a value or, with comment set, the line of a
+// comment, which only needs the Unicode escapes.
+func propertiesEscape(text string, key, comment bool) string {
+	var result strings.Builder
+	for i, r := range text {
+		switch {
+		case r > 0x7e || (r < 0x20 && comment):
+			for _, unit := range utf16.Encode([]rune{r}) {
+				fmt.Fprintf(&result, `\u%04x`, unit)
+			}
+		case comment:
+			result.WriteRune(r)
+		case r == '\\':
+			result.WriteString(`\\`)
+		case r == '\n':
+			result.WriteString(`\n`)
+		case r == '\r':
+			result.WriteString(`\r`)
+		case r == '\t':
+			result.WriteString(`\t`)
+		case r == '\f':
+			result.WriteString(`\f`)
+		case r < 0x20:
+			fmt.Fprintf(&result, `\u%04x`, r)
+		case r == ' ' && (key || i == 0):
+			result.WriteString(`\ `)
+		case key && (r == '=' || r == ':' || ((r == '#' || r == '!') && i == 0)):
+			result.WriteByte('\\')
+			result.WriteRune(r)
+		default:
+			result.WriteRune(r)
+		}
+	}
+	return result.String()
+}
@@ -0,0 +1,159 @@
+package translation
+
+import (
+	"fmt"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// LatestRelease refers to the release the latest tag points to.
+const LatestRelease = "latest"
+
+// ReleaseStore manages immutable snapshots of all translations. Releases are
+// referred to by their number or LatestRelease.
+type ReleaseStore interface {
+	CreateRelease(description string) (*Release, error)
+	ListReleases() ([]Release, error)
+	GetRelease(ref string) (*Release, error)
+	// RollbackRelease moves the latest tag to the release.
+	RollbackRelease(ref string) (*Release, error)
+	// ReleaseRepository serves the translations of the release, writes fail
+	// with ErrReleaseImmutable.
+	ReleaseRepository(ref string) (Repository, error)
+}
+
+type releaseStore struct {
+	db *gorm.DB
+}
+
+func NewReleaseStore(db *gorm.DB) ReleaseStore {
+	return &releaseStore{
+		db: db,
+	}
+}
+
+// CreateRelease copies the translation table into a new release and tags it
+// as latest. The copy is a single statement, so it sees a consistent state.
+func (r releaseStore) CreateRelease(description string) (*Release, error) {
+	release := Release{Description: description}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&release).Error; err != nil {
+			return err
+		}
+
+		err := tx.Exec(`INSERT INTO release_translation (release_id, id, namespace, language_key, locale, translation, plural_forms, comment, fuzzy, created_at, updated_at)
+SELECT ?, id, namespace, language_key, locale, translation, plural_forms, comment, fuzzy, created_at, updated_at FROM translation`, release.ID).Error
+		if err != nil {
+			return err
+		}
+
+		// Concurrent releases must not leave the tag on the older one.
+		return tx.Exec(`INSERT INTO release_tag (name, release_id) VALUES (?, ?)
+ON CONFLICT (name) DO UPDATE SET release_id = EXCLUDED.release_id, updated_at = NOW()
+WHERE release_tag.release_id < EXCLUDED.release_id`, LatestRelease, release.ID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetRelease(strconv.Itoa(release.ID))
+}
+
+func (r releaseStore) ListReleases() ([]Release, error) {
+	var result []Release
+
+	err := r.releases().Order("release.id DESC").Find(&result).Error
+
+	return result, err
+}
+
+func (r releaseStore) GetRelease(ref string) (*Release, error) {
+	id, err := r.resolve(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	result := Release{}
+	err = r.releases().Where("release.id = ?", id).Take(&result).Error
+
+	return &result, err
+}
+
+func (r releaseStore) RollbackRelease(ref string) (*Release, error) {
+	id, err := r.resolve(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Exec(`INSERT INTO release_tag (name, release_id) VALUES (?, ?)
+ON CONFLICT (name) DO UPDATE SET release_id = EXCLUDED.release_id, updated_at = NOW()`, LatestRelease, id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetRelease(strconv.Itoa(id))
+}
+
+func (r releaseStore) ReleaseRepository(ref string) (Repository, error) {
+	id, err := r.resolve(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := r.db.Table("release_translation").
+		Select("id, namespace, language_key, locale, translation, plural_forms, comment, fuzzy, created_at, updated_at").
+		Where("release_id = ?", id)
+
+	return releaseRepository{
+		repository: repository{db: r.db.Table("(?) AS translation", rows).Session(&gorm.Session{}), namespace: DefaultNamespace},
+	}, nil
+}
+
+// resolve returns the number of an existing release.
+func (r releaseStore) resolve(ref string) (int, error) {
+	var release Release
+
+	if ref == LatestRelease {
+		err := r.db.Table("release_tag").Select("release_id AS id").Where("name = ?", LatestRelease).Take(&release).Error
+		return release.ID, err
+	}
+
+	id, err := strconv.Atoi(ref)
+	if err != nil || id < 1 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidRelease, ref)
+	}
+
+	err = r.db.Select("id").Where("id = ?", id).Take(&release).Error
+
+	return release.ID, err
+}
+
+func (r releaseStore) releases() *gorm.DB {
+	return r.db.Model(&Release{}).
+		Select("release.*, release_tag.release_id IS NOT NULL AS latest").
+		Joins("LEFT JOIN release_tag ON release_tag.release_id = release.id AND release_tag.name = ?", LatestRelease)
+}
+
+// releaseRepository runs the queries of repository against the translations
+// of a release.
+type releaseRepository struct {
+	repository
+}
+
+func (r releaseRepository) InNamespace(namespace string) Repository {
+	return releaseRepository{repository: r.inNamespace(namespace)}
+}
+
+func (releaseRepository) CreateTranslation(*Translation, string) error {
+	return ErrReleaseImmutable
+}
+
+func (releaseRepository) UpdateTranslation(string, Locale, string, PluralForms, string) (*Translation, error) {
+	return nil, ErrReleaseImmutable
+}
+
+func (releaseRepository) DeleteTranslation(string, Locale, string) error {
+	return ErrReleaseImmutable
+}
@@ -1,34 +1,345 @@
 package translation
 
-import "gorm.io/gorm"
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
 
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Repository reads accept a list of locales in priority order, typically a
+// fallback chain from LocaleRegistry.FallbackChain. The first locale holding a
+// key serves it. Writes record the author in the revision history, an empty
+// author stays anonymous. Writes with plural forms validate them against the
+// CLDR plural rules of the locale, an empty text defaults to the PluralOther
+// form and a write without plural forms removes them. Updates clear the fuzzy
+// flag. A Repository serves the translations of one namespace,
+// DefaultNamespace unless InNamespace is used.
 type Repository interface {
-	GetTranslationByKey(key string, locale Locale) (*Translation, error)
-	GetTranslations(locale Locale) ([]Translation, error)
+	InNamespace(namespace string) Repository
+	GetTranslationByKey(key string, locales ...Locale) (*Translation, error)
+	GetTranslations(locales ...Locale) ([]Translation, error)
+	ListTranslations(options ListOptions, locales ...Locale) (*TranslationPage, error)
+	GetTranslationsByKeys(keys []string, locales ...Locale) ([]Translation, error)
+	CreateTranslation(translation *Translation, author string) error
+	UpdateTranslation(key string, locale Locale, text string, forms PluralForms, author string) (*Translation, error)
+	DeleteTranslation(key string, locale Locale, author string) error
 }
 
 type repository struct {
-	db *gorm.DB
+	db        *gorm.DB
+	namespace string
 }
 
 func NewRepository(db *gorm.DB) Repository {
 	return &repository{
-		db: db,
+		db:        db,
+		namespace: DefaultNamespace,
 	}
 }
 
-func (t repository) GetTranslationByKey(key string, locale Locale) (*Translation, error) {
+func (t repository) InNamespace(namespace string) Repository {
+	return t.inNamespace(namespace)
+}
+
+func (t repository) inNamespace(namespace string) repository {
+	t.namespace = namespace
+	return t
+}
+
+// scoped returns a query on the translations of the namespace.
+func (t repository) scoped() *gorm.DB {
+	return t.db.Where("namespace = ?", t.namespace)
+}
+
+func (t repository) GetTranslationByKey(key string, locales ...Locale) (*Translation, error) {
 	result := Translation{}
 
-	err := t.db.Where("language_key = ? AND locale = ?", key, locale).First(&result).Error
+	if len(locales) == 0 {
+		return &result, gorm.ErrRecordNotFound
+	}
+
+	priority, vars := localePriority(locales)
+
+	err := t.scoped().Where("language_key = ? AND locale IN ?", key, locales).
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: priority, Vars: vars}}).
+		Take(&result).Error
 
 	return &result, err
 }
 
-func (t repository) GetTranslations(locale Locale) ([]Translation, error) {
+func (t repository) GetTranslations(locales ...Locale) ([]Translation, error) {
+	var result []Translation
+
+	if len(locales) == 0 {
+		return result, nil
+	}
+
+	err := resolvedTranslations(t.scoped(), locales).Find(&result).Error
+
+	return result, err
+}
+
+func (t repository) ListTranslations(options ListOptions, locales ...Locale) (*TranslationPage, error) {
+	query, err := parseListOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(locales) == 0 {
+		return &TranslationPage{}, nil
+	}
+
+	resolved := resolvedTranslations(t.scoped(), locales)
+	if query.keyPrefix != "" {
+		resolved = resolved.Where(`language_key LIKE ? ESCAPE '\'`, escapeLike(query.keyPrefix)+"%")
+	}
+
+	direction, comparison := "ASC", ">"
+	if query.order.descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	// language_key is unique after resolving the fallbacks and breaks ties, so
+	// the ordering is total and a cursor always points between two rows. Keys
+	// are compared bytewise to match the ordering of CachedRepository.
+	db := t.db.Table("(?) AS translation", resolved)
+	if query.order.field == OrderByUpdatedAt {
+		db = db.Order(`updated_at ` + direction + `, language_key COLLATE "C" ` + direction)
+		if query.cursor != nil {
+			db = db.Where(`(updated_at, language_key COLLATE "C") `+comparison+` (?, ?)`, query.cursor.UpdatedAt, query.cursor.Key)
+		}
+	} else {
+		db = db.Order(`language_key COLLATE "C" ` + direction)
+		if query.cursor != nil {
+			db = db.Where(`language_key COLLATE "C" `+comparison+` ?`, query.cursor.Key)
+		}
+	}
+
+	db = db.Limit(query.pageSize + 1)
+
+	var result []Translation
+	if err := db.Find(&result).Error; err != nil {
+		return nil, err
+	}
+
+	return query.page(result), nil
+}
+
+func (t repository) GetTranslationsByKeys(keys []string, locales ...Locale) ([]Translation, error) {
 	var result []Translation
 
-	err := t.db.Where("locale = ?", locale).Find(&result).Error
+	if err := validateBatchKeys(keys); err != nil {
+		return nil, err
+	}
+	if len(locales) == 0 {
+		return result, nil
+	}
+
+	err := resolvedTranslations(t.scoped(), locales).Where("language_key IN ?", keys).Find(&result).Error
 
 	return result, err
 }
+
+func (t repository) CreateTranslation(translation *Translation, author string) error {
+	translation.Translation = pluralText(translation.Translation, translation.PluralForms)
+	if err := translation.Validate(); err != nil {
+		return err
+	}
+	translation.Namespace = t.namespace
+
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		if err := setAuthor(tx, author); err != nil {
+			return err
+		}
+		return tx.Create(translation).Error
+	})
+	if isUniqueViolation(err, uniqueTranslationConstraint) {
+		return ErrTranslationExists
+	}
+	if isForeignKeyViolation(err, translationLocaleConstraint) {
+		return fmt.Errorf("%w: %q", ErrUnsupportedLocale, translation.Locale)
+	}
+	if isForeignKeyViolation(err, translationNamespaceConstraint) {
+		return fmt.Errorf("%w: %q", ErrUnknownNamespace, translation.Namespace)
+	}
+
+	return err
+}
+
+func (t repository) UpdateTranslation(key string, locale Locale, text string, forms PluralForms, author string) (*Translation, error) {
+	text = pluralText(text, forms)
+	if err := (Translation{LanguageKey: key, Locale: locale, Translation: text, PluralForms: forms}).Validate(); err != nil {
+		return nil, err
+	}
+
+	result := Translation{}
+
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		if err := setAuthor(tx, author); err != nil {
+			return err
+		}
+
+		update := tx.Model(&result).
+			Clauses(clause.Returning{}).
+			Where("namespace = ? AND language_key = ? AND locale = ?", t.namespace, key, locale).
+			Updates(map[string]any{"translation": text, "plural_forms": forms, "fuzzy": false})
+		if update.Error != nil {
+			return update.Error
+		}
+		if update.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (t repository) DeleteTranslation(key string, locale Locale, author string) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		if err := setAuthor(tx, author); err != nil {
+			return err
+		}
+
+		deletion := tx.Where("namespace = ? AND language_key = ? AND locale = ?", t.namespace, key, locale).Delete(&Translation{})
+		if deletion.Error != nil {
+			return deletion.Error
+		}
+		if deletion.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return nil
+	})
+}
+
+// setAuthor passes the author of the changes made in tx to the trigger
+// recording the revisions.
+func setAuthor(tx *gorm.DB, author string) error {
+	if author == "" {
+		return nil
+	}
+	return tx.Exec("SELECT set_config('translation.changed_by', ?, true)", author).Error
+}
+
+// writeTranslation updates the translation of key through repo, or creates it
+// if there is none yet.
+func writeTranslation(repo Repository, key string, locale Locale, text string, forms PluralForms, author string) (*Translation, error) {
+	result, err := repo.UpdateTranslation(key, locale, text, forms, author)
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return result, err
+	}
+
+	result = &Translation{
+		LanguageKey: key,
+		Locale:      locale,
+		Translation: text,
+		PluralForms: forms,
+	}
+	if err := repo.CreateTranslation(result, author); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// TranslationPatch holds the fields of a partial update, fields left empty
+// keep their stored value.
+type TranslationPatch struct {
+	Translation *string
+	PluralForms PluralForms
+}
+
+// PatchTranslation applies patch to the translation of key through repo. The
+// plural forms are merged by category and a new text replaces the PluralOther
+// form of a pluralised translation, so a patch never removes plural forms.
+func PatchTranslation(repo Repository, key string, locale Locale, patch TranslationPatch, author string) (*Translation, error) {
+	current, err := repo.GetTranslationByKey(key, locale)
+	if err != nil {
+		return nil, err
+	}
+	if patch.Translation == nil && len(patch.PluralForms) == 0 {
+		return current, nil
+	}
+
+	forms := maps.Clone(current.PluralForms)
+	if forms == nil && len(patch.PluralForms) > 0 {
+		forms = PluralForms{}
+	}
+	maps.Copy(forms, patch.PluralForms)
+
+	text := ""
+	if patch.Translation != nil {
+		text = *patch.Translation
+		if _, ok := patch.PluralForms[PluralOther]; !ok && len(forms) > 0 {
+			forms[PluralOther] = text
+		}
+	}
+
+	return repo.UpdateTranslation(key, locale, text, forms, author)
+}
+
+func validateBatchKeys(keys []string) error {
+	if len(keys) == 0 {
+		return fmt.Errorf("%w: at least one key is required", ErrInvalidListOptions)
+	}
+	if len(keys) > MaxBatchSize {
+		return fmt.Errorf("%w: at most %d keys are allowed", ErrInvalidListOptions, MaxBatchSize)
+	}
+
+	return nil
+}
+
+// resolvedTranslations selects one translation per key, taken from the first
+// of locales that holds the key.
+func resolvedTranslations(db *gorm.DB, locales []Locale) *gorm.DB {
+	priority, vars := localePriority(locales)
+
+	return db.Model(&Translation{}).
+		Select("DISTINCT ON (language_key) *").
+		Where("locale IN ?", locales).
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: "language_key, " + priority, Vars: vars}})
+}
+
+// localePriority builds an ORDER BY expression ranking rows by the position of
+// their locale in locales.
+func localePriority(locales []Locale) (string, []any) {
+	var sql strings.Builder
+	vars := make([]any, 0, 2*len(locales))
+
+	sql.WriteString("CASE locale")
+	for i, locale := range locales {
+		sql.WriteString(" WHEN ? THEN ?")
+		vars = append(vars, locale, i)
+	}
+	sql.WriteString(" END")
+
+	return sql.String(), vars
+}
+
+// MissingKeys returns the keys, in request order and without duplicates, for
+// which found holds no translation.
+func MissingKeys(keys []string, found []Translation) []string {
+	present := make(map[string]bool, len(found))
+	for _, entity := range found {
+		present[entity.LanguageKey] = true
+	}
+
+	missing := []string{}
+	for _, key := range keys {
+		if !present[key] {
+			missing = append(missing, key)
+			present[key] = true
+		}
+	}
+
+	return missing
+}
@@ -0,0 +1,64 @@
+package translation
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// RevisionStore reads the revision history the translation table triggers
+// write. A RevisionStore serves the revisions of one namespace,
+// DefaultNamespace unless InNamespace is used.
+type RevisionStore interface {
+	InNamespace(namespace string) RevisionStore
+	// ListRevisions returns the revisions of a translation, newest first.
+	ListRevisions(key string, locale Locale) ([]Revision, error)
+	GetRevision(id int64) (*Revision, error)
+}
+
+type revisionStore struct {
+	db        *gorm.DB
+	namespace string
+}
+
+func NewRevisionStore(db *gorm.DB) RevisionStore {
+	return &revisionStore{
+		db:        db,
+		namespace: DefaultNamespace,
+	}
+}
+
+func (r revisionStore) InNamespace(namespace string) RevisionStore {
+	r.namespace = namespace
+	return r
+}
+
+func (r revisionStore) ListRevisions(key string, locale Locale) ([]Revision, error) {
+	var result []Revision
+
+	err := r.db.Where("namespace = ? AND language_key = ? AND locale = ?", r.namespace, key, locale).Order("id DESC").Find(&result).Error
+
+	return result, err
+}
+
+func (r revisionStore) GetRevision(id int64) (*Revision, error) {
+	result := Revision{}
+
+	err := r.db.Where("namespace = ? AND id = ?", r.namespace, id).Take(&result).Error
+
+	return &result, err
+}
+
+// RestoreRevision writes the value of a revision of key through repo, which
+// records the restore as a new revision. Deletions cannot be restored, the
+// translation has to be deleted instead.
+func RestoreRevision(repo Repository, revision *Revision, key, author string) (*Translation, error) {
+	if revision.LanguageKey != key {
+		return nil, fmt.Errorf("%w: revision %d belongs to another key", ErrInvalidRevision, revision.ID)
+	}
+	if revision.Translation == nil {
+		return nil, fmt.Errorf("%w: revision %d deletes the translation", ErrInvalidRevision, revision.ID)
+	}
+
+	return writeTranslation(repo, revision.LanguageKey, revision.Locale, *revision.Translation, revision.PluralForms, author)
+}
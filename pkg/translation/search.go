@@ -0,0 +1,226 @@
+package translation
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultSearchPageSize is the page size of searches that do not ask for
+	// one.
+	DefaultSearchPageSize = 20
+
+	maxSearchQueryLength = 256
+)
+
+// ts_headline wraps matched words in these control characters, which are
+// turned into mark elements once the snippet is escaped.
+const (
+	snippetStart = "\x02"
+	snippetStop  = "\x03"
+)
+
+// headlineOptions configures the snippets of ts_headline, up to two fragments
+// of the translation around the matched words.
+const headlineOptions = `StartSel="` + snippetStart + `", StopSel="` + snippetStop + `", MaxWords=20, MinWords=5, MaxFragments=2, FragmentDelimiter=" … "`
+
+// SearchOptions controls SearchTranslations. Query is a web search query as
+// understood by websearch_to_tsquery: words, "quoted phrases", or and -word
+// to exclude a word. An empty Locale searches every locale enabled in the
+// namespace.
+type SearchOptions struct {
+	Query     string
+	Locale    Locale
+	PageSize  int
+	PageToken string
+}
+
+// SearchResult is a translation matching a search. Snippet holds the matched
+// parts of the translation as HTML, escaped and with the matched words in
+// mark elements.
+type SearchResult struct {
+	LanguageKey string
+	Locale      Locale
+	Translation string
+	PluralForms PluralForms
+	Snippet     string
+	Score       float64
+}
+
+type SearchPage struct {
+	Results []SearchResult
+	// NextPageToken is empty on the last page.
+	NextPageToken string
+}
+
+// SearchStore searches the translations of a namespace. Translations match if
+// their text, the PluralOther form of pluralised translations, contains the
+// words of the query, stemmed by the text search configuration of the
+// language of their locale. Keys match if they contain the query or are
+// similar to it by trigrams, which tolerates typos. Results are ordered by
+// relevance, pages are taken by offset and may shift while translations
+// change.
+type SearchStore interface {
+	SearchTranslations(namespace string, options SearchOptions) (*SearchPage, error)
+}
+
+type searchStore struct {
+	db *gorm.DB
+}
+
+func NewSearchStore(db *gorm.DB) SearchStore {
+	return &searchStore{
+		db: db,
+	}
+}
+
+// searchLocaleQuery searches the translations of a single locale. The text
+// is matched against a query parsed with the configuration of the locale, so
+// the expression index on the search vector applies.
+const searchLocaleQuery = `SELECT language_key, locale, translation, plural_forms,
+    ts_headline(translation_search_config(locale), translation, query, ?) AS snippet,
+    ts_rank_cd(to_tsvector(translation_search_config(locale), translation), query) + word_similarity(?, language_key) AS score
+FROM translation, websearch_to_tsquery(translation_search_config(?), ?) AS query
+WHERE namespace = ? AND locale = ?
+    AND (to_tsvector(translation_search_config(locale), translation) @@ query OR ? <% language_key OR language_key ILIKE ? ESCAPE '\')`
+
+func (s searchStore) SearchTranslations(namespace string, options SearchOptions) (*SearchPage, error) {
+	query, err := parseSearchOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := getNamespace(s.db, namespace)
+	if err != nil {
+		return nil, err
+	}
+	locales, err := namespaceLocales(s.db, settings)
+	if err != nil {
+		return nil, err
+	}
+	if query.locale != "" {
+		if !slices.Contains(locales, query.locale) {
+			return nil, fmt.Errorf("%w: %q", ErrUnsupportedLocale, query.locale)
+		}
+		locales = []Locale{query.locale}
+	}
+	if len(locales) == 0 {
+		return &SearchPage{}, nil
+	}
+
+	subqueries := make([]string, 0, len(locales))
+	var vars []any
+	for _, locale := range locales {
+		subqueries = append(subqueries, searchLocaleQuery)
+		vars = append(vars, headlineOptions, query.text, locale, query.text, namespace, locale, query.text, "%"+escapeLike(query.text)+"%")
+	}
+
+	// Keys are compared bytewise, like the other orderings of the service, and
+	// break ties of the score together with the locale, so pages do not
+	// overlap.
+	sql := `SELECT * FROM (` + strings.Join(subqueries, "\nUNION ALL\n") + `) AS result
+ORDER BY score DESC, language_key COLLATE "C", locale COLLATE "C"
+LIMIT ? OFFSET ?`
+	vars = append(vars, query.pageSize+1, query.offset)
+
+	var results []SearchResult
+	if err := s.db.Raw(sql, vars...).Scan(&results).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range results {
+		results[i].Snippet = markSnippet(results[i].Snippet)
+	}
+
+	return query.page(results), nil
+}
+
+// markSnippet escapes a headline and turns its delimiters into mark elements.
+func markSnippet(headline string) string {
+	return strings.NewReplacer(snippetStart, "<mark>", snippetStop, "</mark>").Replace(html.EscapeString(headline))
+}
+
+// searchToken is the opaque cursor of search pages, the offset of the next
+// page and the search it belongs to.
+type searchToken struct {
+	Query  string `json:"q"`
+	Locale Locale `json:"l,omitempty"`
+	Offset int    `json:"n"`
+}
+
+func (p searchToken) encode() string {
+	data, _ := json.Marshal(p)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// searchQuery is the validated form of SearchOptions.
+type searchQuery struct {
+	text     string
+	locale   Locale
+	pageSize int
+	offset   int
+}
+
+func parseSearchOptions(options SearchOptions) (searchQuery, error) {
+	query := searchQuery{
+		text:     strings.TrimSpace(options.Query),
+		locale:   options.Locale,
+		pageSize: options.PageSize,
+	}
+
+	switch {
+	case query.text == "":
+		return searchQuery{}, fmt.Errorf("%w: query is required", ErrInvalidSearch)
+	case utf8.RuneCountInString(query.text) > maxSearchQueryLength:
+		return searchQuery{}, fmt.Errorf("%w: query is longer than %d characters", ErrInvalidSearch, maxSearchQueryLength)
+	case options.PageSize < 0:
+		return searchQuery{}, fmt.Errorf("%w: negative page size", ErrInvalidSearch)
+	case options.PageSize > MaxPageSize:
+		return searchQuery{}, fmt.Errorf("%w: page size exceeds %d", ErrInvalidSearch, MaxPageSize)
+	case options.PageSize == 0:
+		query.pageSize = DefaultSearchPageSize
+	}
+
+	if options.PageToken != "" {
+		data, err := base64.RawURLEncoding.DecodeString(options.PageToken)
+		if err != nil {
+			return searchQuery{}, fmt.Errorf("%w: malformed page token", ErrInvalidSearch)
+		}
+
+		token := searchToken{}
+		if err := json.Unmarshal(data, &token); err != nil || token.Offset < 0 {
+			return searchQuery{}, fmt.Errorf("%w: malformed page token", ErrInvalidSearch)
+		}
+		if token.Query != query.text || token.Locale != query.locale {
+			return searchQuery{}, fmt.Errorf("%w: page token does not match the search", ErrInvalidSearch)
+		}
+
+		query.offset = token.Offset
+	}
+
+	return query, nil
+}
+
+// page turns the results at the offset, fetched with a limit of one more than
+// the page size, into a page.
+func (q searchQuery) page(results []SearchResult) *SearchPage {
+	page := &SearchPage{Results: results}
+
+	if len(results) > q.pageSize {
+		page.Results = results[:q.pageSize]
+		page.NextPageToken = searchToken{
+			Query:  q.text,
+			Locale: q.locale,
+			Offset: q.offset + q.pageSize,
+		}.encode()
+	}
+
+	return page
+}
@@ -0,0 +1,560 @@
+package translation
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// XLIFF versions supported by ParseXLIFF and WriteXLIFF.
+const (
+	XLIFF12 = "1.2"
+	XLIFF20 = "2.0"
+)
+
+const (
+	xliff12Namespace = "urn:oasis:names:tc:xliff:document:1.2"
+	xliff20Namespace = "urn:oasis:names:tc:xliff:document:2.0"
+	// xliffExtensionNamespace holds the attributes the service adds to the
+	// documents it exports, prefixed with "ts".
+	xliffExtensionNamespace = "urn:translation-service:xliff"
+
+	// xliff12Plurals and xliff20Plurals mark the groups holding a unit per
+	// plural category.
+	xliff12Plurals = "x-gettext-plurals"
+	xliff20Plurals = "ts:plurals"
+
+	// xliffContext marks the 2.0 notes and 1.2 contexts holding the gettext
+	// context of a key.
+	xliffContext = "x-gettext-msgctxt"
+
+	maxXLIFFSize = 32 << 20
+)
+
+// xliff12Fuzzy lists the 1.2 target states of translations awaiting work.
+var xliff12Fuzzy = []string{
+	"new", "needs-translation", "needs-adaptation", "needs-l10n",
+	"needs-review-translation", "needs-review-adaptation", "needs-review-l10n",
+}
+
+// XLIFFDocument is an XLIFF file translating one namespace from a source to a
+// target locale. Locales are BCP 47 language tags as in the file.
+type XLIFFDocument struct {
+	Version      string
+	Namespace    string
+	SourceLocale string
+	TargetLocale string
+	Units        []XLIFFUnit
+}
+
+// XLIFFUnit is the translation of a key. Plural units hold a text per plural
+// category of the target locale in SourceForms and TargetForms instead of
+// Source and Target. Target is nil for units never translated. Baseline is the
+// fingerprint of the target when the document was exported, empty if unknown.
+type XLIFFUnit struct {
+	Key         string
+	Source      string
+	Target      *string
+	SourceForms PluralForms
+	TargetForms PluralForms
+	Notes       []string
+	Fuzzy       bool
+	Baseline    string
+}
+
+// Plural reports whether the unit holds plural forms.
+func (u XLIFFUnit) Plural() bool {
+	return u.SourceForms != nil || u.TargetForms != nil
+}
+
+// xliffID encodes a key as unit id. Percent signs and control characters,
+// such as GettextContextSeparator, cannot appear in XML attributes and are
+// percent-encoded.
+func xliffID(key string) string {
+	var result strings.Builder
+	for i := 0; i < len(key); i++ {
+		if c := key[i]; c == '%' || c < 0x20 || c == 0x7f {
+			fmt.Fprintf(&result, "%%%02X", c)
+		} else {
+			result.WriteByte(c)
+		}
+	}
+	return result.String()
+}
+
+func xliffKey(id string) (string, error) {
+	key, err := url.PathUnescape(id)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid unit id %q", ErrInvalidXLIFF, id)
+	}
+	return key, nil
+}
+
+type xliff12Document struct {
+	Version string        `xml:"version,attr"`
+	Files   []xliff12File `xml:"file"`
+}
+
+type xliff12File struct {
+	Original       string         `xml:"original,attr"`
+	SourceLanguage string         `xml:"source-language,attr"`
+	TargetLanguage string         `xml:"target-language,attr"`
+	Units          []xliff12Unit  `xml:"body>trans-unit"`
+	Groups         []xliff12Group `xml:"body>group"`
+}
+
+type xliff12Group struct {
+	ID       string         `xml:"id,attr"`
+	Restype  string         `xml:"restype,attr"`
+	Baseline string         `xml:"urn:translation-service:xliff baseline,attr"`
+	Units    []xliff12Unit  `xml:"trans-unit"`
+	Groups   []xliff12Group `xml:"group"`
+}
+
+type xliff12Unit struct {
+	ID       string         `xml:"id,attr"`
+	Resname  string         `xml:"resname,attr"`
+	Baseline string         `xml:"urn:translation-service:xliff baseline,attr"`
+	Source   string         `xml:"source"`
+	Target   *xliff12Target `xml:"target"`
+	Notes    []string       `xml:"note"`
+}
+
+type xliff12Target struct {
+	State string `xml:"state,attr"`
+	Text  string `xml:",chardata"`
+}
+
+type xliff20Document struct {
+	Version string        `xml:"version,attr"`
+	SrcLang string        `xml:"srcLang,attr"`
+	TrgLang string        `xml:"trgLang,attr"`
+	Files   []xliff20File `xml:"file"`
+}
+
+type xliff20File struct {
+	ID       string         `xml:"id,attr"`
+	Original string         `xml:"original,attr"`
+	Units    []xliff20Unit  `xml:"unit"`
+	Groups   []xliff20Group `xml:"group"`
+}
+
+type xliff20Group struct {
+	ID       string         `xml:"id,attr"`
+	Type     string         `xml:"type,attr"`
+	Baseline string         `xml:"urn:translation-service:xliff baseline,attr"`
+	Units    []xliff20Unit  `xml:"unit"`
+	Groups   []xliff20Group `xml:"group"`
+}
+
+type xliff20Unit struct {
+	ID       string           `xml:"id,attr"`
+	Name     string           `xml:"name,attr"`
+	Baseline string           `xml:"urn:translation-service:xliff baseline,attr"`
+	Notes    []xliff20Note    `xml:"notes>note"`
+	Segments []xliff20Segment `xml:"segment"`
+}
+
+type xliff20Note struct {
+	Category string `xml:"category,attr"`
+	Text     string `xml:",chardata"`
+}
+
+type xliff20Segment struct {
+	State  string  `xml:"state,attr"`
+	Source string  `xml:"source"`
+	Target *string `xml:"target"`
+}
+
+// ParseXLIFF reads an XLIFF 1.2 or 2.0 document. Inline markup is dropped,
+// only the text of sources, targets and notes is kept.
+func ParseXLIFF(r io.Reader) (*XLIFFDocument, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxXLIFFSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxXLIFFSize {
+		return nil, fmt.Errorf("%w: larger than %d bytes", ErrInvalidXLIFF, maxXLIFFSize)
+	}
+
+	var root struct {
+		XMLName xml.Name
+		Version string `xml:"version,attr"`
+	}
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidXLIFF, err)
+	}
+	if root.XMLName.Local != "xliff" {
+		return nil, fmt.Errorf("%w: root element %q instead of xliff", ErrInvalidXLIFF, root.XMLName.Local)
+	}
+
+	switch root.Version {
+	case XLIFF12:
+		var document xliff12Document
+		if err := xml.Unmarshal(data, &document); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidXLIFF, err)
+		}
+		return document.model()
+	case XLIFF20:
+		var document xliff20Document
+		if err := xml.Unmarshal(data, &document); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidXLIFF, err)
+		}
+		return document.model()
+	default:
+		return nil, fmt.Errorf("%w: unsupported version %q", ErrInvalidXLIFF, root.Version)
+	}
+}
+
+func (d xliff12Document) model() (*XLIFFDocument, error) {
+	result := &XLIFFDocument{Version: XLIFF12}
+
+	for i, file := range d.Files {
+		if i == 0 {
+			result.Namespace = file.Original
+			result.SourceLocale = file.SourceLanguage
+			result.TargetLocale = file.TargetLanguage
+		}
+		if file.TargetLanguage != result.TargetLocale {
+			return nil, fmt.Errorf("%w: files of several target languages", ErrInvalidXLIFF)
+		}
+
+		group := xliff12Group{Units: file.Units, Groups: file.Groups}
+		if err := group.collect(result); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// collect appends the units of the group followed by those of its nested
+// groups, plural groups become a single unit.
+func (g xliff12Group) collect(document *XLIFFDocument) error {
+	for _, unit := range g.Units {
+		key, err := xliffKey(unit.ID)
+		if err != nil {
+			return err
+		}
+
+		result := XLIFFUnit{Key: key, Source: unit.Source, Notes: unit.Notes, Baseline: unit.Baseline}
+		if unit.Target != nil {
+			result.Target = &unit.Target.Text
+			result.Fuzzy = slices.Contains(xliff12Fuzzy, unit.Target.State)
+		}
+		document.Units = append(document.Units, result)
+	}
+
+	for _, group := range g.Groups {
+		if group.Restype != xliff12Plurals {
+			if err := group.collect(document); err != nil {
+				return err
+			}
+			continue
+		}
+
+		key, err := xliffKey(group.ID)
+		if err != nil {
+			return err
+		}
+
+		result := XLIFFUnit{Key: key, SourceForms: PluralForms{}, TargetForms: PluralForms{}, Baseline: group.Baseline}
+		for _, unit := range group.Units {
+			category, err := xliffPluralCategory(group.ID, unit.ID, unit.Resname)
+			if err != nil {
+				return err
+			}
+			result.SourceForms[category] = unit.Source
+			if unit.Target != nil {
+				result.TargetForms[category] = unit.Target.Text
+				result.Fuzzy = result.Fuzzy || slices.Contains(xliff12Fuzzy, unit.Target.State)
+			}
+			result.Notes = append(result.Notes, unit.Notes...)
+		}
+		document.Units = append(document.Units, result)
+	}
+
+	return nil
+}
+
+func (d xliff20Document) model() (*XLIFFDocument, error) {
+	result := &XLIFFDocument{Version: XLIFF20, SourceLocale: d.SrcLang, TargetLocale: d.TrgLang}
+
+	for i, file := range d.Files {
+		if i == 0 {
+			result.Namespace = file.Original
+		}
+
+		group := xliff20Group{Units: file.Units, Groups: file.Groups}
+		if err := group.collect(result); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func (g xliff20Group) collect(document *XLIFFDocument) error {
+	for _, unit := range g.Units {
+		key, err := xliffKey(unit.ID)
+		if err != nil {
+			return err
+		}
+
+		source, target, fuzzy := unit.text()
+		document.Units = append(document.Units, XLIFFUnit{
+			Key:      key,
+			Source:   source,
+			Target:   target,
+			Notes:    unit.notes(),
+			Fuzzy:    fuzzy,
+			Baseline: unit.Baseline,
+		})
+	}
+
+	for _, group := range g.Groups {
+		if group.Type != xliff20Plurals {
+			if err := group.collect(document); err != nil {
+				return err
+			}
+			continue
+		}
+
+		key, err := xliffKey(group.ID)
+		if err != nil {
+			return err
+		}
+
+		result := XLIFFUnit{Key: key, SourceForms: PluralForms{}, TargetForms: PluralForms{}, Baseline: group.Baseline}
+		for _, unit := range group.Units {
+			category, err := xliffPluralCategory(group.ID, unit.ID, unit.Name)
+			if err != nil {
+				return err
+			}
+
+			source, target, fuzzy := unit.text()
+			result.SourceForms[category] = source
+			if target != nil {
+				result.TargetForms[category] = *target
+			}
+			result.Fuzzy = result.Fuzzy || fuzzy
+			result.Notes = append(result.Notes, unit.notes()...)
+		}
+		document.Units = append(document.Units, result)
+	}
+
+	return nil
+}
+
+// text joins the segments of the unit. A missing state counts as translated,
+// the "initial" state marks the target as fuzzy.
+func (u xliff20Unit) text() (source string, target *string, fuzzy bool) {
+	var targetText strings.Builder
+	translated := false
+	for _, segment := range u.Segments {
+		source += segment.Source
+		if segment.Target != nil {
+			translated = true
+			targetText.WriteString(*segment.Target)
+			fuzzy = fuzzy || segment.State == "initial"
+		}
+	}
+	if !translated {
+		return source, nil, false
+	}
+
+	text := targetText.String()
+	return source, &text, fuzzy
+}
+
+// notes returns the notes of the unit without the gettext context.
+func (u xliff20Unit) notes() []string {
+	var result []string
+	for _, note := range u.Notes {
+		if note.Category != xliffContext {
+			result = append(result, note.Text)
+		}
+	}
+	return result
+}
+
+// xliffPluralCategory returns the plural category of a unit of a plural group,
+// named by the unit or encoded in its id as key[category].
+func xliffPluralCategory(groupID, unitID, name string) (PluralCategory, error) {
+	if name == "" {
+		name = strings.TrimSuffix(strings.TrimPrefix(unitID, groupID+"["), "]")
+	}
+	if !slices.Contains(pluralCategoryOrder, PluralCategory(name)) {
+		return "", fmt.Errorf("%w: unit %q of plural group %q has no plural category", ErrInvalidXLIFF, unitID, groupID)
+	}
+	return PluralCategory(name), nil
+}
+
+// WriteXLIFF writes the document in its version. Fuzzy targets get the state
+// needs-review-translation in 1.2 and initial in 2.0, the gettext context of
+// a key is written as context in 1.2 and as note in 2.0.
+func WriteXLIFF(w io.Writer, document *XLIFFDocument) error {
+	out := xliffWriter{Writer: bufio.NewWriter(w)}
+
+	out.WriteString(xml.Header)
+	switch document.Version {
+	case XLIFF12:
+		out.writeXLIFF12(document)
+	case XLIFF20:
+		out.writeXLIFF20(document)
+	default:
+		return fmt.Errorf("%w: unsupported version %q", ErrInvalidXLIFF, document.Version)
+	}
+
+	return out.Flush()
+}
+
+type xliffWriter struct {
+	*bufio.Writer
+}
+
+// line writes an indented line, formatting the arguments with xmlEscape.
+func (w xliffWriter) line(depth int, format string, args ...any) {
+	for i, arg := range args {
+		if text, ok := arg.(string); ok {
+			args[i] = xmlEscape(text)
+		}
+	}
+	w.WriteString(strings.Repeat("  ", depth))
+	fmt.Fprintf(w, format, args...)
+	w.WriteString("\n")
+}
+
+func (w xliffWriter) writeXLIFF12(document *XLIFFDocument) {
+	w.line(0, `<xliff version="1.2" xmlns="%s" xmlns:ts="%s">`, xliff12Namespace, xliffExtensionNamespace)
+	w.line(1, `<file original="%s" source-language="%s" target-language="%s" datatype="plaintext">`, document.Namespace, document.SourceLocale, document.TargetLocale)
+	w.line(2, `<body>`)
+
+	for _, unit := range document.Units {
+		id := xliffID(unit.Key)
+		context, name, hasContext := strings.Cut(unit.Key, GettextContextSeparator)
+		if !hasContext {
+			name = unit.Key
+		}
+
+		state := "translated"
+		if unit.Fuzzy {
+			state = "needs-review-translation"
+		}
+
+		writeUnit := func(depth int, id, resname, baseline, source string, target *string) {
+			if baseline != "" {
+				w.line(depth, `<trans-unit id="%s" resname="%s" ts:baseline="%s">`, id, resname, baseline)
+			} else {
+				w.line(depth, `<trans-unit id="%s" resname="%s">`, id, resname)
+			}
+			w.line(depth+1, `<source>%s</source>`, source)
+			if target != nil {
+				w.line(depth+1, `<target state="%s">%s</target>`, state, *target)
+			}
+			if hasContext {
+				w.line(depth+1, `<context-group purpose="information">`)
+				w.line(depth+2, `<context context-type="%s">%s</context>`, xliffContext, context)
+				w.line(depth+1, `</context-group>`)
+			}
+			for _, note := range unit.Notes {
+				w.line(depth+1, `<note>%s</note>`, note)
+			}
+			w.line(depth, `</trans-unit>`)
+		}
+
+		if !unit.Plural() {
+			writeUnit(3, id, name, unit.Baseline, unit.Source, unit.Target)
+			continue
+		}
+
+		w.line(3, `<group id="%s" resname="%s" restype="%s" ts:baseline="%s">`, id, name, xliff12Plurals, unit.Baseline)
+		for _, category := range unit.TargetForms.Categories() {
+			target := unit.TargetForms[category]
+			writeUnit(4, id+"["+string(category)+"]", string(category), "", unit.SourceForms[category], xliffPluralTarget(target))
+		}
+		w.line(3, `</group>`)
+	}
+
+	w.line(2, `</body>`)
+	w.line(1, `</file>`)
+	w.line(0, `</xliff>`)
+}
+
+func (w xliffWriter) writeXLIFF20(document *XLIFFDocument) {
+	w.line(0, `<xliff version="2.0" xmlns="%s" xmlns:ts="%s" srcLang="%s" trgLang="%s">`, xliff20Namespace, xliffExtensionNamespace, document.SourceLocale, document.TargetLocale)
+	w.line(1, `<file id="%s" original="%s">`, xliffID(document.Namespace), document.Namespace)
+
+	for _, unit := range document.Units {
+		id := xliffID(unit.Key)
+		context, name, hasContext := strings.Cut(unit.Key, GettextContextSeparator)
+		if !hasContext {
+			name = unit.Key
+		}
+
+		writeUnit := func(depth int, id, name, baseline, source string, target *string) {
+			if baseline != "" {
+				w.line(depth, `<unit id="%s" name="%s" ts:baseline="%s">`, id, name, baseline)
+			} else {
+				w.line(depth, `<unit id="%s" name="%s">`, id, name)
+			}
+			if hasContext || len(unit.Notes) > 0 {
+				w.line(depth+1, `<notes>`)
+				if hasContext {
+					w.line(depth+2, `<note category="%s">%s</note>`, xliffContext, context)
+				}
+				for _, note := range unit.Notes {
+					w.line(depth+2, `<note>%s</note>`, note)
+				}
+				w.line(depth+1, `</notes>`)
+			}
+
+			state := "translated"
+			if target == nil || unit.Fuzzy {
+				state = "initial"
+			}
+			w.line(depth+1, `<segment state="%s">`, state)
+			w.line(depth+2, `<source>%s</source>`, source)
+			if target != nil {
+				w.line(depth+2, `<target>%s</target>`, *target)
+			}
+			w.line(depth+1, `</segment>`)
+			w.line(depth, `</unit>`)
+		}
+
+		if !unit.Plural() {
+			writeUnit(2, id, name, unit.Baseline, unit.Source, unit.Target)
+			continue
+		}
+
+		w.line(2, `<group id="%s" name="%s" type="%s" ts:baseline="%s">`, id, name, xliff20Plurals, unit.Baseline)
+		for _, category := range unit.TargetForms.Categories() {
+			target := unit.TargetForms[category]
+			writeUnit(3, id+"["+string(category)+"]", string(category), "", unit.SourceForms[category], xliffPluralTarget(target))
+		}
+		w.line(2, `</group>`)
+	}
+
+	w.line(1, `</file>`)
+	w.line(0, `</xliff>`)
+}
+
+// xliffPluralTarget returns the target of a plural form, nil if untranslated.
+func xliffPluralTarget(text string) *string {
+	if text == "" {
+		return nil
+	}
+	return &text
+}
+
+// xmlEscape escapes text for element content and attribute values.
+func xmlEscape(text string) string {
+	var result bytes.Buffer
+	_ = xml.EscapeText(&result, []byte(text))
+	return result.String()
+}